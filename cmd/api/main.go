@@ -7,10 +7,10 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
-	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
 	"github.com/JeanGrijp/AutoBanca/internal/api"
 	"github.com/JeanGrijp/AutoBanca/internal/handlers"
 	"github.com/JeanGrijp/AutoBanca/internal/service"
@@ -39,17 +39,36 @@ func main() {
 	}
 	defer pool.Close()
 
-	// Inicializa o Querier do sqlc
-	queries := db.New(pool)
 	slog.InfoContext(ctx, "Database connection established")
 
+	// Número de reportes abertos que suspende uma questão da geração de provas
+	suspendAfter := service.DefaultReportSuspensionThreshold
+	if v := os.Getenv("REPORT_SUSPENSION_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("Invalid REPORT_SUSPENSION_THRESHOLD: %v", err)
+		}
+		suspendAfter = n
+	}
+
 	// Inicializa os Services e Handlers (arquitetura simplificada)
-	subjectService := service.NewSubjectService(queries)
-	topicService := service.NewTopicService(queries)
-	choiceService := service.NewChoiceService(queries)
-	questionService := service.NewQuestionService(queries)
+	subjectService := service.NewSubjectService(pool)
+	topicService := service.NewTopicService(pool)
+	choiceService := service.NewChoiceService(pool)
+	questionService := service.NewQuestionService(pool)
 	importService := service.NewImportService(pool)
-	examService := service.NewExamService(queries, subjectService, topicService, questionService)
+	examService := service.NewExamService(pool)
+	reviewService := service.NewReviewService(pool)
+	revisionService := service.NewRevisionService(pool)
+	trashService := service.NewTrashService(pool)
+	duplicateService := service.NewDuplicateService(pool)
+	boardService := service.NewBoardService(pool)
+	taxonomyService := service.NewTaxonomyService(pool)
+	legalReferenceService := service.NewLegalReferenceService(pool)
+	statisticsService := service.NewStatisticsService(pool)
+	irtService := service.NewIRTService(pool)
+	reportService := service.NewReportService(pool, suspendAfter)
+	importJobService := service.NewImportJobService(pool, importService)
 
 	slog.InfoContext(ctx, "Initializing handlers")
 
@@ -57,18 +76,44 @@ func main() {
 	choiceHandler := handlers.NewChoiceHandler(choiceService)
 	questionHandler := handlers.NewQuestionHandler(questionService, choiceService, importService)
 	examHandler := handlers.NewExamHandler(examService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	boardHandler := handlers.NewBoardHandler(boardService)
+	taxonomyHandler := handlers.NewTaxonomyHandler(taxonomyService)
+	legalReferenceHandler := handlers.NewLegalReferenceHandler(legalReferenceService)
+	statisticsHandler := handlers.NewStatisticsHandler(statisticsService)
+	irtHandler := handlers.NewIRTHandler(irtService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	importJobHandler := handlers.NewImportJobHandler(importJobService)
 
 	subjectHandler := handlers.NewSubjectHandler(subjectService)
 
 	// Inicializa o Router
 	r := api.NewRouter(&api.RouterHandlers{
-		SubjectHandler:  subjectHandler,
-		TopicHandler:    topicHandler,
-		ChoiceHandler:   choiceHandler,
-		QuestionHandler: questionHandler,
-		ExamHandler:     examHandler,
+		SubjectHandler:        subjectHandler,
+		TopicHandler:          topicHandler,
+		ChoiceHandler:         choiceHandler,
+		QuestionHandler:       questionHandler,
+		ExamHandler:           examHandler,
+		ReviewHandler:         reviewHandler,
+		RevisionHandler:       revisionHandler,
+		TrashHandler:          trashHandler,
+		DuplicateHandler:      duplicateHandler,
+		BoardHandler:          boardHandler,
+		TaxonomyHandler:       taxonomyHandler,
+		LegalReferenceHandler: legalReferenceHandler,
+		StatisticsHandler:     statisticsHandler,
+		IRTHandler:            irtHandler,
+		ReportHandler:         reportHandler,
+		ImportJobHandler:      importJobHandler,
 	})
 
+	// Processa as importações em segundo plano, retomando as que ficaram
+	// pendentes antes da última parada do servidor
+	go importJobService.Run(ctx)
+
 	slog.InfoContext(ctx, "Server executing on port 8000")
 	if err := http.ListenAndServe(":8000", r); err != nil {
 		slog.ErrorContext(ctx, "Error starting server", "error", err)
@@ -16,9 +16,21 @@ INSERT INTO
     choices (
         question_id,
         choice_text,
-        is_correct
+        is_correct,
+        position
     )
-VALUES ($1, $2, $3) RETURNING id, question_id, choice_text, is_correct
+VALUES (
+        $1,
+        $2,
+        $3,
+        (
+            SELECT COALESCE(MAX(position) + 1, 0)
+            FROM choices
+            WHERE
+                question_id = $1
+                AND deleted_at IS NULL
+        )
+    ) RETURNING id, question_id, choice_text, is_correct, deleted_at, position
 `
 
 type CreateChoiceParams struct {
@@ -35,21 +47,14 @@ func (q *Queries) CreateChoice(ctx context.Context, arg CreateChoiceParams) (Cho
 		&i.QuestionID,
 		&i.ChoiceText,
 		&i.IsCorrect,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return i, err
 }
 
-const deleteChoice = `-- name: DeleteChoice :exec
-DELETE FROM choices WHERE id = $1
-`
-
-func (q *Queries) DeleteChoice(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteChoice, id)
-	return err
-}
-
 const getChoice = `-- name: GetChoice :one
-SELECT id, question_id, choice_text, is_correct FROM choices WHERE id = $1
+SELECT id, question_id, choice_text, is_correct, deleted_at, position FROM choices WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetChoice(ctx context.Context, id pgtype.UUID) (Choice, error) {
@@ -60,12 +65,19 @@ func (q *Queries) GetChoice(ctx context.Context, id pgtype.UUID) (Choice, error)
 		&i.QuestionID,
 		&i.ChoiceText,
 		&i.IsCorrect,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return i, err
 }
 
 const listChoicesByQuestion = `-- name: ListChoicesByQuestion :many
-SELECT id, question_id, choice_text, is_correct FROM choices WHERE question_id = $1 ORDER BY id
+SELECT id, question_id, choice_text, is_correct, deleted_at, position
+FROM choices
+WHERE
+    question_id = $1
+    AND deleted_at IS NULL
+ORDER BY position, id
 `
 
 func (q *Queries) ListChoicesByQuestion(ctx context.Context, questionID pgtype.UUID) ([]Choice, error) {
@@ -82,6 +94,44 @@ func (q *Queries) ListChoicesByQuestion(ctx context.Context, questionID pgtype.U
 			&i.QuestionID,
 			&i.ChoiceText,
 			&i.IsCorrect,
+			&i.DeletedAt,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChoicesByQuestions = `-- name: ListChoicesByQuestions :many
+SELECT id, question_id, choice_text, is_correct, deleted_at, position
+FROM choices
+WHERE
+    question_id = ANY($1::UUID[])
+    AND deleted_at IS NULL
+ORDER BY question_id, position, id
+`
+
+func (q *Queries) ListChoicesByQuestions(ctx context.Context, ids []pgtype.UUID) ([]Choice, error) {
+	rows, err := q.db.Query(ctx, listChoicesByQuestions, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Choice{}
+	for rows.Next() {
+		var i Choice
+		if err := rows.Scan(
+			&i.ID,
+			&i.QuestionID,
+			&i.ChoiceText,
+			&i.IsCorrect,
+			&i.DeletedAt,
+			&i.Position,
 		); err != nil {
 			return nil, err
 		}
@@ -99,7 +149,8 @@ SET
     choice_text = $2,
     is_correct = $3
 WHERE
-    id = $1 RETURNING id, question_id, choice_text, is_correct
+    id = $1
+    AND deleted_at IS NULL RETURNING id, question_id, choice_text, is_correct, deleted_at, position
 `
 
 type UpdateChoiceParams struct {
@@ -116,6 +167,27 @@ func (q *Queries) UpdateChoice(ctx context.Context, arg UpdateChoiceParams) (Cho
 		&i.QuestionID,
 		&i.ChoiceText,
 		&i.IsCorrect,
+		&i.DeletedAt,
+		&i.Position,
 	)
 	return i, err
 }
+
+const updateChoicePosition = `-- name: UpdateChoicePosition :exec
+UPDATE choices
+SET
+    position = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+`
+
+type UpdateChoicePositionParams struct {
+	ID       pgtype.UUID `json:"id"`
+	Position int32       `json:"position"`
+}
+
+func (q *Queries) UpdateChoicePosition(ctx context.Context, arg UpdateChoicePositionParams) error {
+	_, err := q.db.Exec(ctx, updateChoicePosition, arg.ID, arg.Position)
+	return err
+}
@@ -8,34 +8,231 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Choice struct {
-	ID         pgtype.UUID `json:"id"`
+type AttemptAnswer struct {
+	AttemptID  pgtype.UUID `json:"attempt_id"`
+	Number     int32       `json:"number"`
 	QuestionID pgtype.UUID `json:"question_id"`
-	ChoiceText string      `json:"choice_text"`
-	IsCorrect  pgtype.Bool `json:"is_correct"`
+	ChoiceID   pgtype.UUID `json:"choice_id"`
+	IsCorrect  bool        `json:"is_correct"`
+}
+
+type Board struct {
+	ID          pgtype.UUID        `json:"id"`
+	Name        string             `json:"name"`
+	ChoiceCount int32              `json:"choice_count"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type BulkUpdate struct {
+	ID          pgtype.UUID        `json:"id"`
+	Author      string             `json:"author"`
+	Selection   []byte             `json:"selection"`
+	Changes     []byte             `json:"changes"`
+	QuestionIds []pgtype.UUID      `json:"question_ids"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type CatResponse struct {
+	SessionID  pgtype.UUID        `json:"session_id"`
+	Number     int32              `json:"number"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	ChoiceID   pgtype.UUID        `json:"choice_id"`
+	IsCorrect  pgtype.Bool        `json:"is_correct"`
+	Theta      pgtype.Float8      `json:"theta"`
+	Se         pgtype.Float8      `json:"se"`
+	AnsweredAt pgtype.Timestamptz `json:"answered_at"`
+}
+
+type CatSession struct {
+	ID         pgtype.UUID        `json:"id"`
+	Candidate  string             `json:"candidate"`
+	SubjectID  pgtype.UUID        `json:"subject_id"`
+	MaxItems   int32              `json:"max_items"`
+	TargetSe   float64            `json:"target_se"`
+	Status     string             `json:"status"`
+	Theta      float64            `json:"theta"`
+	Se         float64            `json:"se"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	FinishedAt pgtype.Timestamptz `json:"finished_at"`
+}
+
+type Choice struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	ChoiceText string             `json:"choice_text"`
+	IsCorrect  pgtype.Bool        `json:"is_correct"`
+	DeletedAt  pgtype.Timestamptz `json:"deleted_at"`
+	Position   int32              `json:"position"`
+}
+
+type Exam struct {
+	ID        pgtype.UUID        `json:"id"`
+	CreatedBy string             `json:"created_by"`
+	Filters   []byte             `json:"filters"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExamAttempt struct {
+	ID        pgtype.UUID        `json:"id"`
+	ExamID    pgtype.UUID        `json:"exam_id"`
+	Candidate string             `json:"candidate"`
+	Score     int32              `json:"score"`
+	Total     int32              `json:"total"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExamQuestion struct {
+	ExamID      pgtype.UUID   `json:"exam_id"`
+	Number      int32         `json:"number"`
+	QuestionID  pgtype.UUID   `json:"question_id"`
+	RevisionID  pgtype.UUID   `json:"revision_id"`
+	SubjectName string        `json:"subject_name"`
+	Answer      string        `json:"answer"`
+	ChoiceOrder []pgtype.UUID `json:"choice_order"`
+}
+
+type ImportJob struct {
+	ID              pgtype.UUID        `json:"id"`
+	Filename        string             `json:"filename"`
+	Options         []byte             `json:"options"`
+	Status          string             `json:"status"`
+	CreatedBy       string             `json:"created_by"`
+	Processed       int32              `json:"processed"`
+	Created         int32              `json:"created"`
+	Ignored         int32              `json:"ignored"`
+	Failed          int32              `json:"failed"`
+	Report          []byte             `json:"report"`
+	Error           pgtype.Text        `json:"error"`
+	CancelRequested bool               `json:"cancel_requested"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+	FinishedAt      pgtype.Timestamptz `json:"finished_at"`
+}
+
+type ImportJobFile struct {
+	JobID   pgtype.UUID `json:"job_id"`
+	Content []byte      `json:"content"`
+}
+
+type ItemParameter struct {
+	QuestionID     pgtype.UUID        `json:"question_id"`
+	Model          string             `json:"model"`
+	Discrimination float64            `json:"discrimination"`
+	Difficulty     float64            `json:"difficulty"`
+	Guessing       float64            `json:"guessing"`
+	Responses      int32              `json:"responses"`
+	CalibratedAt   pgtype.Timestamptz `json:"calibrated_at"`
+}
+
+type LegalReference struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	Law        string             `json:"law"`
+	Article    pgtype.Text        `json:"article"`
+	Paragraph  pgtype.Text        `json:"paragraph"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type Question struct {
-	ID           pgtype.UUID        `json:"id"`
-	Statement    string             `json:"statement"`
-	Year         int32              `json:"year"`
-	TopicID      pgtype.UUID        `json:"topic_id"`
-	Position     pgtype.Text        `json:"position"`
-	Level        pgtype.Text        `json:"level"`
-	Difficulty   pgtype.Text        `json:"difficulty"`
-	Modality     pgtype.Text        `json:"modality"`
-	PracticeArea pgtype.Text        `json:"practice_area"`
-	FieldOfStudy pgtype.Text        `json:"field_of_study"`
-	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ID                  pgtype.UUID        `json:"id"`
+	Statement           string             `json:"statement"`
+	Year                int32              `json:"year"`
+	TopicID             pgtype.UUID        `json:"topic_id"`
+	Position            pgtype.Text        `json:"position"`
+	Level               pgtype.Text        `json:"level"`
+	Difficulty          pgtype.Text        `json:"difficulty"`
+	Modality            pgtype.Text        `json:"modality"`
+	PracticeArea        pgtype.Text        `json:"practice_area"`
+	FieldOfStudy        pgtype.Text        `json:"field_of_study"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	ReviewStatus        string             `json:"review_status"`
+	DeletedAt           pgtype.Timestamptz `json:"deleted_at"`
+	NormalizedStatement string             `json:"normalized_statement"`
+	Explanation         pgtype.Text        `json:"explanation"`
+	Board               pgtype.Text        `json:"board"`
+	ChoiceCount         pgtype.Int4        `json:"choice_count"`
+	ExternalID          pgtype.Text        `json:"external_id"`
+}
+
+type QuestionIntegrity struct {
+	QuestionID      pgtype.UUID `json:"question_id"`
+	ExpectedChoices int32       `json:"expected_choices"`
+	Choices         int64       `json:"choices"`
+	CorrectChoices  int64       `json:"correct_choices"`
+	EmptyChoices    int64       `json:"empty_choices"`
+	EmptyStatement  bool        `json:"empty_statement"`
+}
+
+type QuestionReport struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	Category   string             `json:"category"`
+	Comment    string             `json:"comment"`
+	Reporter   string             `json:"reporter"`
+	Status     string             `json:"status"`
+	Resolution pgtype.Text        `json:"resolution"`
+	Moderator  pgtype.Text        `json:"moderator"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ClosedAt   pgtype.Timestamptz `json:"closed_at"`
+}
+
+type QuestionReview struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	Reviewer   string             `json:"reviewer"`
+	Action     string             `json:"action"`
+	Comment    pgtype.Text        `json:"comment"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type QuestionRevision struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	Revision   int32              `json:"revision"`
+	Author     string             `json:"author"`
+	Reason     string             `json:"reason"`
+	Snapshot   []byte             `json:"snapshot"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type QuestionSearchDocument struct {
+	QuestionID pgtype.UUID `json:"question_id"`
+	Document   interface{} `json:"document"`
+}
+
+type QuestionStatistic struct {
+	QuestionID     pgtype.UUID        `json:"question_id"`
+	Responses      int32              `json:"responses"`
+	PCorrect       float64            `json:"p_correct"`
+	Discrimination pgtype.Float8      `json:"discrimination"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
+type QuestionSuspension struct {
+	QuestionID  pgtype.UUID        `json:"question_id"`
+	OpenReports int32              `json:"open_reports"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
 }
 
 type Subject struct {
-	ID   pgtype.UUID `json:"id"`
-	Name string      `json:"name"`
+	ID        pgtype.UUID        `json:"id"`
+	Name      string             `json:"name"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type TaxonomyChange struct {
+	ID          pgtype.UUID        `json:"id"`
+	Author      string             `json:"author"`
+	Action      string             `json:"action"`
+	SourceID    pgtype.UUID        `json:"source_id"`
+	TargetID    pgtype.UUID        `json:"target_id"`
+	QuestionIds []pgtype.UUID      `json:"question_ids"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 }
 
 type Topic struct {
-	ID        pgtype.UUID `json:"id"`
-	SubjectID pgtype.UUID `json:"subject_id"`
-	Name      string      `json:"name"`
+	ID        pgtype.UUID        `json:"id"`
+	SubjectID pgtype.UUID        `json:"subject_id"`
+	Name      string             `json:"name"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
 }
@@ -11,6 +11,11 @@ import (
 )
 
 type Querier interface {
+	AnswerCATResponse(ctx context.Context, arg AnswerCATResponseParams) error
+	BulkUpdateQuestions(ctx context.Context, arg BulkUpdateQuestionsParams) ([]Question, error)
+	ClaimImportJob(ctx context.Context) (ImportJob, error)
+	CountOpenReports(ctx context.Context, questionID pgtype.UUID) (int64, error)
+	CountQuestionDependents(ctx context.Context, questionID pgtype.UUID) (int64, error)
 	CountQuestions(ctx context.Context) (int64, error)
 	CountQuestionsByFieldOfStudy(ctx context.Context, fieldOfStudy pgtype.Text) (int64, error)
 	CountQuestionsByFilters(ctx context.Context, arg CountQuestionsByFiltersParams) (int64, error)
@@ -21,42 +26,165 @@ type Querier interface {
 	CountQuestionsByYear(ctx context.Context, year int32) (int64, error)
 	CountQuestionsByYearAndLevel(ctx context.Context, arg CountQuestionsByYearAndLevelParams) (int64, error)
 	CountQuestionsForExam(ctx context.Context, arg CountQuestionsForExamParams) (int64, error)
+	CountQuestionsForReviewBySubject(ctx context.Context, reviewStatus string) ([]CountQuestionsForReviewBySubjectRow, error)
+	CountSearchQuestions(ctx context.Context, arg CountSearchQuestionsParams) (int64, error)
+	CountSubjectDependents(ctx context.Context, subjectID pgtype.UUID) (CountSubjectDependentsRow, error)
 	CountSubjects(ctx context.Context) (int64, error)
 	CountSubjectsByName(ctx context.Context, dollar_1 pgtype.Text) (int64, error)
+	CountTopicDependents(ctx context.Context, topicID pgtype.UUID) (CountTopicDependentsRow, error)
+	CreateAttemptAnswer(ctx context.Context, arg CreateAttemptAnswerParams) error
+	CreateBoard(ctx context.Context, arg CreateBoardParams) (Board, error)
+	CreateBulkUpdate(ctx context.Context, arg CreateBulkUpdateParams) (BulkUpdate, error)
+	CreateCATResponse(ctx context.Context, arg CreateCATResponseParams) error
+	CreateCATSession(ctx context.Context, arg CreateCATSessionParams) (CatSession, error)
 	CreateChoice(ctx context.Context, arg CreateChoiceParams) (Choice, error)
+	CreateExam(ctx context.Context, arg CreateExamParams) (Exam, error)
+	CreateExamAttempt(ctx context.Context, arg CreateExamAttemptParams) (ExamAttempt, error)
+	CreateExamQuestion(ctx context.Context, arg CreateExamQuestionParams) error
+	CreateImportJob(ctx context.Context, arg CreateImportJobParams) (ImportJob, error)
+	CreateImportJobFile(ctx context.Context, arg CreateImportJobFileParams) error
+	CreateLegalReference(ctx context.Context, arg CreateLegalReferenceParams) (LegalReference, error)
 	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error)
+	CreateQuestionReport(ctx context.Context, arg CreateQuestionReportParams) (QuestionReport, error)
+	CreateQuestionReview(ctx context.Context, arg CreateQuestionReviewParams) (QuestionReview, error)
+	CreateQuestionRevision(ctx context.Context, arg CreateQuestionRevisionParams) (QuestionRevision, error)
+	CreateQuestionWithID(ctx context.Context, arg CreateQuestionWithIDParams) (Question, error)
 	CreateSubject(ctx context.Context, name string) (Subject, error)
+	CreateTaxonomyChange(ctx context.Context, arg CreateTaxonomyChangeParams) (TaxonomyChange, error)
 	CreateTopic(ctx context.Context, arg CreateTopicParams) (Topic, error)
-	DeleteChoice(ctx context.Context, id pgtype.UUID) error
-	DeleteQuestion(ctx context.Context, id pgtype.UUID) error
-	DeleteSubject(ctx context.Context, id pgtype.UUID) error
-	DeleteTopic(ctx context.Context, id pgtype.UUID) error
+	DeleteBoard(ctx context.Context, id pgtype.UUID) error
+	DeleteImportJobFile(ctx context.Context, jobID pgtype.UUID) error
+	DeleteLegalReference(ctx context.Context, arg DeleteLegalReferenceParams) (int64, error)
+	FindSimilarQuestions(ctx context.Context, arg FindSimilarQuestionsParams) ([]FindSimilarQuestionsRow, error)
+	FinishImportJob(ctx context.Context, arg FinishImportJobParams) (ImportJob, error)
+	GetBoard(ctx context.Context, id pgtype.UUID) (Board, error)
+	GetBoardByName(ctx context.Context, name string) (Board, error)
+	GetCATSession(ctx context.Context, id pgtype.UUID) (CatSession, error)
+	GetCATSessionForUpdate(ctx context.Context, id pgtype.UUID) (CatSession, error)
 	GetChoice(ctx context.Context, id pgtype.UUID) (Choice, error)
+	GetExam(ctx context.Context, id pgtype.UUID) (Exam, error)
+	GetImportJob(ctx context.Context, id pgtype.UUID) (ImportJob, error)
+	GetImportJobFile(ctx context.Context, jobID pgtype.UUID) ([]byte, error)
+	GetItemParameters(ctx context.Context, questionID pgtype.UUID) (ItemParameter, error)
+	GetLatestQuestionRevision(ctx context.Context, questionID pgtype.UUID) (QuestionRevision, error)
 	GetQuestion(ctx context.Context, id pgtype.UUID) (Question, error)
+	GetQuestionForUpdate(ctx context.Context, id pgtype.UUID) (Question, error)
+	GetQuestionIDByExternalID(ctx context.Context, externalID pgtype.Text) (pgtype.UUID, error)
+	GetQuestionIntegrity(ctx context.Context, questionID pgtype.UUID) (QuestionIntegrity, error)
+	GetQuestionReport(ctx context.Context, id pgtype.UUID) (QuestionReport, error)
+	GetQuestionReportForUpdate(ctx context.Context, id pgtype.UUID) (QuestionReport, error)
+	GetQuestionRevision(ctx context.Context, arg GetQuestionRevisionParams) (QuestionRevision, error)
+	GetQuestionStatistics(ctx context.Context, questionID pgtype.UUID) (QuestionStatistic, error)
+	GetQuestionSuspension(ctx context.Context, questionID pgtype.UUID) (QuestionSuspension, error)
 	GetQuestionsForExam(ctx context.Context, arg GetQuestionsForExamParams) ([]GetQuestionsForExamRow, error)
 	GetSubject(ctx context.Context, id pgtype.UUID) (Subject, error)
 	GetSubjectByName(ctx context.Context, name string) (Subject, error)
 	GetTopic(ctx context.Context, id pgtype.UUID) (Topic, error)
+	GetTopicByName(ctx context.Context, arg GetTopicByNameParams) (Topic, error)
+	GetTrashedChoice(ctx context.Context, id pgtype.UUID) (Choice, error)
+	GetTrashedQuestion(ctx context.Context, id pgtype.UUID) (Question, error)
+	GetTrashedSubject(ctx context.Context, id pgtype.UUID) (Subject, error)
+	GetTrashedTopic(ctx context.Context, id pgtype.UUID) (Topic, error)
+	LiftQuestionSuspension(ctx context.Context, questionID pgtype.UUID) (int64, error)
+	ListBoards(ctx context.Context) ([]Board, error)
+	ListBulkUpdates(ctx context.Context) ([]BulkUpdate, error)
+	ListCATItems(ctx context.Context, subjectID pgtype.UUID) ([]ListCATItemsRow, error)
+	ListCATResponses(ctx context.Context, sessionID pgtype.UUID) ([]ListCATResponsesRow, error)
+	ListCalibrationResponses(ctx context.Context) ([]ListCalibrationResponsesRow, error)
 	ListChoicesByQuestion(ctx context.Context, questionID pgtype.UUID) ([]Choice, error)
+	ListChoicesByQuestions(ctx context.Context, ids []pgtype.UUID) ([]Choice, error)
+	ListExamAttempts(ctx context.Context, examID pgtype.UUID) ([]ExamAttempt, error)
+	ListExamQuestions(ctx context.Context, examID pgtype.UUID) ([]ListExamQuestionsRow, error)
+	ListExams(ctx context.Context) ([]Exam, error)
+	ListImportJobs(ctx context.Context) ([]ImportJob, error)
+	ListItemParameters(ctx context.Context) ([]ItemParameter, error)
+	ListLegalReferencesByQuestion(ctx context.Context, questionID pgtype.UUID) ([]LegalReference, error)
+	ListLegalReferencesByQuestions(ctx context.Context, ids []pgtype.UUID) ([]LegalReference, error)
+	ListQuestionIDsMissingExternalIDs(ctx context.Context, arg ListQuestionIDsMissingExternalIDsParams) ([]pgtype.UUID, error)
+	ListQuestionIntegrityIssues(ctx context.Context) ([]ListQuestionIntegrityIssuesRow, error)
+	ListQuestionReports(ctx context.Context, arg ListQuestionReportsParams) ([]QuestionReport, error)
+	ListQuestionResponses(ctx context.Context, ids []pgtype.UUID) ([]ListQuestionResponsesRow, error)
+	ListQuestionReviews(ctx context.Context, questionID pgtype.UUID) ([]QuestionReview, error)
+	ListQuestionRevisions(ctx context.Context, questionID pgtype.UUID) ([]QuestionRevision, error)
+	ListQuestionSuspensions(ctx context.Context) ([]QuestionSuspension, error)
 	ListQuestions(ctx context.Context) ([]Question, error)
 	ListQuestionsByFieldOfStudy(ctx context.Context, fieldOfStudy pgtype.Text) ([]Question, error)
 	ListQuestionsByFilters(ctx context.Context, arg ListQuestionsByFiltersParams) ([]Question, error)
 	ListQuestionsByFiltersWithChoices(ctx context.Context, arg ListQuestionsByFiltersWithChoicesParams) ([]ListQuestionsByFiltersWithChoicesRow, error)
+	ListQuestionsByIDs(ctx context.Context, ids []pgtype.UUID) ([]Question, error)
+	ListQuestionsByLegalReference(ctx context.Context, arg ListQuestionsByLegalReferenceParams) ([]Question, error)
 	ListQuestionsByLevel(ctx context.Context, level pgtype.Text) ([]Question, error)
 	ListQuestionsByModality(ctx context.Context, modality pgtype.Text) ([]Question, error)
 	ListQuestionsByPracticeArea(ctx context.Context, practiceArea pgtype.Text) ([]Question, error)
 	ListQuestionsByTopic(ctx context.Context, topicID pgtype.UUID) ([]Question, error)
 	ListQuestionsByYear(ctx context.Context, year int32) ([]Question, error)
 	ListQuestionsByYearAndLevel(ctx context.Context, arg ListQuestionsByYearAndLevelParams) ([]Question, error)
+	ListQuestionsForExport(ctx context.Context, arg ListQuestionsForExportParams) ([]ListQuestionsForExportRow, error)
+	ListQuestionsForReviewBySubject(ctx context.Context, arg ListQuestionsForReviewBySubjectParams) ([]Question, error)
+	ListReportsByQuestion(ctx context.Context, questionID pgtype.UUID) ([]QuestionReport, error)
+	ListSimilarQuestionPairs(ctx context.Context, arg ListSimilarQuestionPairsParams) ([]ListSimilarQuestionPairsRow, error)
 	ListSubjects(ctx context.Context) ([]Subject, error)
 	ListSubjectsByName(ctx context.Context, dollar_1 pgtype.Text) ([]Subject, error)
+	ListTaxonomyChanges(ctx context.Context) ([]TaxonomyChange, error)
 	ListTopics(ctx context.Context) ([]Topic, error)
 	ListTopicsBySubject(ctx context.Context, subjectID pgtype.UUID) ([]Topic, error)
+	ListTrashedChoices(ctx context.Context) ([]Choice, error)
+	ListTrashedQuestions(ctx context.Context) ([]Question, error)
+	ListTrashedSubjects(ctx context.Context) ([]Subject, error)
+	ListTrashedTopics(ctx context.Context) ([]Topic, error)
+	ListTrashedTopicsBySubject(ctx context.Context, subjectID pgtype.UUID) ([]Topic, error)
+	MoveQuestionsByIDsToTopic(ctx context.Context, arg MoveQuestionsByIDsToTopicParams) ([]Question, error)
+	MoveQuestionsToTopic(ctx context.Context, arg MoveQuestionsToTopicParams) ([]Question, error)
+	MoveTopicsToSubject(ctx context.Context, arg MoveTopicsToSubjectParams) error
+	PurgeChoice(ctx context.Context, id pgtype.UUID) (int64, error)
+	PurgeQuestion(ctx context.Context, id pgtype.UUID) (int64, error)
+	PurgeQuestionsBySubject(ctx context.Context, subjectID pgtype.UUID) error
+	PurgeQuestionsByTopic(ctx context.Context, topicID pgtype.UUID) error
+	PurgeSubject(ctx context.Context, id pgtype.UUID) (int64, error)
+	PurgeTopic(ctx context.Context, id pgtype.UUID) (int64, error)
+	PurgeTrashedChoices(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeTrashedQuestions(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeTrashedSubjects(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
+	PurgeTrashedTopics(ctx context.Context, deletedAt pgtype.Timestamptz) (int64, error)
 	QuestionExistsByStatement(ctx context.Context, statement string) (bool, error)
+	ReopenQuestionReview(ctx context.Context, id pgtype.UUID) error
+	RequestImportJobCancel(ctx context.Context, id pgtype.UUID) (ImportJob, error)
+	RequeueRunningImportJobs(ctx context.Context) (int64, error)
+	RestoreChoice(ctx context.Context, id pgtype.UUID) error
+	RestoreChoicesByQuestion(ctx context.Context, arg RestoreChoicesByQuestionParams) error
+	RestoreChoicesBySubject(ctx context.Context, arg RestoreChoicesBySubjectParams) error
+	RestoreChoicesByTopic(ctx context.Context, arg RestoreChoicesByTopicParams) error
+	RestoreQuestion(ctx context.Context, id pgtype.UUID) error
+	RestoreQuestionsBySubject(ctx context.Context, arg RestoreQuestionsBySubjectParams) error
+	RestoreQuestionsByTopic(ctx context.Context, arg RestoreQuestionsByTopicParams) error
+	RestoreSubject(ctx context.Context, id pgtype.UUID) error
+	RestoreTopic(ctx context.Context, id pgtype.UUID) error
+	RestoreTopicsBySubject(ctx context.Context, arg RestoreTopicsBySubjectParams) error
+	SearchQuestions(ctx context.Context, arg SearchQuestionsParams) ([]SearchQuestionsRow, error)
+	SetQuestionExternalID(ctx context.Context, arg SetQuestionExternalIDParams) error
+	SetQuestionReviewStatus(ctx context.Context, arg SetQuestionReviewStatusParams) (Question, error)
+	SoftDeleteChoice(ctx context.Context, arg SoftDeleteChoiceParams) (int64, error)
+	SoftDeleteChoicesByQuestion(ctx context.Context, arg SoftDeleteChoicesByQuestionParams) error
+	SoftDeleteChoicesBySubject(ctx context.Context, arg SoftDeleteChoicesBySubjectParams) error
+	SoftDeleteChoicesByTopic(ctx context.Context, arg SoftDeleteChoicesByTopicParams) error
+	SoftDeleteQuestion(ctx context.Context, arg SoftDeleteQuestionParams) (int64, error)
+	SoftDeleteQuestionsBySubject(ctx context.Context, arg SoftDeleteQuestionsBySubjectParams) error
+	SoftDeleteQuestionsByTopic(ctx context.Context, arg SoftDeleteQuestionsByTopicParams) error
+	SoftDeleteSubject(ctx context.Context, arg SoftDeleteSubjectParams) (int64, error)
+	SoftDeleteTopic(ctx context.Context, arg SoftDeleteTopicParams) (int64, error)
+	SoftDeleteTopicsBySubject(ctx context.Context, arg SoftDeleteTopicsBySubjectParams) error
+	SuspendQuestion(ctx context.Context, arg SuspendQuestionParams) (QuestionSuspension, error)
+	UpdateBoard(ctx context.Context, arg UpdateBoardParams) (Board, error)
+	UpdateCATSession(ctx context.Context, arg UpdateCATSessionParams) (CatSession, error)
 	UpdateChoice(ctx context.Context, arg UpdateChoiceParams) (Choice, error)
+	UpdateChoicePosition(ctx context.Context, arg UpdateChoicePositionParams) error
+	UpdateImportJobProgress(ctx context.Context, arg UpdateImportJobProgressParams) error
 	UpdateQuestion(ctx context.Context, arg UpdateQuestionParams) (Question, error)
+	UpdateQuestionReport(ctx context.Context, arg UpdateQuestionReportParams) (QuestionReport, error)
 	UpdateSubject(ctx context.Context, arg UpdateSubjectParams) (Subject, error)
 	UpdateTopic(ctx context.Context, arg UpdateTopicParams) (Topic, error)
+	UpsertItemParameters(ctx context.Context, arg UpsertItemParametersParams) (ItemParameter, error)
+	UpsertQuestionStatistics(ctx context.Context, arg UpsertQuestionStatisticsParams) (QuestionStatistic, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -12,7 +12,7 @@ import (
 )
 
 const countQuestions = `-- name: CountQuestions :one
-SELECT COUNT(*) FROM questions
+SELECT COUNT(*) FROM questions WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestions(ctx context.Context) (int64, error) {
@@ -23,7 +23,11 @@ func (q *Queries) CountQuestions(ctx context.Context) (int64, error) {
 }
 
 const countQuestionsByFieldOfStudy = `-- name: CountQuestionsByFieldOfStudy :one
-SELECT COUNT(*) FROM questions WHERE field_of_study = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    field_of_study = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByFieldOfStudy(ctx context.Context, fieldOfStudy pgtype.Text) (int64, error) {
@@ -37,7 +41,8 @@ const countQuestionsByFilters = `-- name: CountQuestionsByFilters :one
 SELECT COUNT(*)
 FROM questions
 WHERE
-    ($1::INT IS NULL OR year = $1)
+    deleted_at IS NULL
+    AND ($1::INT IS NULL OR year = $1)
     AND ($2::TEXT IS NULL OR level = $2)
     AND ($3::TEXT IS NULL OR difficulty = $3)
     AND ($4::TEXT IS NULL OR modality = $4)
@@ -75,7 +80,11 @@ func (q *Queries) CountQuestionsByFilters(ctx context.Context, arg CountQuestion
 }
 
 const countQuestionsByLevel = `-- name: CountQuestionsByLevel :one
-SELECT COUNT(*) FROM questions WHERE level = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    level = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByLevel(ctx context.Context, level pgtype.Text) (int64, error) {
@@ -86,7 +95,11 @@ func (q *Queries) CountQuestionsByLevel(ctx context.Context, level pgtype.Text)
 }
 
 const countQuestionsByModality = `-- name: CountQuestionsByModality :one
-SELECT COUNT(*) FROM questions WHERE modality = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    modality = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByModality(ctx context.Context, modality pgtype.Text) (int64, error) {
@@ -97,7 +110,11 @@ func (q *Queries) CountQuestionsByModality(ctx context.Context, modality pgtype.
 }
 
 const countQuestionsByPracticeArea = `-- name: CountQuestionsByPracticeArea :one
-SELECT COUNT(*) FROM questions WHERE practice_area = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    practice_area = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByPracticeArea(ctx context.Context, practiceArea pgtype.Text) (int64, error) {
@@ -108,7 +125,11 @@ func (q *Queries) CountQuestionsByPracticeArea(ctx context.Context, practiceArea
 }
 
 const countQuestionsByTopic = `-- name: CountQuestionsByTopic :one
-SELECT COUNT(*) FROM questions WHERE topic_id = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    topic_id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByTopic(ctx context.Context, topicID pgtype.UUID) (int64, error) {
@@ -119,7 +140,11 @@ func (q *Queries) CountQuestionsByTopic(ctx context.Context, topicID pgtype.UUID
 }
 
 const countQuestionsByYear = `-- name: CountQuestionsByYear :one
-SELECT COUNT(*) FROM questions WHERE year = $1
+SELECT COUNT(*)
+FROM questions
+WHERE
+    year = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountQuestionsByYear(ctx context.Context, year int32) (int64, error) {
@@ -130,7 +155,12 @@ func (q *Queries) CountQuestionsByYear(ctx context.Context, year int32) (int64,
 }
 
 const countQuestionsByYearAndLevel = `-- name: CountQuestionsByYearAndLevel :one
-SELECT COUNT(*) FROM questions WHERE year = $1 AND level = $2
+SELECT COUNT(*)
+FROM questions
+WHERE
+    year = $1
+    AND level = $2
+    AND deleted_at IS NULL
 `
 
 type CountQuestionsByYearAndLevelParams struct {
@@ -152,6 +182,8 @@ JOIN topics t ON q.topic_id = t.id
 JOIN subjects s ON t.subject_id = s.id
 WHERE 
     s.id = $1 
+    AND q.review_status = 'approved'
+    AND q.deleted_at IS NULL
     AND ($2::uuid IS NULL OR q.topic_id = $2)
     AND ($3::text IS NULL OR q.position = $3)
     AND ($4::text IS NULL OR q.level = $4)
@@ -160,18 +192,51 @@ WHERE
     AND ($7::text IS NULL OR q.field_of_study = $7)
     AND ($8::int IS NULL OR q.year >= $8)
     AND ($9::int IS NULL OR q.year <= $9)
+    AND ($10::text IS NULL OR q.board = $10)
+    AND (
+        ($11::float8 IS NULL AND $12::float8 IS NULL)
+        OR EXISTS (
+            SELECT 1
+            FROM question_statistics qs
+            WHERE
+                qs.question_id = q.id
+                AND qs.responses >= COALESCE($13::int, 1)
+                AND qs.p_correct >= COALESCE($11, 0)
+                AND qs.p_correct <= COALESCE($12, 1)
+        )
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM question_suspensions sp
+        WHERE
+            sp.question_id = q.id
+    )
+    AND EXISTS (
+        SELECT 1
+        FROM question_integrity qi
+        WHERE
+            qi.question_id = q.id
+            AND NOT qi.empty_statement
+            AND qi.choices = qi.expected_choices
+            AND qi.correct_choices = 1
+            AND qi.empty_choices = 0
+    )
 `
 
 type CountQuestionsForExamParams struct {
-	ID           pgtype.UUID `json:"id"`
-	TopicID      pgtype.UUID `json:"topic_id"`
-	Position     pgtype.Text `json:"position"`
-	Level        pgtype.Text `json:"level"`
-	Difficulty   pgtype.Text `json:"difficulty"`
-	Modality     pgtype.Text `json:"modality"`
-	FieldOfStudy pgtype.Text `json:"field_of_study"`
-	MinYear      pgtype.Int4 `json:"min_year"`
-	MaxYear      pgtype.Int4 `json:"max_year"`
+	ID             pgtype.UUID   `json:"id"`
+	TopicID        pgtype.UUID   `json:"topic_id"`
+	Position       pgtype.Text   `json:"position"`
+	Level          pgtype.Text   `json:"level"`
+	Difficulty     pgtype.Text   `json:"difficulty"`
+	Modality       pgtype.Text   `json:"modality"`
+	FieldOfStudy   pgtype.Text   `json:"field_of_study"`
+	MinYear        pgtype.Int4   `json:"min_year"`
+	MaxYear        pgtype.Int4   `json:"max_year"`
+	Board          pgtype.Text   `json:"board"`
+	MinCorrectRate pgtype.Float8 `json:"min_correct_rate"`
+	MaxCorrectRate pgtype.Float8 `json:"max_correct_rate"`
+	MinResponses   pgtype.Int4   `json:"min_responses"`
 }
 
 func (q *Queries) CountQuestionsForExam(ctx context.Context, arg CountQuestionsForExamParams) (int64, error) {
@@ -185,6 +250,10 @@ func (q *Queries) CountQuestionsForExam(ctx context.Context, arg CountQuestionsF
 		arg.FieldOfStudy,
 		arg.MinYear,
 		arg.MaxYear,
+		arg.Board,
+		arg.MinCorrectRate,
+		arg.MaxCorrectRate,
+		arg.MinResponses,
 	)
 	var count int64
 	err := row.Scan(&count)
@@ -202,7 +271,12 @@ INSERT INTO
         difficulty,
         modality,
         practice_area,
-        field_of_study
+        field_of_study,
+        normalized_statement,
+        explanation,
+        board,
+        choice_count,
+        external_id
     )
 VALUES (
         $1,
@@ -213,20 +287,30 @@ VALUES (
         $6,
         $7,
         $8,
-        $9
-    ) RETURNING id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14
+    ) RETURNING id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 `
 
 type CreateQuestionParams struct {
-	Statement    string      `json:"statement"`
-	Year         int32       `json:"year"`
-	TopicID      pgtype.UUID `json:"topic_id"`
-	Position     pgtype.Text `json:"position"`
-	Level        pgtype.Text `json:"level"`
-	Difficulty   pgtype.Text `json:"difficulty"`
-	Modality     pgtype.Text `json:"modality"`
-	PracticeArea pgtype.Text `json:"practice_area"`
-	FieldOfStudy pgtype.Text `json:"field_of_study"`
+	Statement           string      `json:"statement"`
+	Year                int32       `json:"year"`
+	TopicID             pgtype.UUID `json:"topic_id"`
+	Position            pgtype.Text `json:"position"`
+	Level               pgtype.Text `json:"level"`
+	Difficulty          pgtype.Text `json:"difficulty"`
+	Modality            pgtype.Text `json:"modality"`
+	PracticeArea        pgtype.Text `json:"practice_area"`
+	FieldOfStudy        pgtype.Text `json:"field_of_study"`
+	NormalizedStatement string      `json:"normalized_statement"`
+	Explanation         pgtype.Text `json:"explanation"`
+	Board               pgtype.Text `json:"board"`
+	ChoiceCount         pgtype.Int4 `json:"choice_count"`
+	ExternalID          pgtype.Text `json:"external_id"`
 }
 
 func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error) {
@@ -240,6 +324,11 @@ func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams)
 		arg.Modality,
 		arg.PracticeArea,
 		arg.FieldOfStudy,
+		arg.NormalizedStatement,
+		arg.Explanation,
+		arg.Board,
+		arg.ChoiceCount,
+		arg.ExternalID,
 	)
 	var i Question
 	err := row.Scan(
@@ -254,21 +343,117 @@ func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams)
 		&i.PracticeArea,
 		&i.FieldOfStudy,
 		&i.CreatedAt,
+		&i.ReviewStatus,
+		&i.DeletedAt,
+		&i.NormalizedStatement,
+		&i.Explanation,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
 	)
 	return i, err
 }
 
-const deleteQuestion = `-- name: DeleteQuestion :exec
-DELETE FROM questions WHERE id = $1
+const createQuestionWithID = `-- name: CreateQuestionWithID :one
+INSERT INTO
+    questions (
+        id,
+        statement,
+        year,
+        topic_id,
+        position,
+        level,
+        difficulty,
+        modality,
+        practice_area,
+        field_of_study,
+        normalized_statement,
+        explanation,
+        board,
+        choice_count,
+        external_id
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15
+    ) RETURNING id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 `
 
-func (q *Queries) DeleteQuestion(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteQuestion, id)
-	return err
+type CreateQuestionWithIDParams struct {
+	ID                  pgtype.UUID `json:"id"`
+	Statement           string      `json:"statement"`
+	Year                int32       `json:"year"`
+	TopicID             pgtype.UUID `json:"topic_id"`
+	Position            pgtype.Text `json:"position"`
+	Level               pgtype.Text `json:"level"`
+	Difficulty          pgtype.Text `json:"difficulty"`
+	Modality            pgtype.Text `json:"modality"`
+	PracticeArea        pgtype.Text `json:"practice_area"`
+	FieldOfStudy        pgtype.Text `json:"field_of_study"`
+	NormalizedStatement string      `json:"normalized_statement"`
+	Explanation         pgtype.Text `json:"explanation"`
+	Board               pgtype.Text `json:"board"`
+	ChoiceCount         pgtype.Int4 `json:"choice_count"`
+	ExternalID          pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) CreateQuestionWithID(ctx context.Context, arg CreateQuestionWithIDParams) (Question, error) {
+	row := q.db.QueryRow(ctx, createQuestionWithID,
+		arg.ID,
+		arg.Statement,
+		arg.Year,
+		arg.TopicID,
+		arg.Position,
+		arg.Level,
+		arg.Difficulty,
+		arg.Modality,
+		arg.PracticeArea,
+		arg.FieldOfStudy,
+		arg.NormalizedStatement,
+		arg.Explanation,
+		arg.Board,
+		arg.ChoiceCount,
+		arg.ExternalID,
+	)
+	var i Question
+	err := row.Scan(
+		&i.ID,
+		&i.Statement,
+		&i.Year,
+		&i.TopicID,
+		&i.Position,
+		&i.Level,
+		&i.Difficulty,
+		&i.Modality,
+		&i.PracticeArea,
+		&i.FieldOfStudy,
+		&i.CreatedAt,
+		&i.ReviewStatus,
+		&i.DeletedAt,
+		&i.NormalizedStatement,
+		&i.Explanation,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
+	)
+	return i, err
 }
 
 const getQuestion = `-- name: GetQuestion :one
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at FROM questions WHERE id = $1
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id FROM questions WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetQuestion(ctx context.Context, id pgtype.UUID) (Question, error) {
@@ -286,6 +471,81 @@ func (q *Queries) GetQuestion(ctx context.Context, id pgtype.UUID) (Question, er
 		&i.PracticeArea,
 		&i.FieldOfStudy,
 		&i.CreatedAt,
+		&i.ReviewStatus,
+		&i.DeletedAt,
+		&i.NormalizedStatement,
+		&i.Explanation,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getQuestionForUpdate = `-- name: GetQuestionForUpdate :one
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
+FROM questions
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetQuestionForUpdate(ctx context.Context, id pgtype.UUID) (Question, error) {
+	row := q.db.QueryRow(ctx, getQuestionForUpdate, id)
+	var i Question
+	err := row.Scan(
+		&i.ID,
+		&i.Statement,
+		&i.Year,
+		&i.TopicID,
+		&i.Position,
+		&i.Level,
+		&i.Difficulty,
+		&i.Modality,
+		&i.PracticeArea,
+		&i.FieldOfStudy,
+		&i.CreatedAt,
+		&i.ReviewStatus,
+		&i.DeletedAt,
+		&i.NormalizedStatement,
+		&i.Explanation,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
+	)
+	return i, err
+}
+
+const getQuestionIDByExternalID = `-- name: GetQuestionIDByExternalID :one
+SELECT id
+FROM questions
+WHERE
+    external_id = $1
+    AND deleted_at IS NULL
+`
+
+func (q *Queries) GetQuestionIDByExternalID(ctx context.Context, externalID pgtype.Text) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getQuestionIDByExternalID, externalID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getQuestionIntegrity = `-- name: GetQuestionIntegrity :one
+SELECT question_id, expected_choices, choices, correct_choices, empty_choices, empty_statement FROM question_integrity WHERE question_id = $1
+`
+
+func (q *Queries) GetQuestionIntegrity(ctx context.Context, questionID pgtype.UUID) (QuestionIntegrity, error) {
+	row := q.db.QueryRow(ctx, getQuestionIntegrity, questionID)
+	var i QuestionIntegrity
+	err := row.Scan(
+		&i.QuestionID,
+		&i.ExpectedChoices,
+		&i.Choices,
+		&i.CorrectChoices,
+		&i.EmptyChoices,
+		&i.EmptyStatement,
 	)
 	return i, err
 }
@@ -300,6 +560,8 @@ JOIN topics t ON q.topic_id = t.id
 JOIN subjects s ON t.subject_id = s.id
 WHERE 
     s.id = $1 
+    AND q.review_status = 'approved'
+    AND q.deleted_at IS NULL
     AND ($3::uuid IS NULL OR q.topic_id = $3)
     AND ($4::text IS NULL OR q.position = $4)
     AND ($5::text IS NULL OR q.level = $5)
@@ -308,21 +570,54 @@ WHERE
     AND ($8::text IS NULL OR q.field_of_study = $8)
     AND ($9::int IS NULL OR q.year >= $9)
     AND ($10::int IS NULL OR q.year <= $10)
+    AND ($11::text IS NULL OR q.board = $11)
+    AND (
+        ($12::float8 IS NULL AND $13::float8 IS NULL)
+        OR EXISTS (
+            SELECT 1
+            FROM question_statistics qs
+            WHERE
+                qs.question_id = q.id
+                AND qs.responses >= COALESCE($14::int, 1)
+                AND qs.p_correct >= COALESCE($12, 0)
+                AND qs.p_correct <= COALESCE($13, 1)
+        )
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM question_suspensions sp
+        WHERE
+            sp.question_id = q.id
+    )
+    AND EXISTS (
+        SELECT 1
+        FROM question_integrity qi
+        WHERE
+            qi.question_id = q.id
+            AND NOT qi.empty_statement
+            AND qi.choices = qi.expected_choices
+            AND qi.correct_choices = 1
+            AND qi.empty_choices = 0
+    )
 ORDER BY RANDOM()
 LIMIT $2
 `
 
 type GetQuestionsForExamParams struct {
-	ID           pgtype.UUID `json:"id"`
-	Limit        int32       `json:"limit"`
-	TopicID      pgtype.UUID `json:"topic_id"`
-	Position     pgtype.Text `json:"position"`
-	Level        pgtype.Text `json:"level"`
-	Difficulty   pgtype.Text `json:"difficulty"`
-	Modality     pgtype.Text `json:"modality"`
-	FieldOfStudy pgtype.Text `json:"field_of_study"`
-	MinYear      pgtype.Int4 `json:"min_year"`
-	MaxYear      pgtype.Int4 `json:"max_year"`
+	ID             pgtype.UUID   `json:"id"`
+	Limit          int32         `json:"limit"`
+	TopicID        pgtype.UUID   `json:"topic_id"`
+	Position       pgtype.Text   `json:"position"`
+	Level          pgtype.Text   `json:"level"`
+	Difficulty     pgtype.Text   `json:"difficulty"`
+	Modality       pgtype.Text   `json:"modality"`
+	FieldOfStudy   pgtype.Text   `json:"field_of_study"`
+	MinYear        pgtype.Int4   `json:"min_year"`
+	MaxYear        pgtype.Int4   `json:"max_year"`
+	Board          pgtype.Text   `json:"board"`
+	MinCorrectRate pgtype.Float8 `json:"min_correct_rate"`
+	MaxCorrectRate pgtype.Float8 `json:"max_correct_rate"`
+	MinResponses   pgtype.Int4   `json:"min_responses"`
 }
 
 type GetQuestionsForExamRow struct {
@@ -350,6 +645,10 @@ func (q *Queries) GetQuestionsForExam(ctx context.Context, arg GetQuestionsForEx
 		arg.FieldOfStudy,
 		arg.MinYear,
 		arg.MaxYear,
+		arg.Board,
+		arg.MinCorrectRate,
+		arg.MaxCorrectRate,
+		arg.MinResponses,
 	)
 	if err != nil {
 		return nil, err
@@ -380,8 +679,113 @@ func (q *Queries) GetQuestionsForExam(ctx context.Context, arg GetQuestionsForEx
 	return items, nil
 }
 
+const listQuestionIDsMissingExternalIDs = `-- name: ListQuestionIDsMissingExternalIDs :many
+SELECT q.id
+FROM questions q
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    q.external_id IS NOT NULL
+    AND NOT (q.external_id = ANY($1::TEXT[]))
+    AND q.deleted_at IS NULL
+    AND (
+        t.subject_id = ANY($2::UUID[])
+        OR t.subject_id IN (
+            SELECT subject_id
+            FROM topics
+            WHERE
+                id = ANY($3::UUID[])
+        )
+    )
+ORDER BY q.id
+`
+
+type ListQuestionIDsMissingExternalIDsParams struct {
+	ExternalIds []string      `json:"external_ids"`
+	SubjectIds  []pgtype.UUID `json:"subject_ids"`
+	TopicIds    []pgtype.UUID `json:"topic_ids"`
+}
+
+func (q *Queries) ListQuestionIDsMissingExternalIDs(ctx context.Context, arg ListQuestionIDsMissingExternalIDsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listQuestionIDsMissingExternalIDs, arg.ExternalIds, arg.SubjectIds, arg.TopicIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []pgtype.UUID{}
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuestionIntegrityIssues = `-- name: ListQuestionIntegrityIssues :many
+SELECT q.id, q.statement, q.review_status, qi.expected_choices, qi.choices, qi.correct_choices, qi.empty_choices, qi.empty_statement
+FROM questions q
+    JOIN question_integrity qi ON qi.question_id = q.id
+WHERE
+    q.deleted_at IS NULL
+    AND (
+        qi.empty_statement
+        OR qi.choices <> qi.expected_choices
+        OR qi.correct_choices <> 1
+        OR qi.empty_choices > 0
+    )
+ORDER BY q.created_at
+`
+
+type ListQuestionIntegrityIssuesRow struct {
+	ID              pgtype.UUID `json:"id"`
+	Statement       string      `json:"statement"`
+	ReviewStatus    string      `json:"review_status"`
+	ExpectedChoices int32       `json:"expected_choices"`
+	Choices         int64       `json:"choices"`
+	CorrectChoices  int64       `json:"correct_choices"`
+	EmptyChoices    int64       `json:"empty_choices"`
+	EmptyStatement  bool        `json:"empty_statement"`
+}
+
+func (q *Queries) ListQuestionIntegrityIssues(ctx context.Context) ([]ListQuestionIntegrityIssuesRow, error) {
+	rows, err := q.db.Query(ctx, listQuestionIntegrityIssues)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListQuestionIntegrityIssuesRow{}
+	for rows.Next() {
+		var i ListQuestionIntegrityIssuesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Statement,
+			&i.ReviewStatus,
+			&i.ExpectedChoices,
+			&i.Choices,
+			&i.CorrectChoices,
+			&i.EmptyChoices,
+			&i.EmptyStatement,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listQuestions = `-- name: ListQuestions :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at FROM questions ORDER BY created_at DESC
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
+FROM questions
+WHERE
+    deleted_at IS NULL
+ORDER BY created_at DESC
 `
 
 func (q *Queries) ListQuestions(ctx context.Context) ([]Question, error) {
@@ -405,6 +809,13 @@ func (q *Queries) ListQuestions(ctx context.Context) ([]Question, error) {
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -417,10 +828,11 @@ func (q *Queries) ListQuestions(ctx context.Context) ([]Question, error) {
 }
 
 const listQuestionsByFieldOfStudy = `-- name: ListQuestionsByFieldOfStudy :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     field_of_study = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -445,6 +857,13 @@ func (q *Queries) ListQuestionsByFieldOfStudy(ctx context.Context, fieldOfStudy
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -457,10 +876,11 @@ func (q *Queries) ListQuestionsByFieldOfStudy(ctx context.Context, fieldOfStudy
 }
 
 const listQuestionsByFilters = `-- name: ListQuestionsByFilters :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
-    ($1::INT IS NULL OR year = $1)
+    deleted_at IS NULL
+    AND ($1::INT IS NULL OR year = $1)
     AND ($2::TEXT IS NULL OR level = $2)
     AND ($3::TEXT IS NULL OR difficulty = $3)
     AND ($4::TEXT IS NULL OR modality = $4)
@@ -515,6 +935,13 @@ func (q *Queries) ListQuestionsByFilters(ctx context.Context, arg ListQuestionsB
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -533,8 +960,10 @@ SELECT
     c.id as choice_id, c.choice_text, c.is_correct
 FROM questions q
 LEFT JOIN choices c ON q.id = c.question_id
+AND c.deleted_at IS NULL
 WHERE
-    ($1::INT IS NULL OR q.year = $1)
+    q.deleted_at IS NULL
+    AND ($1::INT IS NULL OR q.year = $1)
     AND ($2::TEXT IS NULL OR q.level = $2)
     AND ($3::TEXT IS NULL OR q.difficulty = $3)
     AND ($4::TEXT IS NULL OR q.modality = $4)
@@ -542,7 +971,7 @@ WHERE
     AND ($6::TEXT IS NULL OR q.field_of_study = $6)
     AND ($7::UUID IS NULL OR q.topic_id = $7)
     AND ($8::TEXT IS NULL OR q.position = $8)
-ORDER BY q.created_at DESC
+ORDER BY q.created_at DESC, q.id, c.position
 `
 
 type ListQuestionsByFiltersWithChoicesParams struct {
@@ -614,10 +1043,11 @@ func (q *Queries) ListQuestionsByFiltersWithChoices(ctx context.Context, arg Lis
 }
 
 const listQuestionsByLevel = `-- name: ListQuestionsByLevel :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     level = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -642,6 +1072,13 @@ func (q *Queries) ListQuestionsByLevel(ctx context.Context, level pgtype.Text) (
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -654,10 +1091,11 @@ func (q *Queries) ListQuestionsByLevel(ctx context.Context, level pgtype.Text) (
 }
 
 const listQuestionsByModality = `-- name: ListQuestionsByModality :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     modality = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -682,6 +1120,13 @@ func (q *Queries) ListQuestionsByModality(ctx context.Context, modality pgtype.T
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -694,10 +1139,11 @@ func (q *Queries) ListQuestionsByModality(ctx context.Context, modality pgtype.T
 }
 
 const listQuestionsByPracticeArea = `-- name: ListQuestionsByPracticeArea :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     practice_area = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -722,6 +1168,13 @@ func (q *Queries) ListQuestionsByPracticeArea(ctx context.Context, practiceArea
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -734,10 +1187,11 @@ func (q *Queries) ListQuestionsByPracticeArea(ctx context.Context, practiceArea
 }
 
 const listQuestionsByTopic = `-- name: ListQuestionsByTopic :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     topic_id = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -762,6 +1216,13 @@ func (q *Queries) ListQuestionsByTopic(ctx context.Context, topicID pgtype.UUID)
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -774,7 +1235,12 @@ func (q *Queries) ListQuestionsByTopic(ctx context.Context, topicID pgtype.UUID)
 }
 
 const listQuestionsByYear = `-- name: ListQuestionsByYear :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at FROM questions WHERE year = $1 ORDER BY created_at DESC
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
+FROM questions
+WHERE
+    year = $1
+    AND deleted_at IS NULL
+ORDER BY created_at DESC
 `
 
 func (q *Queries) ListQuestionsByYear(ctx context.Context, year int32) ([]Question, error) {
@@ -798,6 +1264,13 @@ func (q *Queries) ListQuestionsByYear(ctx context.Context, year int32) ([]Questi
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
 		); err != nil {
 			return nil, err
 		}
@@ -810,11 +1283,12 @@ func (q *Queries) ListQuestionsByYear(ctx context.Context, year int32) ([]Questi
 }
 
 const listQuestionsByYearAndLevel = `-- name: ListQuestionsByYearAndLevel :many
-SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+SELECT id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 FROM questions
 WHERE
     year = $1
     AND level = $2
+    AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
 
@@ -844,6 +1318,94 @@ func (q *Queries) ListQuestionsByYearAndLevel(ctx context.Context, arg ListQuest
 			&i.PracticeArea,
 			&i.FieldOfStudy,
 			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQuestionsForExport = `-- name: ListQuestionsForExport :many
+SELECT q.id, q.statement, q.year, q.topic_id, q.position, q.level, q.difficulty, q.modality, q.practice_area, q.field_of_study, q.created_at, q.review_status, q.deleted_at, q.normalized_statement, q.explanation, q.board, q.choice_count, q.external_id, t.name AS topic_name, s.name AS subject_name
+FROM questions q
+JOIN topics t ON q.topic_id = t.id
+JOIN subjects s ON t.subject_id = s.id
+WHERE
+    q.deleted_at IS NULL
+    AND ($1::UUID IS NULL OR q.id > $1)
+ORDER BY q.id
+LIMIT $2
+`
+
+type ListQuestionsForExportParams struct {
+	After     pgtype.UUID `json:"after"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListQuestionsForExportRow struct {
+	ID                  pgtype.UUID        `json:"id"`
+	Statement           string             `json:"statement"`
+	Year                int32              `json:"year"`
+	TopicID             pgtype.UUID        `json:"topic_id"`
+	Position            pgtype.Text        `json:"position"`
+	Level               pgtype.Text        `json:"level"`
+	Difficulty          pgtype.Text        `json:"difficulty"`
+	Modality            pgtype.Text        `json:"modality"`
+	PracticeArea        pgtype.Text        `json:"practice_area"`
+	FieldOfStudy        pgtype.Text        `json:"field_of_study"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	ReviewStatus        string             `json:"review_status"`
+	DeletedAt           pgtype.Timestamptz `json:"deleted_at"`
+	NormalizedStatement string             `json:"normalized_statement"`
+	Explanation         pgtype.Text        `json:"explanation"`
+	Board               pgtype.Text        `json:"board"`
+	ChoiceCount         pgtype.Int4        `json:"choice_count"`
+	ExternalID          pgtype.Text        `json:"external_id"`
+	TopicName           string             `json:"topic_name"`
+	SubjectName         string             `json:"subject_name"`
+}
+
+func (q *Queries) ListQuestionsForExport(ctx context.Context, arg ListQuestionsForExportParams) ([]ListQuestionsForExportRow, error) {
+	rows, err := q.db.Query(ctx, listQuestionsForExport, arg.After, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListQuestionsForExportRow{}
+	for rows.Next() {
+		var i ListQuestionsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Statement,
+			&i.Year,
+			&i.TopicID,
+			&i.Position,
+			&i.Level,
+			&i.Difficulty,
+			&i.Modality,
+			&i.PracticeArea,
+			&i.FieldOfStudy,
+			&i.CreatedAt,
+			&i.ReviewStatus,
+			&i.DeletedAt,
+			&i.NormalizedStatement,
+			&i.Explanation,
+			&i.Board,
+			&i.ChoiceCount,
+			&i.ExternalID,
+			&i.TopicName,
+			&i.SubjectName,
 		); err != nil {
 			return nil, err
 		}
@@ -861,6 +1423,7 @@ SELECT EXISTS (
         FROM questions
         WHERE
             statement = $1
+            AND deleted_at IS NULL
     ) AS exists
 `
 
@@ -871,6 +1434,25 @@ func (q *Queries) QuestionExistsByStatement(ctx context.Context, statement strin
 	return exists, err
 }
 
+const setQuestionExternalID = `-- name: SetQuestionExternalID :exec
+UPDATE questions
+SET
+    external_id = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+`
+
+type SetQuestionExternalIDParams struct {
+	ID         pgtype.UUID `json:"id"`
+	ExternalID pgtype.Text `json:"external_id"`
+}
+
+func (q *Queries) SetQuestionExternalID(ctx context.Context, arg SetQuestionExternalIDParams) error {
+	_, err := q.db.Exec(ctx, setQuestionExternalID, arg.ID, arg.ExternalID)
+	return err
+}
+
 const updateQuestion = `-- name: UpdateQuestion :one
 UPDATE questions
 SET
@@ -882,22 +1464,35 @@ SET
     difficulty = $7,
     modality = $8,
     practice_area = $9,
-    field_of_study = $10
+    field_of_study = $10,
+    normalized_statement = $11,
+    explanation = $12,
+    board = $13,
+    choice_count = $14,
+    review_status = CASE
+        WHEN review_status = 'changes_requested' THEN 'pending'
+        ELSE review_status
+    END
 WHERE
-    id = $1 RETURNING id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at
+    id = $1
+    AND deleted_at IS NULL RETURNING id, statement, year, topic_id, position, level, difficulty, modality, practice_area, field_of_study, created_at, review_status, deleted_at, normalized_statement, explanation, board, choice_count, external_id
 `
 
 type UpdateQuestionParams struct {
-	ID           pgtype.UUID `json:"id"`
-	Statement    string      `json:"statement"`
-	Year         int32       `json:"year"`
-	TopicID      pgtype.UUID `json:"topic_id"`
-	Position     pgtype.Text `json:"position"`
-	Level        pgtype.Text `json:"level"`
-	Difficulty   pgtype.Text `json:"difficulty"`
-	Modality     pgtype.Text `json:"modality"`
-	PracticeArea pgtype.Text `json:"practice_area"`
-	FieldOfStudy pgtype.Text `json:"field_of_study"`
+	ID                  pgtype.UUID `json:"id"`
+	Statement           string      `json:"statement"`
+	Year                int32       `json:"year"`
+	TopicID             pgtype.UUID `json:"topic_id"`
+	Position            pgtype.Text `json:"position"`
+	Level               pgtype.Text `json:"level"`
+	Difficulty          pgtype.Text `json:"difficulty"`
+	Modality            pgtype.Text `json:"modality"`
+	PracticeArea        pgtype.Text `json:"practice_area"`
+	FieldOfStudy        pgtype.Text `json:"field_of_study"`
+	NormalizedStatement string      `json:"normalized_statement"`
+	Explanation         pgtype.Text `json:"explanation"`
+	Board               pgtype.Text `json:"board"`
+	ChoiceCount         pgtype.Int4 `json:"choice_count"`
 }
 
 func (q *Queries) UpdateQuestion(ctx context.Context, arg UpdateQuestionParams) (Question, error) {
@@ -912,6 +1507,10 @@ func (q *Queries) UpdateQuestion(ctx context.Context, arg UpdateQuestionParams)
 		arg.Modality,
 		arg.PracticeArea,
 		arg.FieldOfStudy,
+		arg.NormalizedStatement,
+		arg.Explanation,
+		arg.Board,
+		arg.ChoiceCount,
 	)
 	var i Question
 	err := row.Scan(
@@ -926,6 +1525,16 @@ func (q *Queries) UpdateQuestion(ctx context.Context, arg UpdateQuestionParams)
 		&i.PracticeArea,
 		&i.FieldOfStudy,
 		&i.CreatedAt,
+		&i.ReviewStatus,
+		&i.DeletedAt,
+		&i.NormalizedStatement,
+		&i.Explanation,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
+		&i.Board,
+		&i.ChoiceCount,
+		&i.ExternalID,
 	)
 	return i, err
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const countSubjects = `-- name: CountSubjects :one
-SELECT COUNT(*) FROM subjects
+SELECT COUNT(*) FROM subjects WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountSubjects(ctx context.Context) (int64, error) {
@@ -23,7 +23,11 @@ func (q *Queries) CountSubjects(ctx context.Context) (int64, error) {
 }
 
 const countSubjectsByName = `-- name: CountSubjectsByName :one
-SELECT COUNT(*) FROM subjects WHERE name ILIKE '%' || $1 || '%'
+SELECT COUNT(*)
+FROM subjects
+WHERE
+    name ILIKE '%' || $1 || '%'
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) CountSubjectsByName(ctx context.Context, dollar_1 pgtype.Text) (int64, error) {
@@ -34,49 +38,40 @@ func (q *Queries) CountSubjectsByName(ctx context.Context, dollar_1 pgtype.Text)
 }
 
 const createSubject = `-- name: CreateSubject :one
-INSERT INTO subjects (name) VALUES ($1) RETURNING id, name
+INSERT INTO subjects (name) VALUES ($1) RETURNING id, name, deleted_at
 `
 
 func (q *Queries) CreateSubject(ctx context.Context, name string) (Subject, error) {
 	row := q.db.QueryRow(ctx, createSubject, name)
 	var i Subject
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.DeletedAt)
 	return i, err
 }
 
-const deleteSubject = `-- name: DeleteSubject :exec
-DELETE FROM subjects WHERE id = $1
-`
-
-func (q *Queries) DeleteSubject(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteSubject, id)
-	return err
-}
-
 const getSubject = `-- name: GetSubject :one
-SELECT id, name FROM subjects WHERE id = $1
+SELECT id, name, deleted_at FROM subjects WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSubject(ctx context.Context, id pgtype.UUID) (Subject, error) {
 	row := q.db.QueryRow(ctx, getSubject, id)
 	var i Subject
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.DeletedAt)
 	return i, err
 }
 
 const getSubjectByName = `-- name: GetSubjectByName :one
-SELECT id, name FROM subjects WHERE name = $1
+SELECT id, name, deleted_at FROM subjects WHERE name = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetSubjectByName(ctx context.Context, name string) (Subject, error) {
 	row := q.db.QueryRow(ctx, getSubjectByName, name)
 	var i Subject
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.DeletedAt)
 	return i, err
 }
 
 const listSubjects = `-- name: ListSubjects :many
-SELECT id, name FROM subjects ORDER BY name
+SELECT id, name, deleted_at FROM subjects WHERE deleted_at IS NULL ORDER BY name
 `
 
 func (q *Queries) ListSubjects(ctx context.Context) ([]Subject, error) {
@@ -88,7 +83,7 @@ func (q *Queries) ListSubjects(ctx context.Context) ([]Subject, error) {
 	items := []Subject{}
 	for rows.Next() {
 		var i Subject
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.DeletedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -100,10 +95,11 @@ func (q *Queries) ListSubjects(ctx context.Context) ([]Subject, error) {
 }
 
 const listSubjectsByName = `-- name: ListSubjectsByName :many
-SELECT id, name
+SELECT id, name, deleted_at
 FROM subjects
 WHERE
     name ILIKE '%' || $1 || '%'
+    AND deleted_at IS NULL
 ORDER BY name
 `
 
@@ -116,7 +112,7 @@ func (q *Queries) ListSubjectsByName(ctx context.Context, dollar_1 pgtype.Text)
 	items := []Subject{}
 	for rows.Next() {
 		var i Subject
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		if err := rows.Scan(&i.ID, &i.Name, &i.DeletedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -128,7 +124,12 @@ func (q *Queries) ListSubjectsByName(ctx context.Context, dollar_1 pgtype.Text)
 }
 
 const updateSubject = `-- name: UpdateSubject :one
-UPDATE subjects SET name = $2 WHERE id = $1 RETURNING id, name
+UPDATE subjects
+SET
+    name = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL RETURNING id, name, deleted_at
 `
 
 type UpdateSubjectParams struct {
@@ -139,6 +140,6 @@ type UpdateSubjectParams struct {
 func (q *Queries) UpdateSubject(ctx context.Context, arg UpdateSubjectParams) (Subject, error) {
 	row := q.db.QueryRow(ctx, updateSubject, arg.ID, arg.Name)
 	var i Subject
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.DeletedAt)
 	return i, err
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const createTopic = `-- name: CreateTopic :one
-INSERT INTO topics (subject_id, name) VALUES ($1, $2) RETURNING id, subject_id, name
+INSERT INTO topics (subject_id, name) VALUES ($1, $2) RETURNING id, subject_id, name, deleted_at
 `
 
 type CreateTopicParams struct {
@@ -23,32 +23,59 @@ type CreateTopicParams struct {
 func (q *Queries) CreateTopic(ctx context.Context, arg CreateTopicParams) (Topic, error) {
 	row := q.db.QueryRow(ctx, createTopic, arg.SubjectID, arg.Name)
 	var i Topic
-	err := row.Scan(&i.ID, &i.SubjectID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.SubjectID,
+		&i.Name,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
-const deleteTopic = `-- name: DeleteTopic :exec
-DELETE FROM topics WHERE id = $1
+const getTopic = `-- name: GetTopic :one
+SELECT id, subject_id, name, deleted_at FROM topics WHERE id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteTopic(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteTopic, id)
-	return err
+func (q *Queries) GetTopic(ctx context.Context, id pgtype.UUID) (Topic, error) {
+	row := q.db.QueryRow(ctx, getTopic, id)
+	var i Topic
+	err := row.Scan(
+		&i.ID,
+		&i.SubjectID,
+		&i.Name,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
-const getTopic = `-- name: GetTopic :one
-SELECT id, subject_id, name FROM topics WHERE id = $1
+const getTopicByName = `-- name: GetTopicByName :one
+SELECT id, subject_id, name, deleted_at
+FROM topics
+WHERE
+    subject_id = $1
+    AND name = $2
+    AND deleted_at IS NULL
 `
 
-func (q *Queries) GetTopic(ctx context.Context, id pgtype.UUID) (Topic, error) {
-	row := q.db.QueryRow(ctx, getTopic, id)
+type GetTopicByNameParams struct {
+	SubjectID pgtype.UUID `json:"subject_id"`
+	Name      string      `json:"name"`
+}
+
+func (q *Queries) GetTopicByName(ctx context.Context, arg GetTopicByNameParams) (Topic, error) {
+	row := q.db.QueryRow(ctx, getTopicByName, arg.SubjectID, arg.Name)
 	var i Topic
-	err := row.Scan(&i.ID, &i.SubjectID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.SubjectID,
+		&i.Name,
+		&i.DeletedAt,
+	)
 	return i, err
 }
 
 const listTopics = `-- name: ListTopics :many
-SELECT id, subject_id, name FROM topics ORDER BY name
+SELECT id, subject_id, name, deleted_at FROM topics WHERE deleted_at IS NULL ORDER BY name
 `
 
 func (q *Queries) ListTopics(ctx context.Context) ([]Topic, error) {
@@ -60,7 +87,12 @@ func (q *Queries) ListTopics(ctx context.Context) ([]Topic, error) {
 	items := []Topic{}
 	for rows.Next() {
 		var i Topic
-		if err := rows.Scan(&i.ID, &i.SubjectID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubjectID,
+			&i.Name,
+			&i.DeletedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -72,7 +104,12 @@ func (q *Queries) ListTopics(ctx context.Context) ([]Topic, error) {
 }
 
 const listTopicsBySubject = `-- name: ListTopicsBySubject :many
-SELECT id, subject_id, name FROM topics WHERE subject_id = $1 ORDER BY name
+SELECT id, subject_id, name, deleted_at
+FROM topics
+WHERE
+    subject_id = $1
+    AND deleted_at IS NULL
+ORDER BY name
 `
 
 func (q *Queries) ListTopicsBySubject(ctx context.Context, subjectID pgtype.UUID) ([]Topic, error) {
@@ -84,7 +121,12 @@ func (q *Queries) ListTopicsBySubject(ctx context.Context, subjectID pgtype.UUID
 	items := []Topic{}
 	for rows.Next() {
 		var i Topic
-		if err := rows.Scan(&i.ID, &i.SubjectID, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubjectID,
+			&i.Name,
+			&i.DeletedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -101,7 +143,8 @@ SET
     subject_id = $2,
     name = $3
 WHERE
-    id = $1 RETURNING id, subject_id, name
+    id = $1
+    AND deleted_at IS NULL RETURNING id, subject_id, name, deleted_at
 `
 
 type UpdateTopicParams struct {
@@ -113,6 +156,11 @@ type UpdateTopicParams struct {
 func (q *Queries) UpdateTopic(ctx context.Context, arg UpdateTopicParams) (Topic, error) {
 	row := q.db.QueryRow(ctx, updateTopic, arg.ID, arg.SubjectID, arg.Name)
 	var i Topic
-	err := row.Scan(&i.ID, &i.SubjectID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.SubjectID,
+		&i.Name,
+		&i.DeletedAt,
+	)
 	return i, err
 }
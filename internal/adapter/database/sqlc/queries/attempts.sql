@@ -0,0 +1,50 @@
+-- name: CreateExamAttempt :one
+INSERT INTO
+    exam_attempts (
+        exam_id,
+        candidate,
+        score,
+        total
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: CreateAttemptAnswer :exec
+INSERT INTO
+    attempt_answers (
+        attempt_id,
+        number,
+        question_id,
+        choice_id,
+        is_correct
+    )
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListExamAttempts :many
+SELECT * FROM exam_attempts WHERE exam_id = $1 ORDER BY created_at;
+
+-- name: ListQuestionResponses :many
+SELECT aa.question_id, aa.choice_id, aa.is_correct, ea.score, ea.total
+FROM attempt_answers aa
+    JOIN exam_attempts ea ON aa.attempt_id = ea.id
+WHERE
+    aa.question_id = ANY(sqlc.arg('ids')::UUID[])
+ORDER BY aa.question_id;
+
+-- name: UpsertQuestionStatistics :one
+INSERT INTO
+    question_statistics (
+        question_id,
+        responses,
+        p_correct,
+        discrimination
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (question_id) DO UPDATE
+SET
+    responses = EXCLUDED.responses,
+    p_correct = EXCLUDED.p_correct,
+    discrimination = EXCLUDED.discrimination,
+    updated_at = CURRENT_TIMESTAMP RETURNING *;
+
+-- name: GetQuestionStatistics :one
+SELECT * FROM question_statistics WHERE question_id = $1;
\ No newline at end of file
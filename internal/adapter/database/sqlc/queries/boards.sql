@@ -0,0 +1,22 @@
+-- name: CreateBoard :one
+INSERT INTO boards (name, choice_count) VALUES ($1, $2) RETURNING *;
+
+-- name: GetBoard :one
+SELECT * FROM boards WHERE id = $1;
+
+-- name: GetBoardByName :one
+SELECT * FROM boards WHERE name = $1;
+
+-- name: ListBoards :many
+SELECT * FROM boards ORDER BY name;
+
+-- name: UpdateBoard :one
+UPDATE boards
+SET
+    name = $2,
+    choice_count = $3
+WHERE
+    id = $1 RETURNING *;
+
+-- name: DeleteBoard :exec
+DELETE FROM boards WHERE id = $1
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- name: BulkUpdateQuestions :many
+UPDATE questions
+SET
+    topic_id = COALESCE(sqlc.narg('topic_id'), topic_id),
+    year = COALESCE(sqlc.narg('year'), year),
+    position = COALESCE(sqlc.narg('position'), position),
+    level = COALESCE(sqlc.narg('level'), level),
+    difficulty = COALESCE(sqlc.narg('difficulty'), difficulty),
+    modality = COALESCE(sqlc.narg('modality'), modality),
+    practice_area = COALESCE(sqlc.narg('practice_area'), practice_area),
+    field_of_study = COALESCE(sqlc.narg('field_of_study'), field_of_study),
+    board = COALESCE(sqlc.narg('board'), board),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ANY(sqlc.arg('ids')::UUID[])
+    AND deleted_at IS NULL RETURNING *;
+
+-- name: CreateBulkUpdate :one
+INSERT INTO
+    bulk_updates (
+        author,
+        selection,
+        changes,
+        question_ids
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: ListBulkUpdates :many
+SELECT * FROM bulk_updates ORDER BY created_at DESC;
\ No newline at end of file
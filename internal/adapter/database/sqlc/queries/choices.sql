@@ -3,15 +3,40 @@ INSERT INTO
     choices (
         question_id,
         choice_text,
-        is_correct
+        is_correct,
+        position
     )
-VALUES ($1, $2, $3) RETURNING *;
+VALUES (
+        $1,
+        $2,
+        $3,
+        (
+            SELECT COALESCE(MAX(position) + 1, 0)
+            FROM choices
+            WHERE
+                question_id = $1
+                AND deleted_at IS NULL
+        )
+    ) RETURNING *;
 
 -- name: GetChoice :one
-SELECT * FROM choices WHERE id = $1;
+SELECT * FROM choices WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: ListChoicesByQuestion :many
-SELECT * FROM choices WHERE question_id = $1 ORDER BY id;
+SELECT *
+FROM choices
+WHERE
+    question_id = $1
+    AND deleted_at IS NULL
+ORDER BY position, id;
+
+-- name: ListChoicesByQuestions :many
+SELECT *
+FROM choices
+WHERE
+    question_id = ANY(sqlc.arg('ids')::UUID[])
+    AND deleted_at IS NULL
+ORDER BY question_id, position, id;
 
 -- name: UpdateChoice :one
 UPDATE choices
@@ -19,7 +44,13 @@ SET
     choice_text = $2,
     is_correct = $3
 WHERE
-    id = $1 RETURNING *;
+    id = $1
+    AND deleted_at IS NULL RETURNING *;
 
--- name: DeleteChoice :exec
-DELETE FROM choices WHERE id = $1;
\ No newline at end of file
+-- name: UpdateChoicePosition :exec
+UPDATE choices
+SET
+    position = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
\ No newline at end of file
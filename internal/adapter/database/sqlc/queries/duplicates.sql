@@ -0,0 +1,52 @@
+-- name: FindSimilarQuestions :many
+SELECT
+    q.id,
+    q.statement,
+    q.topic_id,
+    similarity(
+        q.normalized_statement,
+        sqlc.arg('normalized_statement')::TEXT
+    )::REAL as score
+FROM questions q
+WHERE
+    q.deleted_at IS NULL
+    AND q.normalized_statement % sqlc.arg('normalized_statement')::TEXT
+    AND similarity(
+        q.normalized_statement,
+        sqlc.arg('normalized_statement')::TEXT
+    ) >= sqlc.arg('threshold')::REAL
+    AND (
+        sqlc.narg('exclude_id')::UUID IS NULL
+        OR q.id <> sqlc.narg('exclude_id')
+    )
+ORDER BY score DESC
+LIMIT 5;
+
+-- name: ListSimilarQuestionPairs :many
+SELECT
+    a.id as question_id,
+    b.id as similar_id,
+    similarity(
+        a.normalized_statement,
+        b.normalized_statement
+    )::REAL as score
+FROM questions a
+    JOIN questions b ON a.id < b.id
+    AND a.normalized_statement % b.normalized_statement
+WHERE
+    a.deleted_at IS NULL
+    AND b.deleted_at IS NULL
+    AND similarity(
+        a.normalized_statement,
+        b.normalized_statement
+    ) >= sqlc.arg('threshold')::REAL
+ORDER BY score DESC
+LIMIT sqlc.arg('max_pairs');
+
+-- name: ListQuestionsByIDs :many
+SELECT *
+FROM questions
+WHERE
+    id = ANY(sqlc.arg('ids')::UUID[])
+    AND deleted_at IS NULL
+ORDER BY created_at;
\ No newline at end of file
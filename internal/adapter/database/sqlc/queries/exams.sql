@@ -0,0 +1,38 @@
+-- name: CreateExam :one
+INSERT INTO exams (created_by, filters) VALUES ($1, $2) RETURNING *;
+
+-- name: GetExam :one
+SELECT * FROM exams WHERE id = $1;
+
+-- name: ListExams :many
+SELECT * FROM exams ORDER BY created_at DESC;
+
+-- name: CreateExamQuestion :exec
+INSERT INTO
+    exam_questions (
+        exam_id,
+        number,
+        question_id,
+        revision_id,
+        subject_name,
+        answer,
+        choice_order
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListExamQuestions :many
+SELECT
+    eq.exam_id,
+    eq.number,
+    eq.question_id,
+    eq.revision_id,
+    eq.subject_name,
+    eq.answer,
+    eq.choice_order,
+    r.revision,
+    r.snapshot
+FROM exam_questions eq
+JOIN question_revisions r ON eq.revision_id = r.id
+WHERE
+    eq.exam_id = $1
+ORDER BY eq.number;
\ No newline at end of file
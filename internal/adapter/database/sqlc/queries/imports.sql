@@ -0,0 +1,83 @@
+-- name: CreateImportJob :one
+INSERT INTO
+    import_jobs (
+        filename,
+        options,
+        created_by
+    )
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: CreateImportJobFile :exec
+INSERT INTO import_job_files (job_id, content) VALUES ($1, $2);
+
+-- name: GetImportJobFile :one
+SELECT content FROM import_job_files WHERE job_id = $1;
+
+-- name: DeleteImportJobFile :exec
+DELETE FROM import_job_files WHERE job_id = $1;
+
+-- name: GetImportJob :one
+SELECT * FROM import_jobs WHERE id = $1;
+
+-- name: ListImportJobs :many
+SELECT * FROM import_jobs ORDER BY created_at DESC;
+
+-- name: ClaimImportJob :one
+UPDATE import_jobs
+SET
+    status = 'running',
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP)
+WHERE
+    id = (
+        SELECT id
+        FROM import_jobs
+        WHERE
+            status = 'queued'
+        ORDER BY created_at
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    ) RETURNING *;
+
+-- name: RequeueRunningImportJobs :execrows
+UPDATE import_jobs SET status = 'queued' WHERE status = 'running';
+
+-- name: UpdateImportJobProgress :exec
+UPDATE import_jobs
+SET
+    processed = $2,
+    created = $3,
+    ignored = $4,
+    failed = $5,
+    report = $6
+WHERE
+    id = $1;
+
+-- name: FinishImportJob :one
+UPDATE import_jobs
+SET
+    status = $2,
+    processed = $3,
+    created = $4,
+    ignored = $5,
+    failed = $6,
+    report = $7,
+    error = $8,
+    finished_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
+-- name: RequestImportJobCancel :one
+UPDATE import_jobs
+SET
+    cancel_requested = TRUE,
+    status = CASE
+        WHEN status = 'queued' THEN 'cancelled'
+        ELSE status
+    END,
+    finished_at = CASE
+        WHEN status = 'queued' THEN CURRENT_TIMESTAMP
+        ELSE finished_at
+    END
+WHERE
+    id = $1
+    AND status IN ('queued', 'running') RETURNING *;
\ No newline at end of file
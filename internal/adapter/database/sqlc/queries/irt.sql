@@ -0,0 +1,116 @@
+-- name: ListCalibrationResponses :many
+SELECT aa.attempt_id, aa.question_id, aa.is_correct
+FROM attempt_answers aa
+    JOIN questions q ON aa.question_id = q.id
+WHERE
+    q.deleted_at IS NULL
+ORDER BY aa.attempt_id;
+
+-- name: UpsertItemParameters :one
+INSERT INTO
+    item_parameters (
+        question_id,
+        model,
+        discrimination,
+        difficulty,
+        guessing,
+        responses
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (question_id) DO UPDATE
+SET
+    model = EXCLUDED.model,
+    discrimination = EXCLUDED.discrimination,
+    difficulty = EXCLUDED.difficulty,
+    guessing = EXCLUDED.guessing,
+    responses = EXCLUDED.responses,
+    calibrated_at = CURRENT_TIMESTAMP RETURNING *;
+
+-- name: GetItemParameters :one
+SELECT * FROM item_parameters WHERE question_id = $1;
+
+-- name: ListItemParameters :many
+SELECT * FROM item_parameters ORDER BY difficulty;
+
+-- name: ListCATItems :many
+SELECT ip.question_id, ip.discrimination, ip.difficulty, ip.guessing
+FROM item_parameters ip
+    JOIN questions q ON ip.question_id = q.id
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    q.review_status = 'approved'
+    AND q.deleted_at IS NULL
+    AND (sqlc.narg('subject_id')::uuid IS NULL OR t.subject_id = sqlc.narg('subject_id'))
+    AND NOT EXISTS (
+        SELECT 1
+        FROM question_suspensions sp
+        WHERE
+            sp.question_id = q.id
+    )
+    AND EXISTS (
+        SELECT 1
+        FROM question_integrity qi
+        WHERE
+            qi.question_id = q.id
+            AND NOT qi.empty_statement
+            AND qi.choices = qi.expected_choices
+            AND qi.correct_choices = 1
+            AND qi.empty_choices = 0
+    );
+
+-- name: CreateCATSession :one
+INSERT INTO
+    cat_sessions (
+        candidate,
+        subject_id,
+        max_items,
+        target_se
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: GetCATSession :one
+SELECT * FROM cat_sessions WHERE id = $1;
+
+-- name: GetCATSessionForUpdate :one
+SELECT * FROM cat_sessions WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateCATSession :one
+UPDATE cat_sessions
+SET
+    status = $2,
+    theta = $3,
+    se = $4,
+    finished_at = $5
+WHERE
+    id = $1 RETURNING *;
+
+-- name: CreateCATResponse :exec
+INSERT INTO
+    cat_responses (
+        session_id,
+        number,
+        question_id
+    )
+VALUES ($1, $2, $3);
+
+-- name: AnswerCATResponse :exec
+UPDATE cat_responses
+SET
+    choice_id = $3,
+    is_correct = $4,
+    theta = $5,
+    se = $6,
+    answered_at = CURRENT_TIMESTAMP
+WHERE
+    session_id = $1
+    AND number = $2;
+
+-- name: ListCATResponses :many
+SELECT
+    cr.session_id, cr.number, cr.question_id, cr.choice_id, cr.is_correct, cr.theta, cr.se, cr.answered_at,
+    ip.discrimination, ip.difficulty, ip.guessing
+FROM cat_responses cr
+    JOIN item_parameters ip ON cr.question_id = ip.question_id
+WHERE
+    cr.session_id = $1
+ORDER BY cr.number;
\ No newline at end of file
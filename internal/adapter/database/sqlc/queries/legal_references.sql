@@ -0,0 +1,42 @@
+-- name: CreateLegalReference :one
+INSERT INTO
+    legal_references (
+        question_id,
+        law,
+        article,
+        paragraph
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: ListLegalReferencesByQuestion :many
+SELECT *
+FROM legal_references
+WHERE
+    question_id = $1
+ORDER BY law, article, paragraph;
+
+-- name: ListLegalReferencesByQuestions :many
+SELECT *
+FROM legal_references
+WHERE
+    question_id = ANY(sqlc.arg('ids')::UUID[])
+ORDER BY question_id, law, article, paragraph;
+
+-- name: DeleteLegalReference :execrows
+DELETE FROM legal_references WHERE id = $1 AND question_id = $2;
+
+-- name: ListQuestionsByLegalReference :many
+SELECT q.*
+FROM questions q
+WHERE
+    q.deleted_at IS NULL
+    AND EXISTS (
+        SELECT 1
+        FROM legal_references lr
+        WHERE
+            lr.question_id = q.id
+            AND lower(lr.law) = lower(sqlc.arg('law'))
+            AND (sqlc.narg('article')::TEXT IS NULL OR lr.article = sqlc.narg('article'))
+            AND (sqlc.narg('paragraph')::TEXT IS NULL OR lr.paragraph = sqlc.narg('paragraph'))
+    )
+ORDER BY q.created_at;
\ No newline at end of file
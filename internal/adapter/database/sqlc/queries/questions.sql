@@ -9,7 +9,12 @@ INSERT INTO
         difficulty,
         modality,
         practice_area,
-        field_of_study
+        field_of_study,
+        normalized_statement,
+        explanation,
+        board,
+        choice_count,
+        external_id
     )
 VALUES (
         $1,
@@ -20,7 +25,49 @@ VALUES (
         $6,
         $7,
         $8,
-        $9
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14
+    ) RETURNING *;
+
+-- name: CreateQuestionWithID :one
+INSERT INTO
+    questions (
+        id,
+        statement,
+        year,
+        topic_id,
+        position,
+        level,
+        difficulty,
+        modality,
+        practice_area,
+        field_of_study,
+        normalized_statement,
+        explanation,
+        board,
+        choice_count,
+        external_id
+    )
+VALUES (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15
     ) RETURNING *;
 
 -- name: QuestionExistsByStatement :one
@@ -29,13 +76,26 @@ SELECT EXISTS (
         FROM questions
         WHERE
             statement = $1
+            AND deleted_at IS NULL
     ) AS exists;
 
 -- name: GetQuestion :one
-SELECT * FROM questions WHERE id = $1;
+SELECT * FROM questions WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetQuestionForUpdate :one
+SELECT *
+FROM questions
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: ListQuestions :many
-SELECT * FROM questions ORDER BY created_at DESC;
+SELECT *
+FROM questions
+WHERE
+    deleted_at IS NULL
+ORDER BY created_at DESC;
 
 -- name: UpdateQuestion :one
 UPDATE questions
@@ -48,71 +108,111 @@ SET
     difficulty = $7,
     modality = $8,
     practice_area = $9,
-    field_of_study = $10
+    field_of_study = $10,
+    normalized_statement = $11,
+    explanation = $12,
+    board = $13,
+    choice_count = $14,
+    review_status = CASE
+        WHEN review_status = 'changes_requested' THEN 'pending'
+        ELSE review_status
+    END
 WHERE
-    id = $1 RETURNING *;
-
--- name: DeleteQuestion :exec
-DELETE FROM questions WHERE id = $1;
+    id = $1
+    AND deleted_at IS NULL RETURNING *;
 
 -- name: ListQuestionsByTopic :many
 SELECT *
 FROM questions
 WHERE
     topic_id = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestions :one
-SELECT COUNT(*) FROM questions;
+SELECT COUNT(*) FROM questions WHERE deleted_at IS NULL;
 
 -- name: CountQuestionsByTopic :one
-SELECT COUNT(*) FROM questions WHERE topic_id = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    topic_id = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByYear :many
-SELECT * FROM questions WHERE year = $1 ORDER BY created_at DESC;
+SELECT *
+FROM questions
+WHERE
+    year = $1
+    AND deleted_at IS NULL
+ORDER BY created_at DESC;
 
 -- name: CountQuestionsByYear :one
-SELECT COUNT(*) FROM questions WHERE year = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    year = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByLevel :many
 SELECT *
 FROM questions
 WHERE
     level = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestionsByLevel :one
-SELECT COUNT(*) FROM questions WHERE level = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    level = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByModality :many
 SELECT *
 FROM questions
 WHERE
     modality = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestionsByModality :one
-SELECT COUNT(*) FROM questions WHERE modality = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    modality = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByPracticeArea :many
 SELECT *
 FROM questions
 WHERE
     practice_area = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestionsByPracticeArea :one
-SELECT COUNT(*) FROM questions WHERE practice_area = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    practice_area = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByFieldOfStudy :many
 SELECT *
 FROM questions
 WHERE
     field_of_study = $1
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestionsByFieldOfStudy :one
-SELECT COUNT(*) FROM questions WHERE field_of_study = $1;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    field_of_study = $1
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByYearAndLevel :many
 SELECT *
@@ -120,16 +220,23 @@ FROM questions
 WHERE
     year = $1
     AND level = $2
+    AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
 -- name: CountQuestionsByYearAndLevel :one
-SELECT COUNT(*) FROM questions WHERE year = $1 AND level = $2;
+SELECT COUNT(*)
+FROM questions
+WHERE
+    year = $1
+    AND level = $2
+    AND deleted_at IS NULL;
 
 -- name: ListQuestionsByFilters :many
 SELECT *
 FROM questions
 WHERE
-    (sqlc.narg('year')::INT IS NULL OR year = sqlc.narg('year'))
+    deleted_at IS NULL
+    AND (sqlc.narg('year')::INT IS NULL OR year = sqlc.narg('year'))
     AND (sqlc.narg('level')::TEXT IS NULL OR level = sqlc.narg('level'))
     AND (sqlc.narg('difficulty')::TEXT IS NULL OR difficulty = sqlc.narg('difficulty'))
     AND (sqlc.narg('modality')::TEXT IS NULL OR modality = sqlc.narg('modality'))
@@ -144,7 +251,8 @@ ORDER BY created_at DESC;
 SELECT COUNT(*)
 FROM questions
 WHERE
-    (sqlc.narg('year')::INT IS NULL OR year = sqlc.narg('year'))
+    deleted_at IS NULL
+    AND (sqlc.narg('year')::INT IS NULL OR year = sqlc.narg('year'))
     AND (sqlc.narg('level')::TEXT IS NULL OR level = sqlc.narg('level'))
     AND (sqlc.narg('difficulty')::TEXT IS NULL OR difficulty = sqlc.narg('difficulty'))
     AND (sqlc.narg('modality')::TEXT IS NULL OR modality = sqlc.narg('modality'))
@@ -160,8 +268,10 @@ SELECT
     c.id as choice_id, c.choice_text, c.is_correct
 FROM questions q
 LEFT JOIN choices c ON q.id = c.question_id
+AND c.deleted_at IS NULL
 WHERE
-    (sqlc.narg('year')::INT IS NULL OR q.year = sqlc.narg('year'))
+    q.deleted_at IS NULL
+    AND (sqlc.narg('year')::INT IS NULL OR q.year = sqlc.narg('year'))
     AND (sqlc.narg('level')::TEXT IS NULL OR q.level = sqlc.narg('level'))
     AND (sqlc.narg('difficulty')::TEXT IS NULL OR q.difficulty = sqlc.narg('difficulty'))
     AND (sqlc.narg('modality')::TEXT IS NULL OR q.modality = sqlc.narg('modality'))
@@ -169,7 +279,7 @@ WHERE
     AND (sqlc.narg('field_of_study')::TEXT IS NULL OR q.field_of_study = sqlc.narg('field_of_study'))
     AND (sqlc.narg('topic_id')::UUID IS NULL OR q.topic_id = sqlc.narg('topic_id'))
     AND (sqlc.narg('position')::TEXT IS NULL OR q.position = sqlc.narg('position'))
-ORDER BY q.created_at DESC;
+ORDER BY q.created_at DESC, q.id, c.position;
 
 -- name: GetQuestionsForExam :many
 SELECT 
@@ -181,6 +291,8 @@ JOIN topics t ON q.topic_id = t.id
 JOIN subjects s ON t.subject_id = s.id
 WHERE 
     s.id = $1 
+    AND q.review_status = 'approved'
+    AND q.deleted_at IS NULL
     AND (sqlc.narg('topic_id')::uuid IS NULL OR q.topic_id = sqlc.narg('topic_id'))
     AND (sqlc.narg('position')::text IS NULL OR q.position = sqlc.narg('position'))
     AND (sqlc.narg('level')::text IS NULL OR q.level = sqlc.narg('level'))
@@ -189,6 +301,35 @@ WHERE
     AND (sqlc.narg('field_of_study')::text IS NULL OR q.field_of_study = sqlc.narg('field_of_study'))
     AND (sqlc.narg('min_year')::int IS NULL OR q.year >= sqlc.narg('min_year'))
     AND (sqlc.narg('max_year')::int IS NULL OR q.year <= sqlc.narg('max_year'))
+    AND (sqlc.narg('board')::text IS NULL OR q.board = sqlc.narg('board'))
+    AND (
+        (sqlc.narg('min_correct_rate')::float8 IS NULL AND sqlc.narg('max_correct_rate')::float8 IS NULL)
+        OR EXISTS (
+            SELECT 1
+            FROM question_statistics qs
+            WHERE
+                qs.question_id = q.id
+                AND qs.responses >= COALESCE(sqlc.narg('min_responses')::int, 1)
+                AND qs.p_correct >= COALESCE(sqlc.narg('min_correct_rate'), 0)
+                AND qs.p_correct <= COALESCE(sqlc.narg('max_correct_rate'), 1)
+        )
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM question_suspensions sp
+        WHERE
+            sp.question_id = q.id
+    )
+    AND EXISTS (
+        SELECT 1
+        FROM question_integrity qi
+        WHERE
+            qi.question_id = q.id
+            AND NOT qi.empty_statement
+            AND qi.choices = qi.expected_choices
+            AND qi.correct_choices = 1
+            AND qi.empty_choices = 0
+    )
 ORDER BY RANDOM()
 LIMIT $2;
 
@@ -199,6 +340,8 @@ JOIN topics t ON q.topic_id = t.id
 JOIN subjects s ON t.subject_id = s.id
 WHERE 
     s.id = $1 
+    AND q.review_status = 'approved'
+    AND q.deleted_at IS NULL
     AND (sqlc.narg('topic_id')::uuid IS NULL OR q.topic_id = sqlc.narg('topic_id'))
     AND (sqlc.narg('position')::text IS NULL OR q.position = sqlc.narg('position'))
     AND (sqlc.narg('level')::text IS NULL OR q.level = sqlc.narg('level'))
@@ -206,4 +349,95 @@ WHERE
     AND (sqlc.narg('modality')::text IS NULL OR q.modality = sqlc.narg('modality'))
     AND (sqlc.narg('field_of_study')::text IS NULL OR q.field_of_study = sqlc.narg('field_of_study'))
     AND (sqlc.narg('min_year')::int IS NULL OR q.year >= sqlc.narg('min_year'))
-    AND (sqlc.narg('max_year')::int IS NULL OR q.year <= sqlc.narg('max_year'));
\ No newline at end of file
+    AND (sqlc.narg('max_year')::int IS NULL OR q.year <= sqlc.narg('max_year'))
+    AND (sqlc.narg('board')::text IS NULL OR q.board = sqlc.narg('board'))
+    AND (
+        (sqlc.narg('min_correct_rate')::float8 IS NULL AND sqlc.narg('max_correct_rate')::float8 IS NULL)
+        OR EXISTS (
+            SELECT 1
+            FROM question_statistics qs
+            WHERE
+                qs.question_id = q.id
+                AND qs.responses >= COALESCE(sqlc.narg('min_responses')::int, 1)
+                AND qs.p_correct >= COALESCE(sqlc.narg('min_correct_rate'), 0)
+                AND qs.p_correct <= COALESCE(sqlc.narg('max_correct_rate'), 1)
+        )
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM question_suspensions sp
+        WHERE
+            sp.question_id = q.id
+    )
+    AND EXISTS (
+        SELECT 1
+        FROM question_integrity qi
+        WHERE
+            qi.question_id = q.id
+            AND NOT qi.empty_statement
+            AND qi.choices = qi.expected_choices
+            AND qi.correct_choices = 1
+            AND qi.empty_choices = 0
+    );
+
+-- name: GetQuestionIntegrity :one
+SELECT * FROM question_integrity WHERE question_id = $1;
+
+-- name: ListQuestionIntegrityIssues :many
+SELECT q.id, q.statement, q.review_status, qi.expected_choices, qi.choices, qi.correct_choices, qi.empty_choices, qi.empty_statement
+FROM questions q
+    JOIN question_integrity qi ON qi.question_id = q.id
+WHERE
+    q.deleted_at IS NULL
+    AND (
+        qi.empty_statement
+        OR qi.choices <> qi.expected_choices
+        OR qi.correct_choices <> 1
+        OR qi.empty_choices > 0
+    )
+ORDER BY q.created_at;
+
+-- name: ListQuestionsForExport :many
+SELECT q.*, t.name AS topic_name, s.name AS subject_name
+FROM questions q
+JOIN topics t ON q.topic_id = t.id
+JOIN subjects s ON t.subject_id = s.id
+WHERE
+    q.deleted_at IS NULL
+    AND (sqlc.narg('after')::UUID IS NULL OR q.id > sqlc.narg('after'))
+ORDER BY q.id
+LIMIT sqlc.arg('batch_size');
+
+-- name: GetQuestionIDByExternalID :one
+SELECT id
+FROM questions
+WHERE
+    external_id = $1
+    AND deleted_at IS NULL;
+
+-- name: ListQuestionIDsMissingExternalIDs :many
+SELECT q.id
+FROM questions q
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    q.external_id IS NOT NULL
+    AND NOT (q.external_id = ANY(sqlc.arg('external_ids')::TEXT[]))
+    AND q.deleted_at IS NULL
+    AND (
+        t.subject_id = ANY(sqlc.arg('subject_ids')::UUID[])
+        OR t.subject_id IN (
+            SELECT subject_id
+            FROM topics
+            WHERE
+                id = ANY(sqlc.arg('topic_ids')::UUID[])
+        )
+    )
+ORDER BY q.id;
+
+-- name: SetQuestionExternalID :exec
+UPDATE questions
+SET
+    external_id = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,64 @@
+-- name: CreateQuestionReport :one
+INSERT INTO
+    question_reports (
+        question_id,
+        category,
+        comment,
+        reporter
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: GetQuestionReportForUpdate :one
+SELECT * FROM question_reports WHERE id = $1 FOR UPDATE;
+
+-- name: GetQuestionReport :one
+SELECT * FROM question_reports WHERE id = $1;
+
+-- name: ListQuestionReports :many
+SELECT *
+FROM question_reports
+WHERE
+    (
+        (sqlc.narg('status')::TEXT IS NULL AND status IN ('open', 'in_review'))
+        OR status = sqlc.narg('status')
+    )
+    AND (sqlc.narg('category')::TEXT IS NULL OR category = sqlc.narg('category'))
+    AND (sqlc.narg('question_id')::UUID IS NULL OR question_id = sqlc.narg('question_id'))
+ORDER BY created_at;
+
+-- name: ListReportsByQuestion :many
+SELECT * FROM question_reports WHERE question_id = $1 ORDER BY created_at;
+
+-- name: UpdateQuestionReport :one
+UPDATE question_reports
+SET
+    status = $2,
+    resolution = $3,
+    moderator = $4,
+    closed_at = $5
+WHERE
+    id = $1 RETURNING *;
+
+-- name: CountOpenReports :one
+SELECT COUNT(*)
+FROM question_reports
+WHERE
+    question_id = $1
+    AND status IN ('open', 'in_review');
+
+-- name: SuspendQuestion :one
+INSERT INTO
+    question_suspensions (question_id, open_reports)
+VALUES ($1, $2)
+ON CONFLICT (question_id) DO UPDATE
+SET
+    open_reports = EXCLUDED.open_reports RETURNING *;
+
+-- name: LiftQuestionSuspension :execrows
+DELETE FROM question_suspensions WHERE question_id = $1;
+
+-- name: GetQuestionSuspension :one
+SELECT * FROM question_suspensions WHERE question_id = $1;
+
+-- name: ListQuestionSuspensions :many
+SELECT * FROM question_suspensions ORDER BY suspended_at;
\ No newline at end of file
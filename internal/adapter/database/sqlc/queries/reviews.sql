@@ -0,0 +1,58 @@
+-- name: CreateQuestionReview :one
+INSERT INTO
+    question_reviews (
+        question_id,
+        reviewer,
+        action,
+        comment
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: ListQuestionReviews :many
+SELECT *
+FROM question_reviews
+WHERE
+    question_id = $1
+ORDER BY created_at DESC;
+
+-- name: ReopenQuestionReview :exec
+UPDATE questions
+SET
+    review_status = 'pending',
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1
+    AND review_status IN ('approved', 'changes_requested');
+
+-- name: SetQuestionReviewStatus :one
+UPDATE questions
+SET
+    review_status = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1
+    AND deleted_at IS NULL RETURNING *;
+
+-- name: ListQuestionsForReviewBySubject :many
+SELECT q.*
+FROM questions q
+JOIN topics t ON q.topic_id = t.id
+WHERE
+    t.subject_id = $1
+    AND q.review_status = $2
+    AND q.deleted_at IS NULL
+ORDER BY q.created_at;
+
+-- name: CountQuestionsForReviewBySubject :many
+SELECT
+    s.id as subject_id,
+    s.name as subject_name,
+    COUNT(q.id) as total
+FROM questions q
+JOIN topics t ON q.topic_id = t.id
+JOIN subjects s ON t.subject_id = s.id
+WHERE
+    q.review_status = $1
+    AND q.deleted_at IS NULL
+GROUP BY s.id, s.name
+ORDER BY s.name;
\ No newline at end of file
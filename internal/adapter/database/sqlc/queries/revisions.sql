@@ -0,0 +1,43 @@
+-- name: CreateQuestionRevision :one
+INSERT INTO
+    question_revisions (
+        question_id,
+        revision,
+        author,
+        reason,
+        snapshot
+    )
+VALUES (
+        $1,
+        (
+            SELECT COALESCE(MAX(revision), 0) + 1
+            FROM question_revisions
+            WHERE
+                question_id = $1
+        ),
+        $2,
+        $3,
+        $4
+    ) RETURNING *;
+
+-- name: GetQuestionRevision :one
+SELECT *
+FROM question_revisions
+WHERE
+    question_id = $1
+    AND revision = $2;
+
+-- name: GetLatestQuestionRevision :one
+SELECT *
+FROM question_revisions
+WHERE
+    question_id = $1
+ORDER BY revision DESC
+LIMIT 1;
+
+-- name: ListQuestionRevisions :many
+SELECT *
+FROM question_revisions
+WHERE
+    question_id = $1
+ORDER BY revision DESC;
\ No newline at end of file
@@ -0,0 +1,71 @@
+-- name: SearchQuestions :many
+SELECT
+    q.id,
+    q.statement,
+    q.year,
+    q.topic_id,
+    q.position,
+    q.level,
+    q.difficulty,
+    q.modality,
+    q.practice_area,
+    q.field_of_study,
+    q.explanation,
+    ts_rank_cd(d.document, query)::REAL as rank,
+    ts_headline(
+        'pt_unaccent',
+        concat_ws(' ', q.statement, q.explanation),
+        query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10'
+    )::TEXT as snippet,
+    (
+        SELECT string_agg(
+                ts_headline(
+                    'pt_unaccent', c.choice_text, query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'
+                ), ' | '
+                ORDER BY c.position
+            )
+        FROM choices c
+        WHERE
+            c.question_id = q.id
+            AND c.deleted_at IS NULL
+            AND to_tsvector('pt_unaccent', c.choice_text) @@ query
+    )::TEXT as choice_snippet
+FROM
+    questions q
+    JOIN question_search_documents d ON d.question_id = q.id,
+    websearch_to_tsquery(
+        'pt_unaccent',
+        sqlc.arg('query')::TEXT
+    ) query
+WHERE
+    d.document @@ query
+    AND q.deleted_at IS NULL
+    AND (sqlc.narg('year')::INT IS NULL OR q.year = sqlc.narg('year'))
+    AND (sqlc.narg('level')::TEXT IS NULL OR q.level = sqlc.narg('level'))
+    AND (sqlc.narg('difficulty')::TEXT IS NULL OR q.difficulty = sqlc.narg('difficulty'))
+    AND (sqlc.narg('modality')::TEXT IS NULL OR q.modality = sqlc.narg('modality'))
+    AND (sqlc.narg('practice_area')::TEXT IS NULL OR q.practice_area = sqlc.narg('practice_area'))
+    AND (sqlc.narg('field_of_study')::TEXT IS NULL OR q.field_of_study = sqlc.narg('field_of_study'))
+    AND (sqlc.narg('topic_id')::UUID IS NULL OR q.topic_id = sqlc.narg('topic_id'))
+    AND (sqlc.narg('position')::TEXT IS NULL OR q.position = sqlc.narg('position'))
+ORDER BY rank DESC, q.created_at DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountSearchQuestions :one
+SELECT COUNT(*)
+FROM
+    questions q
+    JOIN question_search_documents d ON d.question_id = q.id
+WHERE
+    d.document @@ websearch_to_tsquery('pt_unaccent', sqlc.arg('query')::TEXT)
+    AND q.deleted_at IS NULL
+    AND (sqlc.narg('year')::INT IS NULL OR q.year = sqlc.narg('year'))
+    AND (sqlc.narg('level')::TEXT IS NULL OR q.level = sqlc.narg('level'))
+    AND (sqlc.narg('difficulty')::TEXT IS NULL OR q.difficulty = sqlc.narg('difficulty'))
+    AND (sqlc.narg('modality')::TEXT IS NULL OR q.modality = sqlc.narg('modality'))
+    AND (sqlc.narg('practice_area')::TEXT IS NULL OR q.practice_area = sqlc.narg('practice_area'))
+    AND (sqlc.narg('field_of_study')::TEXT IS NULL OR q.field_of_study = sqlc.narg('field_of_study'))
+    AND (sqlc.narg('topic_id')::UUID IS NULL OR q.topic_id = sqlc.narg('topic_id'))
+    AND (sqlc.narg('position')::TEXT IS NULL OR q.position = sqlc.narg('position'));
\ No newline at end of file
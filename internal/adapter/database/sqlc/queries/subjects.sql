@@ -2,29 +2,36 @@
 INSERT INTO subjects (name) VALUES ($1) RETURNING *;
 
 -- name: GetSubject :one
-SELECT * FROM subjects WHERE id = $1;
+SELECT * FROM subjects WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: ListSubjects :many
-SELECT * FROM subjects ORDER BY name;
+SELECT * FROM subjects WHERE deleted_at IS NULL ORDER BY name;
 
 -- name: UpdateSubject :one
-UPDATE subjects SET name = $2 WHERE id = $1 RETURNING *;
-
--- name: DeleteSubject :exec
-DELETE FROM subjects WHERE id = $1;
+UPDATE subjects
+SET
+    name = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL RETURNING *;
 
 -- name: GetSubjectByName :one
-SELECT * FROM subjects WHERE name = $1;
+SELECT * FROM subjects WHERE name = $1 AND deleted_at IS NULL;
 
 -- name: ListSubjectsByName :many
 SELECT *
 FROM subjects
 WHERE
     name ILIKE '%' || $1 || '%'
+    AND deleted_at IS NULL
 ORDER BY name;
 
 -- name: CountSubjects :one
-SELECT COUNT(*) FROM subjects;
+SELECT COUNT(*) FROM subjects WHERE deleted_at IS NULL;
 
 -- name: CountSubjectsByName :one
-SELECT COUNT(*) FROM subjects WHERE name ILIKE '%' || $1 || '%';
\ No newline at end of file
+SELECT COUNT(*)
+FROM subjects
+WHERE
+    name ILIKE '%' || $1 || '%'
+    AND deleted_at IS NULL;
\ No newline at end of file
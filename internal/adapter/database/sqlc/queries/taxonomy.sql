@@ -0,0 +1,47 @@
+-- name: MoveQuestionsToTopic :many
+UPDATE questions
+SET
+    topic_id = sqlc.arg('target_topic_id'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    topic_id = sqlc.arg('source_topic_id') RETURNING *;
+
+-- name: MoveQuestionsByIDsToTopic :many
+UPDATE questions
+SET
+    topic_id = sqlc.arg('target_topic_id'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    topic_id = sqlc.arg('source_topic_id')
+    AND id = ANY(sqlc.arg('ids')::UUID[])
+    AND deleted_at IS NULL RETURNING *;
+
+-- name: MoveTopicsToSubject :exec
+UPDATE topics
+SET
+    subject_id = sqlc.arg('target_subject_id'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    subject_id = sqlc.arg('source_subject_id');
+
+-- name: ListTrashedTopicsBySubject :many
+SELECT *
+FROM topics
+WHERE
+    subject_id = $1
+    AND deleted_at IS NOT NULL
+ORDER BY name;
+
+-- name: CreateTaxonomyChange :one
+INSERT INTO
+    taxonomy_changes (
+        author,
+        action,
+        source_id,
+        target_id,
+        question_ids
+    )
+VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: ListTaxonomyChanges :many
+SELECT * FROM taxonomy_changes ORDER BY created_at DESC;
\ No newline at end of file
@@ -2,13 +2,26 @@
 INSERT INTO topics (subject_id, name) VALUES ($1, $2) RETURNING *;
 
 -- name: GetTopic :one
-SELECT * FROM topics WHERE id = $1;
+SELECT * FROM topics WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetTopicByName :one
+SELECT *
+FROM topics
+WHERE
+    subject_id = $1
+    AND name = $2
+    AND deleted_at IS NULL;
 
 -- name: ListTopics :many
-SELECT * FROM topics ORDER BY name;
+SELECT * FROM topics WHERE deleted_at IS NULL ORDER BY name;
 
 -- name: ListTopicsBySubject :many
-SELECT * FROM topics WHERE subject_id = $1 ORDER BY name;
+SELECT *
+FROM topics
+WHERE
+    subject_id = $1
+    AND deleted_at IS NULL
+ORDER BY name;
 
 -- name: UpdateTopic :one
 UPDATE topics
@@ -16,7 +29,5 @@ SET
     subject_id = $2,
     name = $3
 WHERE
-    id = $1 RETURNING *;
-
--- name: DeleteTopic :exec
-DELETE FROM topics WHERE id = $1;
\ No newline at end of file
+    id = $1
+    AND deleted_at IS NULL RETURNING *;
\ No newline at end of file
@@ -0,0 +1,308 @@
+-- name: SoftDeleteSubject :execrows
+UPDATE subjects
+SET
+    deleted_at = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteTopicsBySubject :exec
+UPDATE topics
+SET
+    deleted_at = $2
+WHERE
+    subject_id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteQuestionsBySubject :exec
+UPDATE questions q
+SET
+    deleted_at = $2
+FROM topics t
+WHERE
+    q.topic_id = t.id
+    AND t.subject_id = $1
+    AND q.deleted_at IS NULL;
+
+-- name: SoftDeleteChoicesBySubject :exec
+UPDATE choices c
+SET
+    deleted_at = $2
+FROM questions q
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    c.question_id = q.id
+    AND t.subject_id = $1
+    AND c.deleted_at IS NULL;
+
+-- name: SoftDeleteTopic :execrows
+UPDATE topics
+SET
+    deleted_at = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteQuestionsByTopic :exec
+UPDATE questions
+SET
+    deleted_at = $2
+WHERE
+    topic_id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteChoicesByTopic :exec
+UPDATE choices c
+SET
+    deleted_at = $2
+FROM questions q
+WHERE
+    c.question_id = q.id
+    AND q.topic_id = $1
+    AND c.deleted_at IS NULL;
+
+-- name: SoftDeleteQuestion :execrows
+UPDATE questions
+SET
+    deleted_at = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteChoicesByQuestion :exec
+UPDATE choices
+SET
+    deleted_at = $2
+WHERE
+    question_id = $1
+    AND deleted_at IS NULL;
+
+-- name: SoftDeleteChoice :execrows
+UPDATE choices
+SET
+    deleted_at = $2
+WHERE
+    id = $1
+    AND deleted_at IS NULL;
+
+-- name: CountSubjectDependents :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM topics t
+        WHERE
+            t.subject_id = $1
+            AND t.deleted_at IS NULL
+    )::BIGINT as topics,
+    (
+        SELECT COUNT(*)
+        FROM questions q
+            JOIN topics t ON q.topic_id = t.id
+        WHERE
+            t.subject_id = $1
+            AND q.deleted_at IS NULL
+    )::BIGINT as questions,
+    (
+        SELECT COUNT(*)
+        FROM choices c
+            JOIN questions q ON c.question_id = q.id
+            JOIN topics t ON q.topic_id = t.id
+        WHERE
+            t.subject_id = $1
+            AND c.deleted_at IS NULL
+    )::BIGINT as choices;
+
+-- name: CountTopicDependents :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM questions q
+        WHERE
+            q.topic_id = $1
+            AND q.deleted_at IS NULL
+    )::BIGINT as questions,
+    (
+        SELECT COUNT(*)
+        FROM choices c
+            JOIN questions q ON c.question_id = q.id
+        WHERE
+            q.topic_id = $1
+            AND c.deleted_at IS NULL
+    )::BIGINT as choices;
+
+-- name: CountQuestionDependents :one
+SELECT COUNT(*)
+FROM choices
+WHERE
+    question_id = $1
+    AND deleted_at IS NULL;
+
+-- name: GetTrashedSubject :one
+SELECT * FROM subjects WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetTrashedTopic :one
+SELECT * FROM topics WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetTrashedQuestion :one
+SELECT * FROM questions WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: GetTrashedChoice :one
+SELECT * FROM choices WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListTrashedSubjects :many
+SELECT * FROM subjects WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- name: ListTrashedTopics :many
+SELECT t.*
+FROM topics t
+    JOIN subjects s ON t.subject_id = s.id
+WHERE
+    t.deleted_at IS NOT NULL
+    AND s.deleted_at IS DISTINCT FROM t.deleted_at
+ORDER BY t.deleted_at DESC;
+
+-- name: ListTrashedQuestions :many
+SELECT q.*
+FROM questions q
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    q.deleted_at IS NOT NULL
+    AND t.deleted_at IS DISTINCT FROM q.deleted_at
+ORDER BY q.deleted_at DESC;
+
+-- name: ListTrashedChoices :many
+SELECT c.*
+FROM choices c
+    JOIN questions q ON c.question_id = q.id
+WHERE
+    c.deleted_at IS NOT NULL
+    AND q.deleted_at IS DISTINCT FROM c.deleted_at
+ORDER BY c.deleted_at DESC;
+
+-- name: RestoreSubject :exec
+UPDATE subjects SET deleted_at = NULL WHERE id = $1;
+
+-- name: RestoreTopicsBySubject :exec
+UPDATE topics
+SET
+    deleted_at = NULL
+WHERE
+    subject_id = $1
+    AND deleted_at = $2;
+
+-- name: RestoreQuestionsBySubject :exec
+UPDATE questions q
+SET
+    deleted_at = NULL
+FROM topics t
+WHERE
+    q.topic_id = t.id
+    AND t.subject_id = $1
+    AND q.deleted_at = $2;
+
+-- name: RestoreChoicesBySubject :exec
+UPDATE choices c
+SET
+    deleted_at = NULL
+FROM questions q
+    JOIN topics t ON q.topic_id = t.id
+WHERE
+    c.question_id = q.id
+    AND t.subject_id = $1
+    AND c.deleted_at = $2;
+
+-- name: RestoreTopic :exec
+UPDATE topics SET deleted_at = NULL WHERE id = $1;
+
+-- name: RestoreQuestionsByTopic :exec
+UPDATE questions
+SET
+    deleted_at = NULL
+WHERE
+    topic_id = $1
+    AND deleted_at = $2;
+
+-- name: RestoreChoicesByTopic :exec
+UPDATE choices c
+SET
+    deleted_at = NULL
+FROM questions q
+WHERE
+    c.question_id = q.id
+    AND q.topic_id = $1
+    AND c.deleted_at = $2;
+
+-- name: RestoreQuestion :exec
+UPDATE questions SET deleted_at = NULL WHERE id = $1;
+
+-- name: RestoreChoicesByQuestion :exec
+UPDATE choices
+SET
+    deleted_at = NULL
+WHERE
+    question_id = $1
+    AND deleted_at = $2;
+
+-- name: RestoreChoice :exec
+UPDATE choices
+SET
+    deleted_at = NULL,
+    position = (
+        SELECT COALESCE(MAX(c.position) + 1, 0)
+        FROM choices c
+        WHERE
+            c.question_id = choices.question_id
+            AND c.deleted_at IS NULL
+    )
+WHERE
+    id = $1;
+
+-- name: PurgeQuestionsBySubject :exec
+DELETE FROM questions q USING topics t
+WHERE
+    q.topic_id = t.id
+    AND t.subject_id = $1
+    AND q.deleted_at IS NOT NULL;
+
+-- name: PurgeSubject :execrows
+DELETE FROM subjects WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeQuestionsByTopic :exec
+DELETE FROM questions WHERE topic_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeTopic :execrows
+DELETE FROM topics WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeQuestion :execrows
+DELETE FROM questions WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeChoice :execrows
+DELETE FROM choices WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: PurgeTrashedChoices :execrows
+DELETE FROM choices WHERE deleted_at < $1;
+
+-- name: PurgeTrashedQuestions :execrows
+DELETE FROM questions WHERE deleted_at < $1;
+
+-- name: PurgeTrashedTopics :execrows
+DELETE FROM topics t
+WHERE
+    t.deleted_at < $1
+    AND NOT EXISTS (
+        SELECT 1
+        FROM questions q
+        WHERE
+            q.topic_id = t.id
+    );
+
+-- name: PurgeTrashedSubjects :execrows
+DELETE FROM subjects s
+WHERE
+    s.deleted_at < $1
+    AND NOT EXISTS (
+        SELECT 1
+        FROM topics t
+        WHERE
+            t.subject_id = s.id
+    );
\ No newline at end of file
@@ -1,16 +1,36 @@
 -- Ativa a extensão para geração de UUID (caso não esteja ativa)
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
+-- Similaridade por trigramas, usada na detecção de questões duplicadas
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+-- Remoção de acentos, usada na busca textual em português
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+
+-- Configuração de busca em português que ignora acentos
+CREATE TEXT SEARCH CONFIGURATION pt_unaccent (COPY = portuguese);
+
+ALTER TEXT SEARCH CONFIGURATION pt_unaccent
+ALTER MAPPING FOR hword,
+hword_part,
+word
+WITH
+    unaccent,
+    portuguese_stem;
+
 -- 1. Subjects table
 CREATE TABLE subjects (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
-    name VARCHAR(100) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
     created_at TIMESTAMP
     WITH
         TIME ZONE DEFAULT CURRENT_TIMESTAMP,
         updated_at TIMESTAMP
     WITH
-        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        deleted_at TIMESTAMP
+    WITH
+        TIME ZONE -- Soft delete: preenchido quando está na lixeira
 );
 
 -- 2. Topics table (related to subjects)
@@ -19,13 +39,15 @@ CREATE TABLE topics (
     subject_id UUID NOT NULL,
     name VARCHAR(100) NOT NULL,
     CONSTRAINT fk_subject FOREIGN KEY (subject_id) REFERENCES subjects (id) ON DELETE CASCADE,
-    UNIQUE (subject_id, name),
     created_at TIMESTAMP
     WITH
         TIME ZONE DEFAULT CURRENT_TIMESTAMP,
         updated_at TIMESTAMP
     WITH
-        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        deleted_at TIMESTAMP
+    WITH
+        TIME ZONE
 );
 
 -- 3. Questions table
@@ -46,6 +68,22 @@ CREATE TABLE questions (
         updated_at TIMESTAMP
     WITH
         TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        review_status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+            review_status IN (
+                'pending',
+                'approved',
+                'rejected',
+                'changes_requested'
+            )
+        ),
+        deleted_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        normalized_statement TEXT NOT NULL DEFAULT '', -- Enunciado sem acentos, pontuação e espaços extras
+        explanation TEXT, -- Comentário/gabarito comentado da questão
+        board VARCHAR(100), -- Banca organizadora (FGV, FCC, Vunesp, ...)
+        choice_count INT CHECK (choice_count BETWEEN 2 AND 10), -- Sobrescreve a quantidade de alternativas da banca
+        external_id VARCHAR(100), -- Identificador da questão no sistema de origem, usado para reimportá-la
         CONSTRAINT fk_topic FOREIGN KEY (topic_id) REFERENCES topics (id)
 );
 
@@ -61,9 +99,268 @@ CREATE TABLE choices (
         updated_at TIMESTAMP
     WITH
         TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        deleted_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        position INT NOT NULL DEFAULT 0, -- Ordem da alternativa na questão (0 = A)
         CONSTRAINT fk_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
 );
 
+-- 5. Question reviews table (histórico da revisão editorial)
+CREATE TABLE question_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    question_id UUID NOT NULL,
+    reviewer VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- approve, reject, request_changes
+    comment TEXT,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_review_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 6. Question revisions table (histórico append-only de questão + alternativas)
+-- Sem FK para questions: o histórico deve sobreviver à exclusão da questão.
+CREATE TABLE question_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    question_id UUID NOT NULL,
+    revision INT NOT NULL,
+    author VARCHAR(100) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE (question_id, revision)
+);
+
+-- 7. Exams table (provas geradas)
+CREATE TABLE exams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    created_by VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 8. Exam questions table (aponta para a revisão exata que foi impressa)
+CREATE TABLE exam_questions (
+    exam_id UUID NOT NULL,
+    number INT NOT NULL,
+    question_id UUID NOT NULL,
+    revision_id UUID NOT NULL,
+    subject_name VARCHAR(100) NOT NULL,
+    answer VARCHAR(1) NOT NULL,
+    choice_order UUID[] NOT NULL DEFAULT '{}', -- Ordem em que as alternativas foram impressas
+    PRIMARY KEY (exam_id, number),
+    CONSTRAINT fk_exam FOREIGN KEY (exam_id) REFERENCES exams (id) ON DELETE CASCADE,
+    CONSTRAINT fk_revision FOREIGN KEY (revision_id) REFERENCES question_revisions (id)
+);
+
+-- 9. Question search documents (documento de busca textual por questão)
+-- Mantido por triggers a partir do enunciado, alternativas e explicação.
+CREATE TABLE question_search_documents (
+    question_id UUID PRIMARY KEY,
+    document TSVECTOR NOT NULL,
+    CONSTRAINT fk_search_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 10. Boards table (bancas e sua quantidade padrão de alternativas)
+CREATE TABLE boards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    choice_count INT NOT NULL DEFAULT 5 CHECK (choice_count BETWEEN 2 AND 10),
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 11. Bulk updates table (auditoria das edições em lote de questões)
+CREATE TABLE bulk_updates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    author VARCHAR(100) NOT NULL,
+    selection JSONB NOT NULL, -- IDs ou filtros usados para escolher as questões
+    changes JSONB NOT NULL,
+    question_ids UUID[] NOT NULL,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 12. Taxonomy changes table (auditoria de fusões e divisões de tópicos e disciplinas)
+CREATE TABLE taxonomy_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    author VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- 'merge_topic', 'merge_subject' ou 'split_topic'
+    source_id UUID NOT NULL,
+    target_id UUID NOT NULL,
+    question_ids UUID[] NOT NULL, -- questões que mudaram de tópico
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 13. Legal references table (lei, artigo e parágrafo em que a questão se baseia)
+CREATE TABLE legal_references (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    question_id UUID NOT NULL,
+    law VARCHAR(100) NOT NULL, -- Ex: Lei 8.112/1990, CF/1988
+    article VARCHAR(20), -- Ex: 37, 5º
+    paragraph VARCHAR(20), -- Ex: § 1º, inciso II
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_legal_reference_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE,
+        UNIQUE NULLS NOT DISTINCT (question_id, law, article, paragraph)
+);
+
+-- 14. Exam attempts table (respostas de um candidato a uma prova)
+CREATE TABLE exam_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    exam_id UUID NOT NULL,
+    candidate VARCHAR(100) NOT NULL,
+    score INT NOT NULL, -- Número de acertos
+    total INT NOT NULL, -- Número de questões da prova
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_attempt_exam FOREIGN KEY (exam_id) REFERENCES exams (id) ON DELETE CASCADE
+);
+
+-- 15. Attempt answers table (alternativa marcada em cada questão da prova)
+CREATE TABLE attempt_answers (
+    attempt_id UUID NOT NULL,
+    number INT NOT NULL,
+    question_id UUID NOT NULL,
+    choice_id UUID, -- NULL quando a questão foi deixada em branco
+    is_correct BOOLEAN NOT NULL,
+    PRIMARY KEY (attempt_id, number),
+    CONSTRAINT fk_answer_attempt FOREIGN KEY (attempt_id) REFERENCES exam_attempts (id) ON DELETE CASCADE
+);
+
+-- 16. Question statistics table (estatísticas calculadas a partir das respostas)
+CREATE TABLE question_statistics (
+    question_id UUID PRIMARY KEY,
+    responses INT NOT NULL,
+    p_correct DOUBLE PRECISION NOT NULL, -- Proporção de acertos (0 a 1)
+    discrimination DOUBLE PRECISION, -- Ponto-bisserial; NULL quando indefinido
+    updated_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_statistics_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 17. Item parameters table (parâmetros TRI calibrados por questão)
+CREATE TABLE item_parameters (
+    question_id UUID PRIMARY KEY,
+    model VARCHAR(3) NOT NULL, -- '2PL' ou '3PL'
+    discrimination DOUBLE PRECISION NOT NULL, -- a
+    difficulty DOUBLE PRECISION NOT NULL, -- b
+    guessing DOUBLE PRECISION NOT NULL DEFAULT 0, -- c (0 no modelo 2PL)
+    responses INT NOT NULL,
+    calibrated_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_item_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 18. CAT sessions table (testes adaptativos)
+CREATE TABLE cat_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    candidate VARCHAR(100) NOT NULL,
+    subject_id UUID, -- NULL: todas as disciplinas
+    max_items INT NOT NULL,
+    target_se DOUBLE PRECISION NOT NULL, -- Encerra quando o erro padrão fica abaixo deste valor
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, finished
+    theta DOUBLE PRECISION NOT NULL DEFAULT 0,
+    se DOUBLE PRECISION NOT NULL DEFAULT 1,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        finished_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        CONSTRAINT fk_cat_subject FOREIGN KEY (subject_id) REFERENCES subjects (id) ON DELETE CASCADE
+);
+
+-- 19. CAT responses table (questões aplicadas em cada sessão adaptativa)
+CREATE TABLE cat_responses (
+    session_id UUID NOT NULL,
+    number INT NOT NULL,
+    question_id UUID NOT NULL,
+    choice_id UUID,
+    is_correct BOOLEAN, -- NULL enquanto a questão aguarda resposta
+    theta DOUBLE PRECISION, -- Estimativa após a resposta
+    se DOUBLE PRECISION,
+    answered_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        PRIMARY KEY (session_id, number),
+        CONSTRAINT fk_cat_response_session FOREIGN KEY (session_id) REFERENCES cat_sessions (id) ON DELETE CASCADE
+);
+
+-- 20. Question reports table (erros apontados por alunos e professores)
+CREATE TABLE question_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    question_id UUID NOT NULL,
+    category VARCHAR(20) NOT NULL, -- wrong_key, typo, outdated, duplicate
+    comment TEXT NOT NULL,
+    reporter VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, in_review, resolved, dismissed
+    resolution TEXT, -- Nota do moderador ao encerrar o reporte
+    moderator VARCHAR(100),
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        closed_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        CONSTRAINT fk_report_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 21. Question suspensions table (questões retiradas da geração de provas por reportes abertos)
+CREATE TABLE question_suspensions (
+    question_id UUID PRIMARY KEY,
+    open_reports INT NOT NULL, -- Reportes abertos no momento da suspensão
+    suspended_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        CONSTRAINT fk_suspension_question FOREIGN KEY (question_id) REFERENCES questions (id) ON DELETE CASCADE
+);
+
+-- 22. Import jobs table (importações em segundo plano)
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    filename VARCHAR(255) NOT NULL,
+    options JSONB NOT NULL, -- create_missing, dry_run, atomic
+    status VARCHAR(20) NOT NULL DEFAULT 'queued', -- queued, running, finished, failed, cancelled
+    created_by VARCHAR(100) NOT NULL,
+    processed INT NOT NULL DEFAULT 0, -- Linhas já gravadas, salvas junto com cada linha
+    created INT NOT NULL DEFAULT 0,
+    ignored INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    report JSONB, -- Relatório parcial ou final, usado para retomar a importação
+    error TEXT, -- Erro que interrompeu a importação
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+        started_at TIMESTAMP
+    WITH
+        TIME ZONE,
+        finished_at TIMESTAMP
+    WITH
+        TIME ZONE
+);
+
+-- 23. Import job files table (arquivo enviado, mantido até o fim da importação)
+CREATE TABLE import_job_files (
+    job_id UUID PRIMARY KEY,
+    content BYTEA NOT NULL,
+    CONSTRAINT fk_import_file_job FOREIGN KEY (job_id) REFERENCES import_jobs (id) ON DELETE CASCADE
+);
+
 -- Índices para acelerar a geração automática de provas (filtros comuns)
 CREATE INDEX idx_questions_topic_id ON questions (topic_id);
 
@@ -73,4 +370,125 @@ CREATE INDEX idx_questions_practice_area ON questions (practice_area);
 
 CREATE INDEX idx_questions_field_of_study ON questions (field_of_study);
 
-CREATE INDEX idx_choices_question_id ON choices (question_id);
\ No newline at end of file
+CREATE INDEX idx_choices_question_id ON choices (question_id, position);
+
+CREATE INDEX idx_questions_review_status ON questions (review_status);
+
+CREATE INDEX idx_legal_references_law ON legal_references (lower(law), article);
+
+CREATE INDEX idx_attempt_answers_question_id ON attempt_answers (question_id);
+
+CREATE INDEX idx_question_reports_status ON question_reports (status, created_at);
+
+CREATE INDEX idx_question_reports_question_id ON question_reports (question_id);
+
+CREATE INDEX idx_import_jobs_status ON import_jobs (status, created_at);
+
+CREATE INDEX idx_question_reviews_question_id ON question_reviews (question_id);
+
+CREATE INDEX idx_exam_questions_question_id ON exam_questions (question_id);
+
+CREATE INDEX idx_questions_board ON questions (board);
+
+-- Unicidade apenas entre registros fora da lixeira, para permitir recriar
+-- uma matéria/tópico com o mesmo nome de um que foi excluído
+CREATE UNIQUE INDEX uq_subjects_name ON subjects (name)
+WHERE
+    deleted_at IS NULL;
+
+CREATE UNIQUE INDEX uq_topics_subject_name ON topics (subject_id, name)
+WHERE
+    deleted_at IS NULL;
+
+CREATE UNIQUE INDEX uq_questions_external_id ON questions (external_id)
+WHERE
+    deleted_at IS NULL;
+
+CREATE INDEX idx_questions_deleted_at ON questions (deleted_at);
+
+CREATE INDEX idx_questions_normalized_statement_trgm ON questions USING GIN (normalized_statement gin_trgm_ops);
+
+CREATE INDEX idx_question_search_documents_document ON question_search_documents USING GIN (document);
+
+-- Integridade do gabarito de cada questão: quantidade esperada de alternativas
+-- (override da questão, 2 para Certo/Errado, padrão da banca ou 5), alternativas
+-- ativas, corretas e vazias. Espelha service.resolveChoiceCount.
+CREATE VIEW question_integrity AS
+SELECT
+    q.id AS question_id,
+    COALESCE(
+        q.choice_count,
+        CASE
+            WHEN q.modality = 'Certo/Errado' THEN 2
+        END,
+        b.choice_count,
+        5
+    )::INT AS expected_choices,
+    COUNT(c.id) AS choices,
+    COUNT(c.id) FILTER (
+        WHERE
+            c.is_correct
+    ) AS correct_choices,
+    COUNT(c.id) FILTER (
+        WHERE
+            btrim(c.choice_text) = ''
+    ) AS empty_choices,
+    btrim(q.statement) = '' AS empty_statement
+FROM
+    questions q
+    LEFT JOIN boards b ON b.name = q.board
+    LEFT JOIN choices c ON c.question_id = q.id
+    AND c.deleted_at IS NULL
+GROUP BY
+    q.id,
+    b.choice_count;
+
+-- Recalcula o documento de busca de uma questão: enunciado (peso A),
+-- alternativas ativas (peso B) e explicação (peso C)
+CREATE FUNCTION refresh_question_search_document (p_question_id UUID) RETURNS void LANGUAGE sql AS $$
+    INSERT INTO question_search_documents (question_id, document)
+    SELECT
+        q.id,
+        setweight(to_tsvector('pt_unaccent', q.statement), 'A')
+        || setweight(to_tsvector('pt_unaccent', coalesce((
+            SELECT string_agg(c.choice_text, ' ')
+            FROM choices c
+            WHERE c.question_id = q.id AND c.deleted_at IS NULL
+        ), '')), 'B')
+        || setweight(to_tsvector('pt_unaccent', coalesce(q.explanation, '')), 'C')
+    FROM questions q
+    WHERE q.id = p_question_id
+    ON CONFLICT (question_id) DO UPDATE SET document = EXCLUDED.document;
+$$;
+
+CREATE FUNCTION questions_search_document_trigger () RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM refresh_question_search_document(NEW.id);
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_questions_search_document
+AFTER INSERT
+OR
+UPDATE OF statement,
+explanation ON questions FOR EACH ROW
+EXECUTE FUNCTION questions_search_document_trigger ();
+
+CREATE FUNCTION choices_search_document_trigger () RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM refresh_question_search_document(OLD.question_id);
+    ELSE
+        PERFORM refresh_question_search_document(NEW.question_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_choices_search_document
+AFTER INSERT
+OR
+UPDATE
+OR DELETE ON choices FOR EACH ROW
+EXECUTE FUNCTION choices_search_document_trigger ();
\ No newline at end of file
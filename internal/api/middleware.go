@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+// userHeader carries the name of whoever is performing the request.
+// There is no authentication yet, so it is trusted as-is.
+const userHeader = "X-User"
+
+// userContext stores the request user in the context so services can
+// attribute reviews, revisions and audit entries.
+func userContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := strings.TrimSpace(r.Header.Get(userHeader))
+		if user == "" {
+			user = "anonymous"
+		}
+		next.ServeHTTP(w, r.WithContext(service.WithUser(r.Context(), user)))
+	})
+}
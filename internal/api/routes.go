@@ -17,17 +17,29 @@ import (
 )
 
 type RouterHandlers struct {
-	SubjectHandler  *handlers.SubjectHandler
-	TopicHandler    *handlers.TopicHandler
-	ChoiceHandler   *handlers.ChoiceHandler
-	QuestionHandler *handlers.QuestionHandler
-	ExamHandler     *handlers.ExamHandler
+	SubjectHandler        *handlers.SubjectHandler
+	TopicHandler          *handlers.TopicHandler
+	ChoiceHandler         *handlers.ChoiceHandler
+	QuestionHandler       *handlers.QuestionHandler
+	ExamHandler           *handlers.ExamHandler
+	ReviewHandler         *handlers.ReviewHandler
+	RevisionHandler       *handlers.RevisionHandler
+	TrashHandler          *handlers.TrashHandler
+	DuplicateHandler      *handlers.DuplicateHandler
+	BoardHandler          *handlers.BoardHandler
+	TaxonomyHandler       *handlers.TaxonomyHandler
+	LegalReferenceHandler *handlers.LegalReferenceHandler
+	StatisticsHandler     *handlers.StatisticsHandler
+	IRTHandler            *handlers.IRTHandler
+	ReportHandler         *handlers.ReportHandler
+	ImportJobHandler      *handlers.ImportJobHandler
 }
 
 func NewRouter(handlers *RouterHandlers) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(userContext)
 
 	r.Route("/subjects", func(r chi.Router) {
 		r.Get("/", handlers.SubjectHandler.ListSubjects)
@@ -35,6 +47,7 @@ func NewRouter(handlers *RouterHandlers) http.Handler {
 		r.Get("/{id}", handlers.SubjectHandler.GetSubject)
 		r.Put("/{id}", handlers.SubjectHandler.UpdateSubject)
 		r.Delete("/{id}", handlers.SubjectHandler.DeleteSubject)
+		r.Post("/{id}/merge", handlers.TaxonomyHandler.MergeSubjects)
 	})
 
 	r.Route("/topics", func(r chi.Router) {
@@ -44,6 +57,8 @@ func NewRouter(handlers *RouterHandlers) http.Handler {
 		r.Put("/{id}", handlers.TopicHandler.UpdateTopic)
 		r.Delete("/{id}", handlers.TopicHandler.DeleteTopic)
 		r.Get("/subject/{subject_id}", handlers.TopicHandler.ListTopicsBySubject)
+		r.Post("/{id}/merge", handlers.TaxonomyHandler.MergeTopics)
+		r.Post("/{id}/split", handlers.TaxonomyHandler.SplitTopic)
 	})
 
 	r.Route("/choices", func(r chi.Router) {
@@ -58,13 +73,98 @@ func NewRouter(handlers *RouterHandlers) http.Handler {
 		r.Get("/", handlers.QuestionHandler.ListQuestionsByFilters)
 		r.Post("/", handlers.QuestionHandler.CreateQuestion)
 		r.Post("/import", handlers.QuestionHandler.ImportQuestionsCSV)
+		r.Post("/import.jsonl", handlers.QuestionHandler.ImportQuestionsJSONL)
+		r.Get("/export.jsonl", handlers.QuestionHandler.ExportQuestionsJSONL)
+		r.Get("/export.csv", handlers.QuestionHandler.ExportQuestionsCSV)
+		r.Post("/with-choices", handlers.QuestionHandler.CreateQuestionWithChoices)
+		r.Get("/search", handlers.QuestionHandler.SearchQuestions)
+		r.Get("/audit", handlers.QuestionHandler.AuditAnswerKeys)
+		r.Post("/bulk", handlers.QuestionHandler.BulkUpdateQuestions)
+		r.Get("/bulk", handlers.QuestionHandler.ListBulkUpdates)
+		r.Get("/duplicates", handlers.DuplicateHandler.ListDuplicateClusters)
+		r.Post("/duplicates/merge", handlers.DuplicateHandler.MergeDuplicates)
 		r.Get("/{id}", handlers.QuestionHandler.GetQuestion)
 		r.Put("/{id}", handlers.QuestionHandler.UpdateQuestion)
 		r.Delete("/{id}", handlers.QuestionHandler.DeleteQuestion)
+		r.Put("/{id}/with-choices", handlers.QuestionHandler.ReplaceQuestionWithChoices)
+		r.Patch("/{id}/with-choices", handlers.QuestionHandler.PatchQuestionWithChoices)
+		r.Get("/{id}/similar", handlers.DuplicateHandler.ListSimilarQuestions)
+		r.Get("/{id}/statistics", handlers.StatisticsHandler.GetQuestionStatistics)
+		r.Get("/{id}/irt", handlers.IRTHandler.GetItemParameters)
+		r.Get("/{id}/reports", handlers.ReportHandler.ListQuestionReports)
+		r.Post("/{id}/reports", handlers.ReportHandler.ReportQuestion)
+		r.Put("/{id}/choices/order", handlers.ChoiceHandler.ReorderChoices)
+		r.Get("/{id}/revisions", handlers.RevisionHandler.ListRevisions)
+		r.Get("/{id}/revisions/diff", handlers.RevisionHandler.DiffRevisions)
+		r.Get("/{id}/revisions/{revision}", handlers.RevisionHandler.GetRevision)
+		r.Post("/{id}/revisions/{revision}/restore", handlers.RevisionHandler.RestoreRevision)
+		r.Get("/{id}/legal-references", handlers.LegalReferenceHandler.ListLegalReferences)
+		r.Post("/{id}/legal-references", handlers.LegalReferenceHandler.AddLegalReference)
+		r.Delete("/{id}/legal-references/{ref_id}", handlers.LegalReferenceHandler.DeleteLegalReference)
 	})
 
 	r.Route("/exams", func(r chi.Router) {
+		r.Get("/", handlers.ExamHandler.ListExams)
 		r.Post("/", handlers.ExamHandler.GenerateExam)
+		r.Get("/{id}", handlers.ExamHandler.GetExam)
+		r.Get("/{id}/pdf", handlers.ExamHandler.RenderExam)
+		r.Get("/{id}/attempts", handlers.StatisticsHandler.ListAttempts)
+		r.Post("/{id}/attempts", handlers.StatisticsHandler.RecordAttempt)
+	})
+
+	r.Route("/reviews", func(r chi.Router) {
+		r.Get("/", handlers.ReviewHandler.CountQuestionsForReview)
+		r.Get("/subject/{subject_id}", handlers.ReviewHandler.ListQuestionsForReview)
+		r.Get("/questions/{id}", handlers.ReviewHandler.ListQuestionReviews)
+		r.Post("/questions/{id}", handlers.ReviewHandler.ReviewQuestion)
+	})
+
+	r.Route("/boards", func(r chi.Router) {
+		r.Get("/", handlers.BoardHandler.ListBoards)
+		r.Post("/", handlers.BoardHandler.CreateBoard)
+		r.Get("/{id}", handlers.BoardHandler.GetBoard)
+		r.Put("/{id}", handlers.BoardHandler.UpdateBoard)
+		r.Delete("/{id}", handlers.BoardHandler.DeleteBoard)
+	})
+
+	r.Get("/taxonomy/changes", handlers.TaxonomyHandler.ListTaxonomyChanges)
+
+	r.Route("/legal-references", func(r chi.Router) {
+		r.Get("/questions", handlers.LegalReferenceHandler.ListQuestionsCiting)
+		r.Post("/review", handlers.LegalReferenceHandler.MarkForReview)
+	})
+
+	r.Route("/reports", func(r chi.Router) {
+		r.Get("/", handlers.ReportHandler.ListReports)
+		r.Get("/suspensions", handlers.ReportHandler.ListSuspensions)
+		r.Get("/{id}", handlers.ReportHandler.GetReport)
+		r.Put("/{id}", handlers.ReportHandler.ModerateReport)
+	})
+
+	r.Route("/imports", func(r chi.Router) {
+		r.Get("/", handlers.ImportJobHandler.ListImports)
+		r.Post("/", handlers.ImportJobHandler.SubmitImport)
+		r.Get("/{id}", handlers.ImportJobHandler.GetImport)
+		r.Post("/{id}/cancel", handlers.ImportJobHandler.CancelImport)
+		r.Get("/{id}/errors.csv", handlers.ImportJobHandler.DownloadImportErrors)
+	})
+
+	r.Route("/irt", func(r chi.Router) {
+		r.Get("/items", handlers.IRTHandler.ListItemParameters)
+		r.Post("/calibrate", handlers.IRTHandler.Calibrate)
+	})
+
+	r.Route("/cat/sessions", func(r chi.Router) {
+		r.Post("/", handlers.IRTHandler.StartCAT)
+		r.Get("/{id}", handlers.IRTHandler.GetCAT)
+		r.Post("/{id}/answers", handlers.IRTHandler.AnswerCAT)
+	})
+
+	r.Route("/trash", func(r chi.Router) {
+		r.Get("/", handlers.TrashHandler.ListTrash)
+		r.Delete("/", handlers.TrashHandler.PurgeTrash)
+		r.Post("/{kind}/{id}/restore", handlers.TrashHandler.RestoreFromTrash)
+		r.Delete("/{kind}/{id}", handlers.TrashHandler.PurgeFromTrash)
 	})
 
 	// slog all routes with a for loop
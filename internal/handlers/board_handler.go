@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type BoardHandler struct {
+	svc *service.BoardService
+}
+
+func NewBoardHandler(svc *service.BoardService) *BoardHandler {
+	return &BoardHandler{svc: svc}
+}
+
+type boardBody struct {
+	Name        string `json:"name"`
+	ChoiceCount int32  `json:"choice_count"`
+}
+
+func (h *BoardHandler) ListBoards(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing boards")
+
+	boards, err := h.svc.ListBoards(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing boards", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Boards listed successfully", "count", len(boards))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(boards)
+}
+
+// CreateBoard registers a banca with its default number of choices. When
+// choice_count is omitted, service.DefaultChoiceCount is used.
+func (h *BoardHandler) CreateBoard(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Creating board")
+
+	body := boardBody{ChoiceCount: service.DefaultChoiceCount}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	board, err := h.svc.CreateBoard(r.Context(), body.Name, body.ChoiceCount)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error creating board", "error", err, "name", body.Name)
+		writeBoardError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Board created successfully", "board_id", board.ID, "name", board.Name)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(board)
+}
+
+func (h *BoardHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	board, err := h.svc.GetBoard(r.Context(), idUUID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting board", "error", err)
+		writeBoardError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+func (h *BoardHandler) UpdateBoard(w http.ResponseWriter, r *http.Request) {
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body boardBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	board, err := h.svc.UpdateBoard(r.Context(), idUUID, body.Name, body.ChoiceCount)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error updating board", "error", err)
+		writeBoardError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Board updated successfully", "board_id", board.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+func (h *BoardHandler) DeleteBoard(w http.ResponseWriter, r *http.Request) {
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteBoard(r.Context(), idUUID); err != nil {
+		slog.ErrorContext(r.Context(), "Error deleting board", "error", err)
+		writeBoardError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeBoardError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrChoiceCount):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "board not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "duplicate key"):
+		http.Error(w, "board already exists", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -7,10 +7,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
@@ -89,7 +91,7 @@ func (h *ChoiceHandler) CreateChoice(w http.ResponseWriter, r *http.Request) {
 	choice, err := h.svc.CreateChoice(r.Context(), bodyForm)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "Error creating choice", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeChoiceError(w, err)
 		return
 	}
 
@@ -100,6 +102,47 @@ func (h *ChoiceHandler) CreateChoice(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(choice)
 }
 
+// ReorderChoices sets the letter order of a question's choices. The body
+// lists every choice ID of the question, first letter first.
+func (h *ChoiceHandler) ReorderChoices(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Reordering choices")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		ChoiceIDs []pgtype.UUID `json:"choice_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	choices, err := h.svc.ReorderChoices(r.Context(), questionID, body.ChoiceIDs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error reordering choices", "error", err)
+		switch {
+		case errors.Is(err, service.ErrInvalidChoiceOrder):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "question not found", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Choices reordered successfully", "question_id", questionID, "count", len(choices))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(choices)
+}
+
 func (h *ChoiceHandler) GetChoice(w http.ResponseWriter, r *http.Request) {
 	slog.InfoContext(r.Context(), "Getting choice")
 	id := chi.URLParam(r, "id")
@@ -154,7 +197,7 @@ func (h *ChoiceHandler) UpdateChoice(w http.ResponseWriter, r *http.Request) {
 	choice, err := h.svc.UpdateChoice(r.Context(), choiceForm)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "Error updating choice", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeChoiceError(w, err)
 		return
 	}
 
@@ -174,9 +217,15 @@ func (h *ChoiceHandler) DeleteChoice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isDryRun(r) {
+		impact, err := h.svc.DeleteChoiceImpact(r.Context(), idUUID)
+		writeDeleteImpact(w, r, impact, err)
+		return
+	}
+
 	if err := h.svc.DeleteChoice(r.Context(), idUUID); err != nil {
 		slog.ErrorContext(r.Context(), "Error deleting choice", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeTrashError(w, err)
 		return
 	}
 
@@ -184,3 +233,16 @@ func (h *ChoiceHandler) DeleteChoice(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// writeChoiceError maps answer-key violations to 400 and missing questions
+// or choices to 404.
+func writeChoiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrChoiceCount), errors.Is(err, service.ErrInvalidChoices):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type DuplicateHandler struct {
+	svc *service.DuplicateService
+}
+
+func NewDuplicateHandler(svc *service.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{svc: svc}
+}
+
+// ListDuplicateClusters returns the groups of suspected duplicate questions.
+// An optional "threshold" query parameter (0 to 1) overrides the default similarity.
+func (h *DuplicateHandler) ListDuplicateClusters(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing duplicate clusters")
+
+	threshold, err := parseThreshold(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clusters, err := h.svc.ListDuplicateClusters(r.Context(), threshold)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing duplicate clusters", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Duplicate clusters listed", "count", len(clusters), "threshold", threshold)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+// ListSimilarQuestions returns the questions that look like the given one.
+func (h *DuplicateHandler) ListSimilarQuestions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing similar questions")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	threshold, err := parseThreshold(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	similar, err := h.svc.FindSimilarToQuestion(r.Context(), idUUID, threshold)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing similar questions", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "question not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(similar)
+}
+
+// MergeDuplicates keeps one question of a cluster and sends the others to the trash.
+func (h *DuplicateHandler) MergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Merging duplicate questions")
+
+	var body struct {
+		KeepID       pgtype.UUID   `json:"keep_id"`
+		DuplicateIDs []pgtype.UUID `json:"duplicate_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	kept, err := h.svc.MergeDuplicates(r.Context(), body.KeepID, body.DuplicateIDs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error merging duplicates", "error", err)
+		switch {
+		case errors.Is(err, service.ErrInvalidMerge):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "question not found", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Duplicates merged", "kept_id", kept.ID, "merged", len(body.DuplicateIDs))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kept)
+}
+
+func parseThreshold(r *http.Request) (float32, error) {
+	v := r.URL.Query().Get("threshold")
+	if v == "" {
+		return service.DefaultDuplicateThreshold, nil
+	}
+	threshold, err := strconv.ParseFloat(v, 32)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return 0, errors.New("threshold must be a number between 0 and 1")
+	}
+	return float32(threshold), nil
+}
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/JeanGrijp/AutoBanca/internal/service"
@@ -31,13 +34,18 @@ func (h *ExamHandler) GenerateExam(w http.ResponseWriter, r *http.Request) {
 			QuestionCount int32    `json:"question_count"`
 			Topics        []string `json:"topics,omitempty"`
 		} `json:"subjects"`
-		Difficulty   *string `json:"difficulty"`
-		Level        *string `json:"level"`
-		Modality     *string `json:"modality"`
-		Position     *string `json:"position"`
-		FieldOfStudy *string `json:"field_of_study"`
-		MinYear      *int32  `json:"min_year"`
-		MaxYear      *int32  `json:"max_year"`
+		Difficulty     *string  `json:"difficulty"`
+		Level          *string  `json:"level"`
+		Modality       *string  `json:"modality"`
+		Position       *string  `json:"position"`
+		FieldOfStudy   *string  `json:"field_of_study"`
+		MinYear        *int32   `json:"min_year"`
+		MaxYear        *int32   `json:"max_year"`
+		Board          *string  `json:"board"`
+		MinCorrectRate *float64 `json:"min_correct_rate"`
+		MaxCorrectRate *float64 `json:"max_correct_rate"`
+		MinResponses   *int32   `json:"min_responses"`
+		ShuffleChoices bool     `json:"shuffle_choices"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -65,19 +73,24 @@ func (h *ExamHandler) GenerateExam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filters := service.GenerateExamFilters{
-		Subjects:     subjects,
-		Difficulty:   stringToPgText(body.Difficulty),
-		Level:        stringToPgText(body.Level),
-		Modality:     stringToPgText(body.Modality),
-		Position:     stringToPgText(body.Position),
-		FieldOfStudy: stringToPgText(body.FieldOfStudy),
-		MinYear:      int32ToPgInt4(body.MinYear),
-		MaxYear:      int32ToPgInt4(body.MaxYear),
+		Subjects:       subjects,
+		Difficulty:     stringToPgText(body.Difficulty),
+		Level:          stringToPgText(body.Level),
+		Modality:       stringToPgText(body.Modality),
+		Position:       stringToPgText(body.Position),
+		FieldOfStudy:   stringToPgText(body.FieldOfStudy),
+		MinYear:        int32ToPgInt4(body.MinYear),
+		MaxYear:        int32ToPgInt4(body.MaxYear),
+		Board:          stringToPgText(body.Board),
+		MinCorrectRate: float64ToPgFloat8(body.MinCorrectRate),
+		MaxCorrectRate: float64ToPgFloat8(body.MaxCorrectRate),
+		MinResponses:   int32ToPgInt4(body.MinResponses),
+		ShuffleChoices: body.ShuffleChoices,
 	}
 
 	slog.InfoContext(r.Context(), "Filters created, calling service to generate exam")
 
-	pdfBytes, err := h.svc.GenerateExam(r.Context(), filters)
+	exam, pdfBytes, err := h.svc.GenerateExam(r.Context(), filters)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "Error generating exam", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -90,13 +103,82 @@ func (h *ExamHandler) GenerateExam(w http.ResponseWriter, r *http.Request) {
 
 	examName := fmt.Sprintf("%s_exam_%s_%s_%s.pdf", "AutoBanca", timeStamp.Format("2006-01-02"), timeStamp.Format("15-04-05"), timeStamp.Format("000"))
 
-	slog.InfoContext(r.Context(), "Exam generated successfully", "exam_name", examName)
+	slog.InfoContext(r.Context(), "Exam generated successfully", "exam_name", examName, "exam_id", exam.ID)
 
+	w.Header().Set("X-Exam-ID", exam.ID.String())
 	w.Header().Set("Content-Type", "application/pdf")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", examName))
 	w.Write(pdfBytes)
 }
 
+// ListExams lists the exams generated so far.
+func (h *ExamHandler) ListExams(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing exams")
+
+	exams, err := h.svc.ListExams(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing exams", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(exams)
+}
+
+// GetExam returns a generated exam with the exact revision of every question.
+func (h *ExamHandler) GetExam(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting exam")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	exam, err := h.svc.GetExam(r.Context(), idUUID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting exam", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "exam not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(exam)
+}
+
+// RenderExam re-renders the PDF of a generated exam from the stored revisions.
+func (h *ExamHandler) RenderExam(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Rendering exam")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pdfBytes, err := h.svc.RenderExam(r.Context(), idUUID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error rendering exam", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "exam not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"AutoBanca_exam_%s.pdf\"", idUUID.String()))
+	w.Write(pdfBytes)
+}
+
 func stringToPgText(s *string) pgtype.Text {
 	if s == nil || *s == "" {
 		return pgtype.Text{Valid: false}
@@ -110,3 +192,10 @@ func int32ToPgInt4(i *int32) pgtype.Int4 {
 	}
 	return pgtype.Int4{Int32: *i, Valid: true}
 }
+
+func float64ToPgFloat8(f *float64) pgtype.Float8 {
+	if f == nil {
+		return pgtype.Float8{Valid: false}
+	}
+	return pgtype.Float8{Float64: *f, Valid: true}
+}
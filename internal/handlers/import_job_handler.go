@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type ImportJobHandler struct {
+	svc *service.ImportJobService
+}
+
+func NewImportJobHandler(svc *service.ImportJobService) *ImportJobHandler {
+	return &ImportJobHandler{svc: svc}
+}
+
+// SubmitImport queues the uploaded "file" for import in the background and
+// answers right away with the job. It accepts the same query parameters as
+// POST /questions/import. The file is streamed instead of parsed as a
+// whole form, so it is only limited by service.MaxImportFileSize.
+func (h *ImportJobHandler) SubmitImport(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Submitting import job")
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var (
+		filename string
+		content  []byte
+	)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		filename = part.FileName()
+		content, err = io.ReadAll(io.LimitReader(part, service.MaxImportFileSize+1))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		break
+	}
+	if content == nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	if len(content) > service.MaxImportFileSize {
+		http.Error(w, fmt.Sprintf("file larger than %d MB", service.MaxImportFileSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	job, err := h.svc.SubmitImport(r.Context(), filename, content, importOptions(r))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error submitting import job", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Import job submitted", "job_id", job.ID, "filename", filename, "bytes", len(content))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *ImportJobHandler) ListImports(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing import jobs")
+
+	jobs, err := h.svc.ListImportJobs(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing import jobs", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// GetImport returns the progress of an import job: rows processed, created,
+// ignored and failed, and the report up to the last imported row.
+func (h *ImportJobHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting import job")
+
+	jobID := pgtype.UUID{}
+	if err := jobID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.svc.GetImportJob(r.Context(), jobID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting import job", "error", err)
+		writeImportJobError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *ImportJobHandler) CancelImport(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Cancelling import job")
+
+	jobID := pgtype.UUID{}
+	if err := jobID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.svc.CancelImportJob(r.Context(), jobID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error cancelling import job", "error", err)
+		writeImportJobError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Import job cancellation requested", "job_id", jobID, "status", job.Status)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// DownloadImportErrors returns the failing lines of an import job as CSV:
+// the line number, its errors and the original columns, so the lines can
+// be fixed in a spreadsheet and submitted again. Spreadsheet imports also
+// get the sheet of each line.
+func (h *ImportJobHandler) DownloadImportErrors(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Downloading import errors")
+
+	jobID := pgtype.UUID{}
+	if err := jobID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.ImportJobReport(r.Context(), jobID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting import report", "error", err)
+		writeImportJobError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"AutoBanca_import_%s_errors.csv\"", jobID.String()))
+	withSheet := slices.ContainsFunc(report.Detalhes, func(d service.ImportRowError) bool { return d.Aba != "" })
+	out := csv.NewWriter(w)
+	header := []string{"linha", "erros"}
+	if withSheet {
+		header = append([]string{"aba"}, header...)
+	}
+	out.Write(append(header, report.ColunasCSV...))
+	for _, detail := range report.Detalhes {
+		record := []string{strconv.Itoa(detail.Linha), strings.Join(detail.Erros, "; ")}
+		if withSheet {
+			record = append([]string{detail.Aba}, record...)
+		}
+		out.Write(append(record, detail.Valores...))
+	}
+	out.Flush()
+}
+
+func writeImportJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrImportJobClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "import job not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type IRTHandler struct {
+	svc *service.IRTService
+}
+
+func NewIRTHandler(svc *service.IRTService) *IRTHandler {
+	return &IRTHandler{svc: svc}
+}
+
+// Calibrate estimates IRT parameters from the recorded exam answers. The
+// body holds the "model", "2PL" or "3PL", and optionally "min_responses",
+// the answers a question needs to be calibrated.
+func (h *IRTHandler) Calibrate(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Calibrating IRT parameters")
+
+	var body struct {
+		Model        string `json:"model"`
+		MinResponses int    `json:"min_responses"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.svc.Calibrate(r.Context(), body.Model, body.MinResponses)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error calibrating IRT parameters", "error", err)
+		writeIRTError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "IRT parameters calibrated", "model", summary.Model, "items", summary.Items, "persons", summary.Persons, "converged", summary.Converged)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+func (h *IRTHandler) ListItemParameters(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing IRT item parameters")
+
+	params, err := h.svc.ListItemParameters(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing IRT item parameters", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(params)
+}
+
+func (h *IRTHandler) GetItemParameters(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting IRT item parameters")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	params, err := h.svc.GetItemParameters(r.Context(), questionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting IRT item parameters", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "question not calibrated", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(params)
+}
+
+// StartCAT opens an adaptive test and returns its first question. The body
+// holds the "candidate" and optionally "subject_id", "max_items" and
+// "target_se", the standard error at which the test stops.
+func (h *IRTHandler) StartCAT(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Starting adaptive test")
+
+	var body struct {
+		Candidate string  `json:"candidate"`
+		SubjectID string  `json:"subject_id"`
+		MaxItems  int32   `json:"max_items"`
+		TargetSE  float64 `json:"target_se"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input := service.CATInput{Candidate: body.Candidate, MaxItems: body.MaxItems, TargetSE: body.TargetSE}
+	if body.SubjectID != "" {
+		if err := input.SubjectID.Scan(body.SubjectID); err != nil {
+			slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+			http.Error(w, "invalid subject_id format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	state, err := h.svc.StartCAT(r.Context(), input)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error starting adaptive test", "error", err)
+		writeIRTError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Adaptive test started", "session_id", state.Session.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(state)
+}
+
+// AnswerCAT answers the pending question of an adaptive test. The body
+// holds the "choice_id", empty for a blank answer. The response carries the
+// next question or, once the test is finished, the final theta score.
+func (h *IRTHandler) AnswerCAT(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Answering adaptive test question")
+
+	sessionID := pgtype.UUID{}
+	if err := sessionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		ChoiceID string `json:"choice_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	choiceID := pgtype.UUID{}
+	if body.ChoiceID != "" {
+		if err := choiceID.Scan(body.ChoiceID); err != nil {
+			slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+			http.Error(w, "invalid choice_id format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	state, err := h.svc.AnswerCAT(r.Context(), sessionID, choiceID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error answering adaptive test question", "error", err)
+		writeIRTError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Adaptive test answer recorded", "session_id", sessionID, "status", state.Session.Status, "theta", state.Session.Theta, "se", state.Session.Se)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+func (h *IRTHandler) GetCAT(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting adaptive test")
+
+	sessionID := pgtype.UUID{}
+	if err := sessionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	state, err := h.svc.GetCAT(r.Context(), sessionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting adaptive test", "error", err)
+		writeIRTError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+func writeIRTError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCalibration), errors.Is(err, service.ErrInvalidCAT):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type LegalReferenceHandler struct {
+	svc *service.LegalReferenceService
+}
+
+func NewLegalReferenceHandler(svc *service.LegalReferenceService) *LegalReferenceHandler {
+	return &LegalReferenceHandler{svc: svc}
+}
+
+func (h *LegalReferenceHandler) ListLegalReferences(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing legal references")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	refs, err := h.svc.ListLegalReferences(r.Context(), questionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing legal references", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refs)
+}
+
+// AddLegalReference attaches a law, and optionally an article and a
+// paragraph, to the question in the path.
+func (h *LegalReferenceHandler) AddLegalReference(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Adding legal reference")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body service.LegalReference
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ref, err := h.svc.AddLegalReference(r.Context(), questionID, body)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error adding legal reference", "error", err)
+		writeLegalReferenceError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Legal reference added", "question_id", questionID, "law", ref.Law)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ref)
+}
+
+func (h *LegalReferenceHandler) DeleteLegalReference(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Deleting legal reference")
+
+	questionID, refID := pgtype.UUID{}, pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+	if err := refID.Scan(chi.URLParam(r, "ref_id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteLegalReference(r.Context(), questionID, refID); err != nil {
+		slog.ErrorContext(r.Context(), "Error deleting legal reference", "error", err)
+		writeLegalReferenceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListQuestionsCiting returns the questions citing the "law" query
+// parameter, narrowed by "article" and "paragraph" when given.
+func (h *LegalReferenceHandler) ListQuestionsCiting(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing questions by legal reference")
+
+	query := r.URL.Query()
+	ref := service.LegalReference{
+		Law:       query.Get("law"),
+		Article:   query.Get("article"),
+		Paragraph: query.Get("paragraph"),
+	}
+
+	questions, err := h.svc.ListQuestionsCiting(r.Context(), ref)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing questions by legal reference", "error", err)
+		writeLegalReferenceError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions by legal reference listed", "law", ref.Law, "count", len(questions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// MarkForReview sends every question citing the reference in the body
+// back to review with the optional "comment". The reviewer is the request
+// user (X-User header).
+func (h *LegalReferenceHandler) MarkForReview(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Marking questions for review by legal reference")
+
+	var body struct {
+		service.LegalReference
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	questions, err := h.svc.MarkForReview(r.Context(), body.LegalReference, body.Comment)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error marking questions for review", "error", err)
+		writeLegalReferenceError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions marked for review", "law", body.Law, "count", len(questions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"marked":    len(questions),
+		"questions": questions,
+	})
+}
+
+func writeLegalReferenceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidLegalReference):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "duplicate key"):
+		http.Error(w, "legal reference already attached to the question", http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
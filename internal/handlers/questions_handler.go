@@ -1,15 +1,17 @@
 package handlers
 
 import (
-	"encoding/csv"
+	"bytes"
 	"encoding/json"
 	"errors"
-	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
@@ -39,6 +41,9 @@ func (h *QuestionHandler) CreateQuestion(w http.ResponseWriter, r *http.Request)
 		Modality     pgtype.Text `json:"modality"`
 		PracticeArea pgtype.Text `json:"practice_area"`
 		FieldOfStudy pgtype.Text `json:"field_of_study"`
+		Explanation  pgtype.Text `json:"explanation"`
+		Board        pgtype.Text `json:"board"`
+		ChoiceCount  pgtype.Int4 `json:"choice_count"`
 	}
 
 	slog.InfoContext(r.Context(), "Decoding request body")
@@ -58,10 +63,13 @@ func (h *QuestionHandler) CreateQuestion(w http.ResponseWriter, r *http.Request)
 		Modality:     body.Modality,
 		PracticeArea: body.PracticeArea,
 		FieldOfStudy: body.FieldOfStudy,
-	})
+		Explanation:  body.Explanation,
+		Board:        body.Board,
+		ChoiceCount:  body.ChoiceCount,
+	}, r.URL.Query().Get("force") == "true")
 	if err != nil {
 		slog.ErrorContext(r.Context(), "Error creating question", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeQuestionWriteError(w, err)
 		return
 	}
 
@@ -72,21 +80,29 @@ func (h *QuestionHandler) CreateQuestion(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(question)
 }
 
-type importError struct {
-	Linha   int      `json:"linha"`
-	Erros   []string `json:"erros"`
-	Valores []string `json:"valores,omitempty"`
-}
-
-type importResponse struct {
-	Total      int           `json:"total"`
-	Criadas    int           `json:"criadas"`
-	Ignoradas  int           `json:"ignoradas"`
-	Falharam   int           `json:"falharam"`
-	Detalhes   []importError `json:"detalhes"`
-	ColunasCSV []string      `json:"colunas_csv"`
-}
-
+// ImportQuestionsCSV creates questions from the uploaded "file", a CSV or
+// an XLSX spreadsheet with the same columns; a spreadsheet may have one
+// sheet per subject, and its errors point to the sheet and cell. JSON Lines
+// files are imported as by ImportQuestionsJSONL. Rows with an id, as
+// downloaded from ExportQuestionsCSV, update that question, and so do rows
+// whose external_id, the ID of the question in the system the file comes
+// from, was imported before; with ?deactivate_missing=true, questions of
+// the file's subjects with an external_id the file no longer lists are moved
+// to the trash, and a file without any external_id is refused. Rows
+// whose modality is Certo/Errado leave the choices empty and answer C or E
+// in correct_choice; the other rows carry their choices and the letter of
+// the correct one, so one file may mix both formats. Each row names its
+// topic either by topic_id or by the subject and topic columns; with
+// ?create_missing=true, subjects and topics not found by name are created
+// and listed in the response. With ?dry_run=true every row is
+// validated and checked for duplicates, including against earlier rows of
+// the file, but nothing is written; the response previews the questions
+// that would be created. With ?atomic=true the whole file runs in a single
+// transaction that is only committed when no row fails; otherwise nothing
+// is kept and every failing line is still reported. The encoding and
+// delimiter of a CSV are detected unless given, as in
+// ?encoding=windows-1252&delimiter=%3B, and the header may use Portuguese
+// column names.
 func (h *QuestionHandler) ImportQuestionsCSV(w http.ResponseWriter, r *http.Request) {
 	slog.InfoContext(r.Context(), "Importing questions from CSV")
 
@@ -95,188 +111,288 @@ func (h *QuestionHandler) ImportQuestionsCSV(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	file, _, err := r.FormFile("file")
+	file, header, err := r.FormFile("file")
 	if err != nil {
 		http.Error(w, "file is required", http.StatusBadRequest)
 		return
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.TrimLeadingSpace = true
+	report, err := h.isvc.ImportFile(r.Context(), header.Filename, file, importOptions(r))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error importing questions", "error", err)
+		if errors.Is(err, service.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions imported", "total", report.Total, "created", report.Criadas, "failed", report.Falharam)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
 
-	// Novo formato com 6 colunas extras para as alternativas
-	// choice_a, choice_b, choice_c, choice_d, choice_e, correct_choice (A-E)
-	expectedHeaders := []string{
-		"statement", "year", "topic_id", "position", "level", "difficulty",
-		"modality", "practice_area", "field_of_study",
-		"choice_a", "choice_b", "choice_c", "choice_d", "choice_e", "correct_choice",
+// ExportQuestionsCSV downloads the questions matching the same filters as
+// ListQuestionsByFilters, given as query parameters, in the CSV import
+// layout with an id column. Importing the edited file updates those
+// questions instead of creating new ones.
+func (h *QuestionHandler) ExportQuestionsCSV(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Exporting questions as CSV")
+
+	filters, err := parseQuestionFilterQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	resp := importResponse{ColunasCSV: expectedHeaders}
-	line := 0
-	firstRow, err := reader.Read()
-	if err == io.EOF {
-		http.Error(w, "csv vazio", http.StatusBadRequest)
+	var buf bytes.Buffer
+	if err := h.isvc.ExportCSV(r.Context(), filters, &buf); err != nil {
+		slog.ErrorContext(r.Context(), "Error exporting questions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"AutoBanca_questions.csv\"")
+	buf.WriteTo(w)
+}
+
+// ImportQuestionsJSONL creates questions from a JSON Lines request body,
+// one question per line with its choices, legal references and metadata, as
+// returned by ExportQuestionsJSONL. The body is read as it arrives instead
+// of as an upload, so large banks are not held in memory. It accepts the
+// same query parameters as ImportQuestionsCSV. Imported questions wait for
+// review as pending unless ?keep_review_status=true keeps the
+// review_status of each line.
+func (h *QuestionHandler) ImportQuestionsJSONL(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Importing questions from JSON Lines")
+
+	report, err := h.isvc.ImportJSONL(r.Context(), r.Body, importOptions(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		slog.ErrorContext(r.Context(), "Error importing questions", "error", err)
+		if errors.Is(err, service.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	line++
 
-	row := firstRow
-	if isHeaderRow(firstRow, expectedHeaders) {
-		row, err = reader.Read()
-		if err == io.EOF {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(resp)
-			return
+	slog.InfoContext(r.Context(), "Questions imported", "total", report.Total, "created", report.Criadas, "failed", report.Falharam)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ExportQuestionsJSONL streams the whole bank as JSON Lines, one question per
+// line with its subject and topic names, choices in order, legal references
+// and metadata. Importing the file with ImportQuestionsJSONL and
+// ?keep_review_status=true recreates the questions as they were, in this or
+// another environment.
+func (h *QuestionHandler) ExportQuestionsJSONL(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Exporting questions as JSON Lines")
+
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	exported := 0
+	err := h.isvc.ExportQuestions(r.Context(), func(records []service.QuestionRecord) error {
+		if exported == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", "attachment; filename=\"AutoBanca_questions.jsonl\"")
 		}
-		if err != nil {
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		exported += len(records)
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error exporting questions", "error", err, "exported", exported)
+		// Depois da primeira linha o status já foi enviado
+		if exported == 0 {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions exported", "total", exported)
+	if exported == 0 {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+}
+
+// importOptions reads the create_missing, dry_run, atomic, encoding,
+// delimiter, deactivate_missing and keep_review_status query parameters of
+// an import.
+func importOptions(r *http.Request) service.ImportOptions {
+	query := r.URL.Query()
+	return service.ImportOptions{
+		CreateMissing:     query.Get("create_missing") == "true",
+		DryRun:            isDryRun(r),
+		Atomic:            query.Get("atomic") == "true",
+		Encoding:          query.Get("encoding"),
+		Delimiter:         query.Get("delimiter"),
+		DeactivateMissing: query.Get("deactivate_missing") == "true",
+		KeepReviewStatus:  query.Get("keep_review_status") == "true",
+	}
+}
+
+// AuditAnswerKeys lists the questions whose answer key or content is
+// broken: empty statement, empty choices, wrong number of choices, or not
+// exactly one correct choice. These questions are left out of exams.
+func (h *QuestionHandler) AuditAnswerKeys(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Auditing answer keys")
+
+	reports, err := h.svc.AuditAnswerKeys(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error auditing answer keys", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Answer keys audited", "invalid", len(reports))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// BulkUpdateQuestions applies the same metadata changes to many questions
+// in one transaction. The body selects the questions either by "ids" or by
+// a "filter" with the fields accepted by ListQuestionsByFilters, and
+// "changes" holds the new values. With ?dry_run=true nothing is saved and
+// the response previews the affected questions.
+func (h *QuestionHandler) BulkUpdateQuestions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Bulk updating questions")
+
+	var body struct {
+		service.BulkSelection
+		Changes service.BulkChanges `json:"changes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.BulkUpdateQuestions(r.Context(), body.BulkSelection, body.Changes, isDryRun(r))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error bulk updating questions", "error", err)
+		if errors.Is(err, service.ErrInvalidBulkUpdate) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		line++
-	}
-
-	for {
-		if len(row) != len(expectedHeaders) {
-			resp.Total++
-			resp.Falharam++
-			resp.Detalhes = append(resp.Detalhes, importError{
-				Linha:   line,
-				Erros:   []string{"quantidade de colunas inválida (esperado: 15)"},
-				Valores: row,
-			})
-		} else {
-			resp.Total++
-			erros := []string{}
-
-			statement := strings.TrimSpace(row[0])
-			yearStr := strings.TrimSpace(row[1])
-			topicIDStr := strings.TrimSpace(row[2])
-			position := strings.TrimSpace(row[3])
-			level := strings.TrimSpace(row[4])
-			difficulty := strings.TrimSpace(row[5])
-			modality := strings.TrimSpace(row[6])
-			practiceArea := strings.TrimSpace(row[7])
-			fieldOfStudy := strings.TrimSpace(row[8])
-
-			// Novas colunas para alternativas
-			choiceA := strings.TrimSpace(row[9])
-			choiceB := strings.TrimSpace(row[10])
-			choiceC := strings.TrimSpace(row[11])
-			choiceD := strings.TrimSpace(row[12])
-			choiceE := strings.TrimSpace(row[13])
-			correctChoice := strings.ToUpper(strings.TrimSpace(row[14]))
-
-			// Valida campos obrigatórios da questão
-			if statement == "" || yearStr == "" || topicIDStr == "" || position == "" || level == "" || difficulty == "" || modality == "" || practiceArea == "" || fieldOfStudy == "" {
-				erros = append(erros, "todos os campos da questão são obrigatórios")
-			}
+		writeQuestionWriteError(w, err)
+		return
+	}
 
-			// Valida alternativas
-			if choiceA == "" || choiceB == "" || choiceC == "" || choiceD == "" || choiceE == "" {
-				erros = append(erros, "todas as 5 alternativas (A-E) são obrigatórias")
-			}
+	slog.InfoContext(r.Context(), "Questions bulk updated", "affected", result.Affected, "dry_run", result.DryRun)
 
-			// Valida correct_choice
-			if correctChoice != "A" && correctChoice != "B" && correctChoice != "C" && correctChoice != "D" && correctChoice != "E" {
-				erros = append(erros, "correct_choice deve ser A, B, C, D ou E")
-			}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
 
-			year64, err := strconv.ParseInt(yearStr, 10, 32)
-			if err != nil {
-				erros = append(erros, "year inválido")
-			}
+// ListBulkUpdates returns the audit log of bulk updates.
+func (h *QuestionHandler) ListBulkUpdates(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing bulk updates")
 
-			topicID := pgtype.UUID{}
-			if err := topicID.Scan(topicIDStr); err != nil {
-				erros = append(erros, "topic_id inválido")
-			}
+	updates, err := h.svc.ListBulkUpdates(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing bulk updates", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
-			if len(erros) == 0 {
-				question := db.Question{
-					Statement:    statement,
-					Year:         int32(year64),
-					TopicID:      topicID,
-					Position:     pgtype.Text{String: position, Valid: true},
-					Level:        pgtype.Text{String: level, Valid: true},
-					Difficulty:   pgtype.Text{String: difficulty, Valid: true},
-					Modality:     pgtype.Text{String: modality, Valid: true},
-					PracticeArea: pgtype.Text{String: practiceArea, Valid: true},
-					FieldOfStudy: pgtype.Text{String: fieldOfStudy, Valid: true},
-				}
-
-				// Monta as choices com o indicador de qual é correta
-				choices := []service.ChoiceInput{
-					{Text: choiceA, IsCorrect: correctChoice == "A"},
-					{Text: choiceB, IsCorrect: correctChoice == "B"},
-					{Text: choiceC, IsCorrect: correctChoice == "C"},
-					{Text: choiceD, IsCorrect: correctChoice == "D"},
-					{Text: choiceE, IsCorrect: correctChoice == "E"},
-				}
-
-				// Usa o ImportService com transação para criar questão + alternativas atomicamente
-				input := service.QuestionWithChoicesInput{
-					Question: question,
-					Choices:  choices,
-				}
-				_, _, createErr := h.isvc.CreateQuestionWithChoices(r.Context(), input)
-				if createErr != nil {
-					// Verifica se é erro de duplicidade
-					if errors.Is(createErr, service.ErrQuestionAlreadyExists) {
-						resp.Ignoradas++
-					} else {
-						erros = append(erros, createErr.Error())
-					}
-				} else {
-					resp.Criadas++
-				}
-			}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updates)
+}
 
-			if len(erros) > 0 {
-				resp.Falharam++
-				resp.Detalhes = append(resp.Detalhes, importError{
-					Linha:   line,
-					Erros:   erros,
-					Valores: row,
-				})
-			}
-		}
+// SearchQuestions runs a full-text search over statements, choices and
+// explanations. The "q" query parameter holds the search terms; the other
+// query parameters are the same metadata filters accepted by ListQuestionsByFilters,
+// plus "limit" (default 20, max 100) and "offset".
+func (h *QuestionHandler) SearchQuestions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Searching questions")
+
+	query := r.URL.Query()
+	terms := strings.TrimSpace(query.Get("q"))
+	if terms == "" {
+		http.Error(w, "q query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	filters, err := parseQuestionFilterQuery(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		row, err = reader.Read()
-		if err == io.EOF {
-			break
+	limit, offset := int64(20), int64(0)
+	if v := query.Get("limit"); v != "" {
+		limit, err = strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 1 || limit > 100 {
+			http.Error(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
 		}
-		if err != nil {
-			resp.Total++
-			resp.Falharam++
-			resp.Detalhes = append(resp.Detalhes, importError{
-				Linha: line + 1,
-				Erros: []string{err.Error()},
-			})
-			break
+	}
+	if v := query.Get("offset"); v != "" {
+		offset, err = strconv.ParseInt(v, 10, 32)
+		if err != nil || offset < 0 {
+			http.Error(w, "offset must be a non-negative integer", http.StatusBadRequest)
+			return
 		}
-		line++
 	}
 
+	result, err := h.svc.SearchQuestions(r.Context(), terms, filters, int32(limit), int32(offset))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error searching questions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions searched", "q", terms, "total", result.Total)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	json.NewEncoder(w).Encode(result)
 }
 
-func isHeaderRow(row []string, expected []string) bool {
-	if len(row) != len(expected) {
-		return false
+// parseQuestionFilterQuery reads the metadata filters from query parameters.
+func parseQuestionFilterQuery(query url.Values) (service.QuestionFilter, error) {
+	var filters service.QuestionFilter
+	text := func(name string) *pgtype.Text {
+		if v := query.Get(name); v != "" {
+			return &pgtype.Text{String: v, Valid: true}
+		}
+		return nil
 	}
-	for i, v := range row {
-		if strings.ToLower(strings.TrimSpace(v)) != expected[i] {
-			return false
+	if v := query.Get("year"); v != "" {
+		year, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return filters, errors.New("year must be an integer")
 		}
+		filters.Year = &pgtype.Int4{Int32: int32(year), Valid: true}
 	}
-	return true
+	if v := query.Get("topic_id"); v != "" {
+		topicID := pgtype.UUID{}
+		if err := topicID.Scan(v); err != nil {
+			return filters, errors.New("topic_id must be a UUID")
+		}
+		filters.TopicID = &topicID
+	}
+	filters.Position = text("position")
+	filters.Level = text("level")
+	filters.Difficulty = text("difficulty")
+	filters.Modality = text("modality")
+	filters.PracticeArea = text("practice_area")
+	filters.FieldOfStudy = text("field_of_study")
+	return filters, nil
 }
 
 func (h *QuestionHandler) ListQuestionsByFilters(w http.ResponseWriter, r *http.Request) {
@@ -356,9 +472,15 @@ func (h *QuestionHandler) DeleteQuestion(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if isDryRun(r) {
+		impact, err := h.svc.DeleteQuestionImpact(r.Context(), body.ID)
+		writeDeleteImpact(w, r, impact, err)
+		return
+	}
+
 	if err := h.svc.DeleteQuestion(r.Context(), body.ID); err != nil {
 		slog.ErrorContext(r.Context(), "Error deleting question", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeTrashError(w, err)
 		return
 	}
 
@@ -381,6 +503,9 @@ func (h *QuestionHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request)
 		Modality     pgtype.Text `json:"modality"`
 		PracticeArea pgtype.Text `json:"practice_area"`
 		FieldOfStudy pgtype.Text `json:"field_of_study"`
+		Explanation  pgtype.Text `json:"explanation"`
+		Board        pgtype.Text `json:"board"`
+		ChoiceCount  pgtype.Int4 `json:"choice_count"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -400,10 +525,13 @@ func (h *QuestionHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request)
 		Modality:     body.Modality,
 		PracticeArea: body.PracticeArea,
 		FieldOfStudy: body.FieldOfStudy,
+		Explanation:  body.Explanation,
+		Board:        body.Board,
+		ChoiceCount:  body.ChoiceCount,
 	})
 	if err != nil {
 		slog.ErrorContext(r.Context(), "Error updating question", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeQuestionWriteError(w, err)
 		return
 	}
 
@@ -435,3 +563,200 @@ func (h *QuestionHandler) GetQuestion(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(question)
 }
+
+type choiceBody struct {
+	ID         pgtype.UUID `json:"id"`
+	ChoiceText string      `json:"choice_text"`
+	IsCorrect  bool        `json:"is_correct"`
+}
+
+type questionWithChoicesBody struct {
+	Statement    string       `json:"statement"`
+	Year         int32        `json:"year"`
+	TopicID      pgtype.UUID  `json:"topic_id"`
+	Position     pgtype.Text  `json:"position"`
+	Level        pgtype.Text  `json:"level"`
+	Difficulty   pgtype.Text  `json:"difficulty"`
+	Modality     pgtype.Text  `json:"modality"`
+	PracticeArea pgtype.Text  `json:"practice_area"`
+	FieldOfStudy pgtype.Text  `json:"field_of_study"`
+	Explanation  pgtype.Text  `json:"explanation"`
+	Board        pgtype.Text  `json:"board"`
+	ChoiceCount  pgtype.Int4  `json:"choice_count"`
+	Choices      []choiceBody `json:"choices"`
+}
+
+func (b questionWithChoicesBody) input() service.QuestionWithChoicesInput {
+	return service.QuestionWithChoicesInput{
+		Question: db.Question{
+			Statement:    b.Statement,
+			Year:         b.Year,
+			TopicID:      b.TopicID,
+			Position:     b.Position,
+			Level:        b.Level,
+			Difficulty:   b.Difficulty,
+			Modality:     b.Modality,
+			PracticeArea: b.PracticeArea,
+			FieldOfStudy: b.FieldOfStudy,
+			Explanation:  b.Explanation,
+			Board:        b.Board,
+			ChoiceCount:  b.ChoiceCount,
+		},
+		Choices: choiceInputs(b.Choices),
+	}
+}
+
+func choiceInputs(body []choiceBody) []service.ChoiceInput {
+	if body == nil {
+		return nil
+	}
+	choices := make([]service.ChoiceInput, 0, len(body))
+	for _, c := range body {
+		choices = append(choices, service.ChoiceInput{ID: c.ID, Text: c.ChoiceText, IsCorrect: c.IsCorrect})
+	}
+	return choices
+}
+
+type questionWithChoicesResponse struct {
+	Question db.Question `json:"question"`
+	Choices  []db.Choice `json:"choices"`
+}
+
+// CreateQuestionWithChoices creates a question and its choices atomically.
+// The choices are lettered in the order they are sent.
+func (h *QuestionHandler) CreateQuestionWithChoices(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Creating question with choices")
+
+	var body questionWithChoicesBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	question, choices, err := h.svc.CreateQuestionWithChoices(r.Context(), body.input(), r.URL.Query().Get("force") == "true")
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error creating question with choices", "error", err)
+		writeQuestionWriteError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Question with choices created successfully", "question_id", question.ID, "choices", len(choices))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(questionWithChoicesResponse{Question: question, Choices: choices})
+}
+
+// ReplaceQuestionWithChoices overwrites a question and its full list of
+// choices atomically. Choices sent with an "id" keep their identity; the
+// ones left out go to the trash.
+func (h *QuestionHandler) ReplaceQuestionWithChoices(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Replacing question with choices")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body questionWithChoicesBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	question, choices, err := h.svc.ReplaceQuestionWithChoices(r.Context(), idUUID, body.input())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error replacing question with choices", "error", err)
+		writeQuestionWriteError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Question with choices replaced successfully", "question_id", question.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questionWithChoicesResponse{Question: question, Choices: choices})
+}
+
+// PatchQuestionWithChoices updates only the fields present in the body.
+// When "choices" is present it replaces the whole list, as in
+// ReplaceQuestionWithChoices.
+func (h *QuestionHandler) PatchQuestionWithChoices(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Patching question with choices")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Statement    *string      `json:"statement"`
+		Year         *int32       `json:"year"`
+		TopicID      *pgtype.UUID `json:"topic_id"`
+		Position     *pgtype.Text `json:"position"`
+		Level        *pgtype.Text `json:"level"`
+		Difficulty   *pgtype.Text `json:"difficulty"`
+		Modality     *pgtype.Text `json:"modality"`
+		PracticeArea *pgtype.Text `json:"practice_area"`
+		FieldOfStudy *pgtype.Text `json:"field_of_study"`
+		Explanation  *pgtype.Text `json:"explanation"`
+		Board        *pgtype.Text `json:"board"`
+		ChoiceCount  *pgtype.Int4 `json:"choice_count"`
+		Choices      []choiceBody `json:"choices"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	question, choices, err := h.svc.PatchQuestionWithChoices(r.Context(), idUUID, service.QuestionPatch{
+		Statement:    body.Statement,
+		Year:         body.Year,
+		TopicID:      body.TopicID,
+		Position:     body.Position,
+		Level:        body.Level,
+		Difficulty:   body.Difficulty,
+		Modality:     body.Modality,
+		PracticeArea: body.PracticeArea,
+		FieldOfStudy: body.FieldOfStudy,
+		Explanation:  body.Explanation,
+		Board:        body.Board,
+		ChoiceCount:  body.ChoiceCount,
+		Choices:      choiceInputs(body.Choices),
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error patching question with choices", "error", err)
+		writeQuestionWriteError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Question with choices patched successfully", "question_id", question.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questionWithChoicesResponse{Question: question, Choices: choices})
+}
+
+func writeQuestionWriteError(w http.ResponseWriter, err error) {
+	var similar *service.SimilarQuestionsError
+	switch {
+	case errors.As(err, &similar):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error":   err.Error(),
+			"similar": similar.Matches,
+		})
+	case errors.Is(err, service.ErrChoiceCount), errors.Is(err, service.ErrInvalidChoices), errors.Is(err, service.ErrUnknownTopic):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "question not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
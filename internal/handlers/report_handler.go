@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type ReportHandler struct {
+	svc *service.ReportService
+}
+
+func NewReportHandler(svc *service.ReportService) *ReportHandler {
+	return &ReportHandler{svc: svc}
+}
+
+// ReportQuestion files a report on the question in the path. The body holds
+// the "category", one of wrong_key, typo, outdated or duplicate, and a
+// "comment"; the reporter is the X-User of the request.
+func (h *ReportHandler) ReportQuestion(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Reporting question")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Category string `json:"category"`
+		Comment  string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.ReportQuestion(r.Context(), questionID, body.Category, body.Comment)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error reporting question", "error", err)
+		writeReportError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Question reported", "question_id", questionID, "report_id", result.Report.ID, "suspended", result.Suspended)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *ReportHandler) ListQuestionReports(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing question reports")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	reports, err := h.svc.ListQuestionReports(r.Context(), questionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing question reports", "error", err)
+		writeReportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// ListReports returns the moderation queue, filtered by the "status",
+// "category" and "question_id" query parameters. Without a status it lists
+// the reports still open or in review.
+func (h *ReportHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing reports")
+
+	query := r.URL.Query()
+	status, category := query.Get("status"), query.Get("category")
+	filter := service.ReportFilter{
+		Status:   stringToPgText(&status),
+		Category: stringToPgText(&category),
+	}
+	if v := query.Get("question_id"); v != "" {
+		if err := filter.QuestionID.Scan(v); err != nil {
+			slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+			http.Error(w, "invalid question_id format", http.StatusBadRequest)
+			return
+		}
+	}
+
+	reports, err := h.svc.ListReports(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing reports", "error", err)
+		writeReportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+func (h *ReportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting report")
+
+	reportID := pgtype.UUID{}
+	if err := reportID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.svc.GetReport(r.Context(), reportID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting report", "error", err)
+		writeReportError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ModerateReport moves the report in the path to the "status" in the body.
+// Resolving or dismissing it requires a "resolution" note.
+func (h *ReportHandler) ModerateReport(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Moderating report")
+
+	reportID := pgtype.UUID{}
+	if err := reportID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Status     string `json:"status"`
+		Resolution string `json:"resolution"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.svc.ModerateReport(r.Context(), reportID, body.Status, body.Resolution)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error moderating report", "error", err)
+		writeReportError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Report moderated", "report_id", reportID, "status", result.Report.Status, "suspended", result.Suspended)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// ListSuspensions returns the questions suspended from exam generation
+// because of open reports.
+func (h *ReportHandler) ListSuspensions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing question suspensions")
+
+	suspensions, err := h.svc.ListSuspensions(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing question suspensions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suspensions)
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type ReviewHandler struct {
+	svc *service.ReviewService
+}
+
+func NewReviewHandler(svc *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{svc: svc}
+}
+
+// CountQuestionsForReview returns how many questions each subject has in a
+// review status. It accepts an optional "status" query parameter (default: pending).
+func (h *ReviewHandler) CountQuestionsForReview(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Counting questions for review")
+
+	counts, err := h.svc.CountQuestionsForReview(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error counting questions for review", "error", err)
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
+
+// ListQuestionsForReview lists the questions of a subject waiting for review.
+// It accepts an optional "status" query parameter (default: pending).
+func (h *ReviewHandler) ListQuestionsForReview(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing questions for review")
+
+	subjectUUID := pgtype.UUID{}
+	if err := subjectUUID.Scan(chi.URLParam(r, "subject_id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	questions, err := h.svc.ListQuestionsForReview(r.Context(), subjectUUID, r.URL.Query().Get("status"))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing questions for review", "error", err)
+		writeReviewError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Questions for review listed", "subject_id", subjectUUID, "count", len(questions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(questions)
+}
+
+// ListQuestionReviews returns the review history of a question.
+func (h *ReviewHandler) ListQuestionReviews(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing question reviews")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := h.svc.ListQuestionReviews(r.Context(), idUUID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing question reviews", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
+
+// ReviewQuestion approves, rejects or requests changes on a question.
+// The reviewer is the request user (X-User header).
+func (h *ReviewHandler) ReviewQuestion(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Reviewing question")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Action  string `json:"action"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	question, review, err := h.svc.ReviewQuestion(r.Context(), service.ReviewInput{
+		QuestionID: idUUID,
+		Reviewer:   service.UserFromContext(r.Context()),
+		Action:     body.Action,
+		Comment:    body.Comment,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error reviewing question", "error", err)
+		writeReviewError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Question reviewed", "question_id", question.ID, "action", review.Action, "status", question.ReviewStatus)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"question": question,
+		"review":   review,
+	})
+}
+
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidReview):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "question not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type RevisionHandler struct {
+	svc *service.RevisionService
+}
+
+func NewRevisionHandler(svc *service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{svc: svc}
+}
+
+// ListRevisions returns the revision history of a question, newest first.
+func (h *RevisionHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing question revisions")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.svc.ListRevisions(r.Context(), idUUID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing revisions", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Revisions listed", "question_id", idUUID, "count", len(revisions))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// GetRevision returns a single revision of a question.
+func (h *RevisionHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting question revision")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	rev, err := h.svc.GetRevision(r.Context(), idUUID, int32(revision))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting revision", "error", err)
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+// DiffRevisions compares two revisions of a question.
+// It expects "from" and "to" revision numbers as query parameters.
+func (h *RevisionHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Diffing question revisions")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 32)
+	if err != nil {
+		http.Error(w, "from query parameter is required", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 32)
+	if err != nil {
+		http.Error(w, "to query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.svc.DiffRevisions(r.Context(), idUUID, int32(from), int32(to))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error diffing revisions", "error", err)
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"from":    from,
+		"to":      to,
+		"changes": changes,
+	})
+}
+
+// RestoreRevision brings a question back to the content of an older revision.
+func (h *RevisionHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Restoring question revision")
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	revision, err := strconv.ParseInt(chi.URLParam(r, "revision"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	rev, err := h.svc.RestoreRevision(r.Context(), idUUID, int32(revision))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error restoring revision", "error", err)
+		writeRevisionError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Revision restored", "question_id", idUUID, "from_revision", revision, "new_revision", rev.Revision)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rev)
+}
+
+func writeRevisionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrRevisionNotFound), errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrChoiceCount), errors.Is(err, service.ErrInvalidChoices):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type StatisticsHandler struct {
+	svc *service.StatisticsService
+}
+
+func NewStatisticsHandler(svc *service.StatisticsService) *StatisticsHandler {
+	return &StatisticsHandler{svc: svc}
+}
+
+// RecordAttempt stores a candidate's answers to the exam in the path. The
+// body holds the "candidate" and "answers", one letter per question in exam
+// order, with "" for a blank answer.
+func (h *StatisticsHandler) RecordAttempt(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Recording exam attempt")
+
+	examID := pgtype.UUID{}
+	if err := examID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Candidate string   `json:"candidate"`
+		Answers   []string `json:"answers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	attempt, err := h.svc.RecordAttempt(r.Context(), examID, body.Candidate, body.Answers)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error recording exam attempt", "error", err)
+		switch {
+		case errors.Is(err, service.ErrInvalidAttempt):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, pgx.ErrNoRows):
+			http.Error(w, "exam not found", http.StatusNotFound)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Exam attempt recorded", "exam_id", examID, "attempt_id", attempt.ID, "score", attempt.Score)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+func (h *StatisticsHandler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing exam attempts")
+
+	examID := pgtype.UUID{}
+	if err := examID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	attempts, err := h.svc.ListAttempts(r.Context(), examID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing exam attempts", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attempts)
+}
+
+// GetQuestionStatistics returns the share of correct answers, the
+// point-biserial discrimination and the distractor analysis of a question.
+func (h *StatisticsHandler) GetQuestionStatistics(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Getting question statistics")
+
+	questionID := pgtype.UUID{}
+	if err := questionID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.svc.GetQuestionStatistics(r.Context(), questionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error getting question statistics", "error", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			http.Error(w, "question not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
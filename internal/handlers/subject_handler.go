@@ -130,9 +130,15 @@ func (h *SubjectHandler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	slog.InfoContext(r.Context(), "Deleting subject", "id", id)
 
+	if isDryRun(r) {
+		impact, err := h.svc.DeleteSubjectImpact(r.Context(), id)
+		writeDeleteImpact(w, r, impact, err)
+		return
+	}
+
 	if err := h.svc.DeleteSubject(r.Context(), id); err != nil {
 		slog.ErrorContext(r.Context(), "Error deleting subject", "error", err, "id", id)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeTrashError(w, err)
 		return
 	}
 
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type TaxonomyHandler struct {
+	svc *service.TaxonomyService
+}
+
+func NewTaxonomyHandler(svc *service.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{svc: svc}
+}
+
+type mergeBody struct {
+	TargetID pgtype.UUID `json:"target_id"`
+}
+
+// MergeTopics moves every question of the topic in the path to the topic
+// given as "target_id" and trashes the emptied topic.
+func (h *TaxonomyHandler) MergeTopics(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Merging topics")
+
+	sourceID, body, ok := decodeMerge(w, r)
+	if !ok {
+		return
+	}
+
+	change, err := h.svc.MergeTopics(r.Context(), sourceID, body.TargetID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error merging topics", "error", err)
+		writeTaxonomyError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Topics merged", "source_id", sourceID, "target_id", body.TargetID, "questions", len(change.QuestionIds))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
+
+// MergeSubjects moves the topics of the subject in the path to the subject
+// given as "target_id", merging topics with the same name, and trashes the
+// emptied subject.
+func (h *TaxonomyHandler) MergeSubjects(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Merging subjects")
+
+	sourceID, body, ok := decodeMerge(w, r)
+	if !ok {
+		return
+	}
+
+	change, err := h.svc.MergeSubjects(r.Context(), sourceID, body.TargetID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error merging subjects", "error", err)
+		writeTaxonomyError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Subjects merged", "source_id", sourceID, "target_id", body.TargetID, "questions", len(change.QuestionIds))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
+
+// SplitTopic creates a topic and moves the listed questions of the topic in
+// the path to it. "subject_id" is optional and defaults to the subject of
+// the split topic.
+func (h *TaxonomyHandler) SplitTopic(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Splitting topic")
+
+	sourceID := pgtype.UUID{}
+	if err := sourceID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Name        string        `json:"name"`
+		SubjectID   pgtype.UUID   `json:"subject_id"`
+		QuestionIDs []pgtype.UUID `json:"question_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topic, change, err := h.svc.SplitTopic(r.Context(), sourceID, body.Name, body.SubjectID, body.QuestionIDs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error splitting topic", "error", err)
+		writeTaxonomyError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Topic split", "source_id", sourceID, "topic_id", topic.ID, "questions", len(change.QuestionIds))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"topic":  topic,
+		"change": change,
+	})
+}
+
+// ListTaxonomyChanges returns the log of merges and splits.
+func (h *TaxonomyHandler) ListTaxonomyChanges(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing taxonomy changes")
+
+	changes, err := h.svc.ListTaxonomyChanges(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing taxonomy changes", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+func decodeMerge(w http.ResponseWriter, r *http.Request) (pgtype.UUID, mergeBody, bool) {
+	sourceID := pgtype.UUID{}
+	if err := sourceID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, "invalid ID format", http.StatusBadRequest)
+		return sourceID, mergeBody{}, false
+	}
+	var body mergeBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		slog.ErrorContext(r.Context(), "Error decoding request body", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return sourceID, body, false
+	}
+	if !body.TargetID.Valid {
+		http.Error(w, "target_id is required", http.StatusBadRequest)
+		return sourceID, body, false
+	}
+	return sourceID, body, true
+}
+
+func writeTaxonomyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTaxonomyChange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "topic or subject not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
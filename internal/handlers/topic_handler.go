@@ -132,9 +132,15 @@ func (h *TopicHandler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isDryRun(r) {
+		impact, err := h.svc.DeleteTopicImpact(r.Context(), idUUID)
+		writeDeleteImpact(w, r, impact, err)
+		return
+	}
+
 	if err := h.svc.DeleteTopic(r.Context(), idUUID); err != nil {
 		slog.ErrorContext(r.Context(), "Error deleting topic", "error", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeTrashError(w, err)
 		return
 	}
 	slog.InfoContext(r.Context(), "Topic deleted", "topic_id", idUUID)
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/service"
+)
+
+type TrashHandler struct {
+	svc *service.TrashService
+}
+
+func NewTrashHandler(svc *service.TrashService) *TrashHandler {
+	return &TrashHandler{svc: svc}
+}
+
+// ListTrash returns the subjects, topics, questions and choices in the trash.
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Listing trash")
+
+	trash, err := h.svc.ListTrash(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing trash", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trash)
+}
+
+// RestoreFromTrash brings an entry back together with the children deleted
+// with it. The kind path parameter is subjects, topics, questions or choices.
+func (h *TrashHandler) RestoreFromTrash(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	slog.InfoContext(r.Context(), "Restoring from trash", "kind", kind)
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.Restore(r.Context(), kind, idUUID); err != nil {
+		slog.ErrorContext(r.Context(), "Error restoring from trash", "error", err, "kind", kind, "id", idUUID)
+		writeTrashError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Restored from trash", "kind", kind, "id", idUUID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeFromTrash permanently removes an entry that is in the trash. A
+// subject or topic that still holds active topics or questions answers 409.
+func (h *TrashHandler) PurgeFromTrash(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	slog.InfoContext(r.Context(), "Purging from trash", "kind", kind)
+
+	idUUID := pgtype.UUID{}
+	if err := idUUID.Scan(chi.URLParam(r, "id")); err != nil {
+		slog.ErrorContext(r.Context(), "Error scanning UUID", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.svc.Purge(r.Context(), kind, idUUID); err != nil {
+		slog.ErrorContext(r.Context(), "Error purging from trash", "error", err, "kind", kind, "id", idUUID)
+		writeTrashError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Purged from trash", "kind", kind, "id", idUUID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeTrash permanently removes everything deleted before the "before"
+// query parameter (RFC 3339). Without it the whole trash is emptied.
+func (h *TrashHandler) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	slog.InfoContext(r.Context(), "Purging trash")
+
+	before := time.Now()
+	if v := r.URL.Query().Get("before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, "before must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		before = t
+	}
+
+	purged, err := h.svc.PurgeBefore(r.Context(), before)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error purging trash", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Trash purged", "before", before, "purged", purged)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(purged)
+}
+
+// isDryRun reports whether a delete request only asks for its impact.
+func isDryRun(r *http.Request) bool {
+	return r.URL.Query().Get("dry_run") == "true"
+}
+
+// writeDeleteImpact answers a dry-run delete with the rows it would affect.
+func writeDeleteImpact(w http.ResponseWriter, r *http.Request, impact service.DeleteImpact, err error) {
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error computing delete impact", "error", err)
+		writeTrashError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"dry_run":  true,
+		"affected": impact,
+	})
+}
+
+func writeTrashError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTrashKind):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, pgx.ErrNoRows):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrParentInTrash), errors.Is(err, service.ErrNameInUse), errors.Is(err, service.ErrActiveChildren):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrChoiceCount), errors.Is(err, service.ErrInvalidChoices):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Integrity issues reported by the answer-key audit.
+const (
+	IssueEmptyStatement  = "empty_statement"
+	IssueMissingChoices  = "missing_choices"
+	IssueExtraChoices    = "extra_choices"
+	IssueEmptyChoice     = "empty_choice"
+	IssueNoCorrectChoice = "no_correct_choice"
+	IssueMultipleCorrect = "multiple_correct_choices"
+)
+
+// IntegrityReport describes a question whose content breaks the answer-key
+// rules. Such questions are never picked for an exam.
+type IntegrityReport struct {
+	QuestionID      pgtype.UUID `json:"question_id"`
+	Statement       string      `json:"statement"`
+	ReviewStatus    string      `json:"review_status"`
+	ExpectedChoices int32       `json:"expected_choices"`
+	Choices         int64       `json:"choices"`
+	CorrectChoices  int64       `json:"correct_choices"`
+	Issues          []string    `json:"issues"`
+}
+
+// AuditAnswerKeys scans the bank for questions with an empty statement,
+// empty choices, the wrong number of choices, or not exactly one correct
+// choice.
+func (s *QuestionService) AuditAnswerKeys(ctx context.Context) ([]IntegrityReport, error) {
+	rows, err := s.svc.ListQuestionIntegrityIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]IntegrityReport, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, IntegrityReport{
+			QuestionID:      row.ID,
+			Statement:       row.Statement,
+			ReviewStatus:    row.ReviewStatus,
+			ExpectedChoices: row.ExpectedChoices,
+			Choices:         row.Choices,
+			CorrectChoices:  row.CorrectChoices,
+			Issues: integrityIssues(db.QuestionIntegrity{
+				ExpectedChoices: row.ExpectedChoices,
+				Choices:         row.Choices,
+				CorrectChoices:  row.CorrectChoices,
+				EmptyChoices:    row.EmptyChoices,
+				EmptyStatement:  row.EmptyStatement,
+			}),
+		})
+	}
+	return reports, nil
+}
+
+func integrityIssues(qi db.QuestionIntegrity) []string {
+	issues := []string{}
+	if qi.EmptyStatement {
+		issues = append(issues, IssueEmptyStatement)
+	}
+	switch {
+	case qi.Choices < int64(qi.ExpectedChoices):
+		issues = append(issues, IssueMissingChoices)
+	case qi.Choices > int64(qi.ExpectedChoices):
+		issues = append(issues, IssueExtraChoices)
+	}
+	if qi.EmptyChoices > 0 {
+		issues = append(issues, IssueEmptyChoice)
+	}
+	switch {
+	case qi.CorrectChoices == 0:
+		issues = append(issues, IssueNoCorrectChoice)
+	case qi.CorrectChoices > 1:
+		issues = append(issues, IssueMultipleCorrect)
+	}
+	return issues
+}
+
+// checkAnswerKey enforces the answer-key rules after a mutation, inside the
+// same transaction. A question being built choice by choice may still lack
+// choices or its correct answer, but it can never have more than one
+// correct choice, more choices than expected, or empty choices, and once
+// all its choices exist exactly one of them must be correct.
+func checkAnswerKey(ctx context.Context, q db.Querier, questionID pgtype.UUID) error {
+	qi, err := q.GetQuestionIntegrity(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar gabarito: %w", err)
+	}
+	return answerKeyError(qi)
+}
+
+// checkChoiceChange is checkAnswerKey for the mutation of a single choice:
+// besides the usual rules, a question that had a correct answer before the
+// mutation must keep one. Switching the correct answer takes a question
+// update carrying all its choices.
+func checkChoiceChange(ctx context.Context, q db.Querier, questionID pgtype.UUID, before db.QuestionIntegrity) error {
+	after, err := q.GetQuestionIntegrity(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("erro ao verificar gabarito: %w", err)
+	}
+	return choiceChangeError(before, after)
+}
+
+func answerKeyError(qi db.QuestionIntegrity) error {
+	switch {
+	case qi.CorrectChoices > 1:
+		return fmt.Errorf("%w: a questão ficaria com %d alternativas corretas", ErrInvalidChoices, qi.CorrectChoices)
+	case qi.EmptyChoices > 0:
+		return fmt.Errorf("%w: a questão ficaria com alternativas vazias", ErrInvalidChoices)
+	case qi.Choices > int64(qi.ExpectedChoices):
+		return fmt.Errorf("%w: a questão ficaria com %d alternativas, esperado: %d", ErrChoiceCount, qi.Choices, qi.ExpectedChoices)
+	case qi.Choices == int64(qi.ExpectedChoices) && qi.CorrectChoices == 0:
+		return fmt.Errorf("%w: a questão ficaria sem alternativa correta", ErrInvalidChoices)
+	}
+	return nil
+}
+
+func choiceChangeError(before, after db.QuestionIntegrity) error {
+	if err := answerKeyError(after); err != nil {
+		return err
+	}
+	if before.CorrectChoices > 0 && after.CorrectChoices == 0 {
+		return fmt.Errorf("%w: a questão perderia a alternativa correta; para trocar o gabarito, atualize a questão com todas as alternativas", ErrInvalidChoices)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+func TestIntegrityIssues(t *testing.T) {
+	tests := []struct {
+		name string
+		qi   db.QuestionIntegrity
+		want []string
+	}{
+		{
+			name: "healthy",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 5, Choices: 5, CorrectChoices: 1},
+			want: []string{},
+		},
+		{
+			name: "empty statement",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 2, Choices: 2, CorrectChoices: 1, EmptyStatement: true},
+			want: []string{IssueEmptyStatement},
+		},
+		{
+			name: "missing choices without answer",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 5, Choices: 3},
+			want: []string{IssueMissingChoices, IssueNoCorrectChoice},
+		},
+		{
+			name: "extra choices",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 4, Choices: 5, CorrectChoices: 1},
+			want: []string{IssueExtraChoices},
+		},
+		{
+			name: "empty choice and multiple correct",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 5, Choices: 5, CorrectChoices: 2, EmptyChoices: 1},
+			want: []string{IssueEmptyChoice, IssueMultipleCorrect},
+		},
+		{
+			name: "everything wrong",
+			qi:   db.QuestionIntegrity{ExpectedChoices: 5, Choices: 0, EmptyStatement: true},
+			want: []string{IssueEmptyStatement, IssueMissingChoices, IssueNoCorrectChoice},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := integrityIssues(tt.qi); !slices.Equal(got, tt.want) {
+				t.Errorf("integrityIssues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnswerKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		qi   db.QuestionIntegrity
+		want error
+	}{
+		{"complete", db.QuestionIntegrity{ExpectedChoices: 5, Choices: 5, CorrectChoices: 1}, nil},
+		{"still being built", db.QuestionIntegrity{ExpectedChoices: 5, Choices: 3}, nil},
+		{"complete without answer", db.QuestionIntegrity{ExpectedChoices: 5, Choices: 5}, ErrInvalidChoices},
+		{"multiple correct", db.QuestionIntegrity{ExpectedChoices: 5, Choices: 4, CorrectChoices: 2}, ErrInvalidChoices},
+		{"empty choice", db.QuestionIntegrity{ExpectedChoices: 5, Choices: 4, EmptyChoices: 1}, ErrInvalidChoices},
+		{"too many choices", db.QuestionIntegrity{ExpectedChoices: 4, Choices: 5, CorrectChoices: 1}, ErrChoiceCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := answerKeyError(tt.qi)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("answerKeyError() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("answerKeyError() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChoiceChangeError(t *testing.T) {
+	answered := db.QuestionIntegrity{ExpectedChoices: 5, Choices: 4, CorrectChoices: 1}
+	unanswered := db.QuestionIntegrity{ExpectedChoices: 5, Choices: 4}
+	tests := []struct {
+		name   string
+		before db.QuestionIntegrity
+		after  db.QuestionIntegrity
+		want   error
+	}{
+		{"keeps answer", answered, db.QuestionIntegrity{ExpectedChoices: 5, Choices: 3, CorrectChoices: 1}, nil},
+		{"loses answer while incomplete", answered, db.QuestionIntegrity{ExpectedChoices: 5, Choices: 3}, ErrInvalidChoices},
+		{"unmarks answer", answered, unanswered, ErrInvalidChoices},
+		{"never had an answer", unanswered, db.QuestionIntegrity{ExpectedChoices: 5, Choices: 3}, nil},
+		{"gains second answer", answered, db.QuestionIntegrity{ExpectedChoices: 5, Choices: 4, CorrectChoices: 2}, ErrInvalidChoices},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := choiceChangeError(tt.before, tt.after)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("choiceChangeError() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("choiceChangeError() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+const (
+	// DefaultChoiceCount is used for questions without an override whose
+	// banca is unknown or not registered.
+	DefaultChoiceCount = 5
+	// MinChoiceCount and MaxChoiceCount bound how many choices a multiple
+	// choice question may have (A-B up to A-J).
+	MinChoiceCount = 2
+	MaxChoiceCount = 10
+	// ModalityTrueFalse questions always have two choices.
+	ModalityTrueFalse = "Certo/Errado"
+)
+
+// ErrChoiceCount is returned when a question's choices do not match the
+// number expected for it, or when a choice count is out of range.
+var ErrChoiceCount = errors.New("quantidade de alternativas inválida")
+
+// BoardService manages bancas and their default number of choices.
+type BoardService struct {
+	q db.Querier
+}
+
+// NewBoardService creates a new BoardService.
+func NewBoardService(pool *pgxpool.Pool) *BoardService {
+	return &BoardService{q: db.New(pool)}
+}
+
+func (s *BoardService) CreateBoard(ctx context.Context, name string, choiceCount int32) (db.Board, error) {
+	if err := validateChoiceCount(choiceCount); err != nil {
+		return db.Board{}, err
+	}
+	return s.q.CreateBoard(ctx, db.CreateBoardParams{Name: name, ChoiceCount: choiceCount})
+}
+
+func (s *BoardService) ListBoards(ctx context.Context) ([]db.Board, error) {
+	return s.q.ListBoards(ctx)
+}
+
+func (s *BoardService) GetBoard(ctx context.Context, id pgtype.UUID) (db.Board, error) {
+	return s.q.GetBoard(ctx, id)
+}
+
+func (s *BoardService) UpdateBoard(ctx context.Context, id pgtype.UUID, name string, choiceCount int32) (db.Board, error) {
+	if err := validateChoiceCount(choiceCount); err != nil {
+		return db.Board{}, err
+	}
+	return s.q.UpdateBoard(ctx, db.UpdateBoardParams{ID: id, Name: name, ChoiceCount: choiceCount})
+}
+
+func (s *BoardService) DeleteBoard(ctx context.Context, id pgtype.UUID) error {
+	return s.q.DeleteBoard(ctx, id)
+}
+
+func validateChoiceCount(n int32) error {
+	if n < MinChoiceCount || n > MaxChoiceCount {
+		return fmt.Errorf("%w: deve estar entre %d e %d", ErrChoiceCount, MinChoiceCount, MaxChoiceCount)
+	}
+	return nil
+}
+
+// resolveChoiceCount returns how many choices a question must have: its own
+// override, else two for Certo/Errado, else the default of its banca, else
+// DefaultChoiceCount.
+func resolveChoiceCount(ctx context.Context, q db.Querier, question db.Question) (int, error) {
+	if question.ChoiceCount.Valid {
+		return int(question.ChoiceCount.Int32), nil
+	}
+	if question.Modality.String == ModalityTrueFalse {
+		return MinChoiceCount, nil
+	}
+	if question.Board.Valid && question.Board.String != "" {
+		board, err := q.GetBoardByName(ctx, question.Board.String)
+		switch {
+		case err == nil:
+			return int(board.ChoiceCount), nil
+		case !errors.Is(err, pgx.ErrNoRows):
+			return 0, fmt.Errorf("erro ao buscar banca: %w", err)
+		}
+	}
+	return DefaultChoiceCount, nil
+}
+
+// choiceRange describes the letters of n choices, e.g. "A-E".
+func choiceRange(n int) string {
+	return fmt.Sprintf("A-%c", 'A'+n-1)
+}
@@ -2,25 +2,64 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
 )
 
+// ErrInvalidChoiceOrder is returned when a reorder request does not list
+// every choice of the question exactly once.
+var ErrInvalidChoiceOrder = errors.New("ordem de alternativas inválida")
+
+// ChoiceService manages choices. Every mutation is recorded as a new
+// revision of the owning question.
 type ChoiceService struct {
-	q db.Querier
+	pool *pgxpool.Pool
+	q    db.Querier
 }
 
-func NewChoiceService(q db.Querier) *ChoiceService {
-	return &ChoiceService{q: q}
+func NewChoiceService(pool *pgxpool.Pool) *ChoiceService {
+	return &ChoiceService{pool: pool, q: db.New(pool)}
 }
 
+// CreateChoice adds a choice to a question. It fails with ErrChoiceCount when
+// the question already has all the choices expected for it.
 func (s *ChoiceService) CreateChoice(ctx context.Context, choice db.Choice) (db.Choice, error) {
-	row, err := s.q.CreateChoice(ctx, db.CreateChoiceParams{
-		QuestionID: choice.QuestionID,
-		ChoiceText: choice.ChoiceText,
-		IsCorrect:  choice.IsCorrect,
+	var row db.Choice
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		question, err := qtx.GetQuestion(ctx, choice.QuestionID)
+		if err != nil {
+			return err
+		}
+		expected, err := resolveChoiceCount(ctx, qtx, question)
+		if err != nil {
+			return err
+		}
+		existing, err := qtx.ListChoicesByQuestion(ctx, question.ID)
+		if err != nil {
+			return err
+		}
+		if len(existing) >= expected {
+			return fmt.Errorf("%w: a questão já possui as %d alternativas previstas (%s)", ErrChoiceCount, expected, choiceRange(expected))
+		}
+
+		row, err = qtx.CreateChoice(ctx, db.CreateChoiceParams{
+			QuestionID: choice.QuestionID,
+			ChoiceText: choice.ChoiceText,
+			IsCorrect:  choice.IsCorrect,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkAnswerKey(ctx, qtx, row.QuestionID); err != nil {
+			return err
+		}
+		_, err = recordRevision(ctx, qtx, row.QuestionID, RevisionReasonCreateChoice)
+		return err
 	})
 
 	if err != nil {
@@ -29,23 +68,112 @@ func (s *ChoiceService) CreateChoice(ctx context.Context, choice db.Choice) (db.
 	return row, nil
 }
 
+// ListChoicesByQuestion returns the choices of a question in letter order.
 func (s *ChoiceService) ListChoicesByQuestion(ctx context.Context, questionID pgtype.UUID) ([]db.Choice, error) {
 	return s.q.ListChoicesByQuestion(ctx, questionID)
 }
 
+// ReorderChoices sets the letter order of a question's choices. choiceIDs
+// must list every choice of the question exactly once, first letter first.
+// The new order is recorded as a revision of the question.
+func (s *ChoiceService) ReorderChoices(ctx context.Context, questionID pgtype.UUID, choiceIDs []pgtype.UUID) ([]db.Choice, error) {
+	var choices []db.Choice
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.GetQuestion(ctx, questionID); err != nil {
+			return err
+		}
+		current, err := qtx.ListChoicesByQuestion(ctx, questionID)
+		if err != nil {
+			return err
+		}
+		if len(choiceIDs) != len(current) {
+			return fmt.Errorf("%w: esperado %d alternativas, recebido %d", ErrInvalidChoiceOrder, len(current), len(choiceIDs))
+		}
+		remaining := make(map[pgtype.UUID]bool, len(current))
+		for _, c := range current {
+			remaining[c.ID] = true
+		}
+		for _, id := range choiceIDs {
+			if !remaining[id] {
+				return fmt.Errorf("%w: alternativa repetida ou de outra questão", ErrInvalidChoiceOrder)
+			}
+			delete(remaining, id)
+		}
+
+		if err := setChoicePositions(ctx, qtx, choiceIDs); err != nil {
+			return err
+		}
+		if _, err := recordRevision(ctx, qtx, questionID, RevisionReasonReorder); err != nil {
+			return err
+		}
+		choices, err = qtx.ListChoicesByQuestion(ctx, questionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return choices, nil
+}
+
+// DeleteChoice moves a choice to the trash and records the change as a new
+// revision of its question. The correct choice of a question cannot be
+// deleted on its own.
 func (s *ChoiceService) DeleteChoice(ctx context.Context, id pgtype.UUID) error {
-	return s.q.DeleteChoice(ctx, id)
+	return inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		choice, err := qtx.GetChoice(ctx, id)
+		if err != nil {
+			return err
+		}
+		before, err := qtx.GetQuestionIntegrity(ctx, choice.QuestionID)
+		if err != nil {
+			return err
+		}
+		if err := softDelete(ctx, qtx, TrashKindChoice, id); err != nil {
+			return err
+		}
+		if err := checkChoiceChange(ctx, qtx, choice.QuestionID, before); err != nil {
+			return err
+		}
+		_, err = recordRevision(ctx, qtx, choice.QuestionID, RevisionReasonDeleteChoice)
+		return err
+	})
+}
+
+// DeleteChoiceImpact reports what DeleteChoice would move to the trash.
+func (s *ChoiceService) DeleteChoiceImpact(ctx context.Context, id pgtype.UUID) (DeleteImpact, error) {
+	return deleteImpact(ctx, s.q, TrashKindChoice, id)
 }
 
 func (s *ChoiceService) GetChoice(ctx context.Context, id pgtype.UUID) (db.Choice, error) {
 	return s.q.GetChoice(ctx, id)
 }
 
+// UpdateChoice overwrites a choice. It cannot unmark the only correct choice
+// of a question; the correct answer is switched through the question.
 func (s *ChoiceService) UpdateChoice(ctx context.Context, choice db.Choice) (db.Choice, error) {
-	row, err := s.q.UpdateChoice(ctx, db.UpdateChoiceParams{
-		ID:         choice.ID,
-		ChoiceText: choice.ChoiceText,
-		IsCorrect:  choice.IsCorrect,
+	var row db.Choice
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		current, err := qtx.GetChoice(ctx, choice.ID)
+		if err != nil {
+			return err
+		}
+		before, err := qtx.GetQuestionIntegrity(ctx, current.QuestionID)
+		if err != nil {
+			return err
+		}
+		row, err = qtx.UpdateChoice(ctx, db.UpdateChoiceParams{
+			ID:         choice.ID,
+			ChoiceText: choice.ChoiceText,
+			IsCorrect:  choice.IsCorrect,
+		})
+		if err != nil {
+			return err
+		}
+		if err := checkChoiceChange(ctx, qtx, row.QuestionID, before); err != nil {
+			return err
+		}
+		_, err = recordRevision(ctx, qtx, row.QuestionID, RevisionReasonUpdateChoice)
+		return err
 	})
 
 	if err != nil {
@@ -53,3 +181,13 @@ func (s *ChoiceService) UpdateChoice(ctx context.Context, choice db.Choice) (db.
 	}
 	return row, nil
 }
+
+// setChoicePositions numbers the given choices 0, 1, 2, ... in order.
+func setChoicePositions(ctx context.Context, qtx *db.Queries, choiceIDs []pgtype.UUID) error {
+	for i, id := range choiceIDs {
+		if err := qtx.UpdateChoicePosition(ctx, db.UpdateChoicePositionParams{ID: id, Position: int32(i)}); err != nil {
+			return fmt.Errorf("erro ao ordenar alternativa %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// DefaultDuplicateThreshold is the trigram similarity between normalized
+// statements from which two questions are treated as duplicates.
+const DefaultDuplicateThreshold = 0.8
+
+// maxDuplicatePairs bounds how many similar pairs a cluster listing reads.
+const maxDuplicatePairs = 1000
+
+var (
+	// ErrSimilarQuestion is returned when a near-duplicate of a question
+	// already exists.
+	ErrSimilarQuestion = errors.New("questão semelhante já existe no banco de dados")
+	// ErrInvalidMerge is returned when a merge request is malformed.
+	ErrInvalidMerge = errors.New("mesclagem inválida")
+)
+
+// SimilarQuestionsError lists the existing questions that look like the one
+// being written. It unwraps to ErrSimilarQuestion.
+type SimilarQuestionsError struct {
+	Matches []db.FindSimilarQuestionsRow
+}
+
+func (e *SimilarQuestionsError) Error() string {
+	best := e.Matches[0]
+	return fmt.Sprintf("%s: %d candidata(s), a mais próxima com similaridade %.2f", ErrSimilarQuestion, len(e.Matches), best.Score)
+}
+
+func (e *SimilarQuestionsError) Unwrap() error {
+	return ErrSimilarQuestion
+}
+
+// DuplicateCluster groups questions connected by pairs of similar statements.
+type DuplicateCluster struct {
+	Questions []db.Question                    `json:"questions"`
+	Pairs     []db.ListSimilarQuestionPairsRow `json:"pairs"`
+	MaxScore  float32                          `json:"max_score"`
+}
+
+// DuplicateService finds near-duplicate questions and merges them.
+type DuplicateService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewDuplicateService creates a new DuplicateService.
+func NewDuplicateService(pool *pgxpool.Pool) *DuplicateService {
+	return &DuplicateService{pool: pool, q: db.New(pool)}
+}
+
+// FindSimilarToQuestion returns the other questions whose statement looks
+// like the one of the given question, most similar first.
+func (s *DuplicateService) FindSimilarToQuestion(ctx context.Context, id pgtype.UUID, threshold float32) ([]db.FindSimilarQuestionsRow, error) {
+	question, err := s.q.GetQuestion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.q.FindSimilarQuestions(ctx, db.FindSimilarQuestionsParams{
+		NormalizedStatement: question.NormalizedStatement,
+		Threshold:           threshold,
+		ExcludeID:           question.ID,
+	})
+}
+
+// ListDuplicateClusters groups the questions suspected to be duplicates.
+// Questions are linked when their similarity reaches the threshold, and
+// linked questions end up in the same cluster even if not directly similar.
+func (s *DuplicateService) ListDuplicateClusters(ctx context.Context, threshold float32) ([]DuplicateCluster, error) {
+	pairs, err := s.q.ListSimilarQuestionPairs(ctx, db.ListSimilarQuestionPairsParams{
+		Threshold: threshold,
+		MaxPairs:  maxDuplicatePairs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar pares semelhantes: %w", err)
+	}
+
+	// Union-find over the question IDs found in pairs.
+	parent := make(map[pgtype.UUID]pgtype.UUID)
+	var find func(id pgtype.UUID) pgtype.UUID
+	find = func(id pgtype.UUID) pgtype.UUID {
+		p, ok := parent[id]
+		if !ok || p == id {
+			parent[id] = id
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, p := range pairs {
+		a, b := find(p.QuestionID), find(p.SimilarID)
+		if a != b {
+			parent[b] = a
+		}
+	}
+
+	clusters := make(map[pgtype.UUID]*DuplicateCluster)
+	var order []pgtype.UUID
+	for _, p := range pairs {
+		root := find(p.QuestionID)
+		c, ok := clusters[root]
+		if !ok {
+			c = &DuplicateCluster{}
+			clusters[root] = c
+			order = append(order, root)
+		}
+		c.Pairs = append(c.Pairs, p)
+		if p.Score > c.MaxScore {
+			c.MaxScore = p.Score
+		}
+	}
+
+	ids := make([]pgtype.UUID, 0, len(parent))
+	for id := range parent {
+		ids = append(ids, id)
+	}
+	questions, err := s.q.ListQuestionsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar questões: %w", err)
+	}
+	for _, q := range questions {
+		if c, ok := clusters[find(q.ID)]; ok {
+			c.Questions = append(c.Questions, q)
+		}
+	}
+
+	// Pairs come sorted by score, so clusters keep the order of their best pair.
+	result := make([]DuplicateCluster, 0, len(order))
+	for _, root := range order {
+		result = append(result, *clusters[root])
+	}
+	return result, nil
+}
+
+// MergeDuplicates keeps one question and moves its duplicates to the trash,
+// where they can still be restored.
+func (s *DuplicateService) MergeDuplicates(ctx context.Context, keepID pgtype.UUID, duplicateIDs []pgtype.UUID) (db.Question, error) {
+	if len(duplicateIDs) == 0 {
+		return db.Question{}, fmt.Errorf("%w: informe ao menos uma duplicata", ErrInvalidMerge)
+	}
+	for _, id := range duplicateIDs {
+		if id == keepID {
+			return db.Question{}, fmt.Errorf("%w: a questão mantida não pode ser uma duplicata", ErrInvalidMerge)
+		}
+	}
+
+	var kept db.Question
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		var err error
+		kept, err = qtx.GetQuestion(ctx, keepID)
+		if err != nil {
+			return err
+		}
+		for _, id := range duplicateIDs {
+			if err := softDelete(ctx, qtx, TrashKindQuestion, id); err != nil {
+				return fmt.Errorf("erro ao mesclar duplicata: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return db.Question{}, err
+	}
+	return kept, nil
+}
+
+// checkSimilar fails with a SimilarQuestionsError when an existing question
+// has a statement similar to the given one.
+func checkSimilar(ctx context.Context, q db.Querier, statement string) error {
+	matches, err := q.FindSimilarQuestions(ctx, db.FindSimilarQuestionsParams{
+		NormalizedStatement: NormalizeStatement(statement),
+		Threshold:           DefaultDuplicateThreshold,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao verificar duplicidade: %w", err)
+	}
+	if len(matches) > 0 {
+		return &SimilarQuestionsError{Matches: matches}
+	}
+	return nil
+}
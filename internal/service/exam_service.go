@@ -3,11 +3,14 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jung-kurt/gofpdf"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
@@ -15,10 +18,8 @@ import (
 
 // ExamService gerencia a geração de provas
 type ExamService struct {
-	q           db.Querier
-	svcSubject  *SubjectService
-	svcTopic    *TopicService
-	svcQuestion *QuestionService
+	pool *pgxpool.Pool
+	q    db.Querier
 }
 
 // SubjectFilter representa o filtro de matéria para geração de prova
@@ -45,12 +46,24 @@ type GenerateExamFilters struct {
 	FieldOfStudy pgtype.Text     `json:"field_of_study"`
 	MinYear      pgtype.Int4     `json:"min_year"`
 	MaxYear      pgtype.Int4     `json:"max_year"`
+	Board        pgtype.Text     `json:"board"`
+	// Faixa de dificuldade medida: proporção de acertos (0 a 1) nas
+	// respostas registradas, considerando só questões com pelo menos
+	// MinResponses respostas
+	MinCorrectRate pgtype.Float8 `json:"min_correct_rate"`
+	MaxCorrectRate pgtype.Float8 `json:"max_correct_rate"`
+	MinResponses   pgtype.Int4   `json:"min_responses"`
+	// Embaralha as alternativas de cada questão; a ordem impressa é
+	// persistida para manter o gabarito consistente
+	ShuffleChoices bool `json:"shuffle_choices"`
 }
 
-// QuestionWithChoices agrupa uma questão com suas alternativas
+// QuestionWithChoices agrupa uma questão com suas alternativas, na ordem
+// impressa, e a revisão de onde veio o conteúdo
 type QuestionWithChoices struct {
-	Question db.GetQuestionsForExamRow
-	Choices  []db.Choice
+	Question   db.GetQuestionsForExamRow
+	Choices    []db.Choice
+	RevisionID pgtype.UUID
 }
 
 // SubjectQuestions agrupa questões por matéria
@@ -66,6 +79,28 @@ type GabaritoItem struct {
 	Subject string
 }
 
+// ExamQuestionDetail representa uma questão de uma prova persistida,
+// com o conteúdo exato da revisão impressa
+type ExamQuestionDetail struct {
+	Number      int32            `json:"number"`
+	QuestionID  pgtype.UUID      `json:"question_id"`
+	RevisionID  pgtype.UUID      `json:"revision_id"`
+	Revision    int32            `json:"revision"`
+	SubjectName string           `json:"subject_name"`
+	Answer      string           `json:"answer"`
+	ChoiceOrder []pgtype.UUID    `json:"choice_order"`
+	Snapshot    RevisionSnapshot `json:"snapshot"`
+}
+
+// ExamDetail representa uma prova persistida
+type ExamDetail struct {
+	ID        pgtype.UUID          `json:"id"`
+	CreatedBy string               `json:"created_by"`
+	CreatedAt pgtype.Timestamptz   `json:"created_at"`
+	Filters   json.RawMessage      `json:"filters"`
+	Questions []ExamQuestionDetail `json:"questions,omitempty"`
+}
+
 // IsValid verifica se os filtros são válidos
 func (gef *GenerateExamFilters) IsValid() bool {
 	if len(gef.Subjects) == 0 {
@@ -82,57 +117,248 @@ func (gef *GenerateExamFilters) IsValid() bool {
 	if !gef.FieldOfStudy.Valid || gef.FieldOfStudy.String == "" {
 		return false
 	}
+	for _, rate := range []pgtype.Float8{gef.MinCorrectRate, gef.MaxCorrectRate} {
+		if rate.Valid && (rate.Float64 < 0 || rate.Float64 > 1) {
+			return false
+		}
+	}
 	return true
 }
 
 // NewExamService cria uma nova instância do ExamService
-func NewExamService(q db.Querier, svcSubject *SubjectService, svcTopic *TopicService, svcQuestion *QuestionService) *ExamService {
+func NewExamService(pool *pgxpool.Pool) *ExamService {
 	return &ExamService{
-		q:           q,
-		svcSubject:  svcSubject,
-		svcTopic:    svcTopic,
-		svcQuestion: svcQuestion,
+		pool: pool,
+		q:    db.New(pool),
 	}
 }
 
-// GenerateExam gera uma prova em PDF com base nos filtros fornecidos
-func (s *ExamService) GenerateExam(ctx context.Context, filters GenerateExamFilters) ([]byte, error) {
+// GenerateExam gera uma prova em PDF com base nos filtros fornecidos.
+// A prova é persistida apontando para a revisão exata de cada questão impressa.
+//
+// Tudo roda em uma única transação: cada questão sorteada é travada e o
+// conteúdo impresso vem da revisão registrada sob essa trava, de modo que
+// uma edição concorrente não separa o que foi impresso do que foi gravado.
+// O PDF é gerado antes do commit, então uma falha nele não deixa a prova
+// persistida.
+func (s *ExamService) GenerateExam(ctx context.Context, filters GenerateExamFilters) (db.Exam, []byte, error) {
 	slog.InfoContext(ctx, "-----------------------------")
 	slog.InfoContext(ctx, "Generate Exam Service")
 	slog.InfoContext(ctx, "-----------------------------")
 
 	if !filters.IsValid() {
 		slog.ErrorContext(ctx, "Invalid filters")
-		return nil, fmt.Errorf("invalid filters provided")
+		return db.Exam{}, nil, fmt.Errorf("invalid filters provided")
+	}
+
+	var (
+		exam     db.Exam
+		pdfBytes []byte
+	)
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		// 1. Buscar dados do banco, travando as questões sorteadas
+		subjectQuestionsList, gabarito, err := s.fetchExamData(ctx, qtx, filters)
+		if err != nil {
+			return err
+		}
+
+		totalQuestions := s.countTotalQuestions(subjectQuestionsList)
+		slog.InfoContext(ctx, "Total questions fetched", "count", totalQuestions)
+
+		if totalQuestions == 0 {
+			slog.ErrorContext(ctx, "Nenhuma questão encontrada para os filtros fornecidos", "filters", filters)
+			return fmt.Errorf("nenhuma questão encontrada para os filtros fornecidos")
+		}
+
+		// 2. Persistir a prova com as revisões impressas
+		exam, err = s.persistExam(ctx, qtx, filters, subjectQuestionsList, gabarito)
+		if err != nil {
+			return err
+		}
+
+		// 3. Gerar PDF antes de confirmar a prova
+		slog.InfoContext(ctx, "Gerando PDF com as questões")
+		pdfBytes, err = s.generatePDF(subjectQuestionsList, gabarito, totalQuestions)
+		return err
+	})
+	if err != nil {
+		return db.Exam{}, nil, err
 	}
 
-	// 1. Buscar dados do banco
-	subjectQuestionsList, gabarito, err := s.fetchExamData(ctx, filters)
+	slog.InfoContext(ctx, "PDF gerado com sucesso!!!", "exam_id", exam.ID)
+	return exam, pdfBytes, nil
+}
+
+// persistExam grava a prova e, para cada questão, a revisão de onde veio o
+// conteúdo impresso
+func (s *ExamService) persistExam(ctx context.Context, qtx *db.Queries, filters GenerateExamFilters, subjectQuestionsList []SubjectQuestions, gabarito []GabaritoItem) (db.Exam, error) {
+	filtersJSON, err := json.Marshal(filters)
+	if err != nil {
+		return db.Exam{}, fmt.Errorf("erro ao serializar filtros: %v", err)
+	}
+
+	exam, err := qtx.CreateExam(ctx, db.CreateExamParams{
+		CreatedBy: UserFromContext(ctx),
+		Filters:   filtersJSON,
+	})
+	if err != nil {
+		return db.Exam{}, fmt.Errorf("erro ao criar prova: %v", err)
+	}
+
+	i := 0
+	for _, sq := range subjectQuestionsList {
+		for _, qwc := range sq.Questions {
+			choiceOrder := make([]pgtype.UUID, 0, len(qwc.Choices))
+			for _, c := range qwc.Choices {
+				choiceOrder = append(choiceOrder, c.ID)
+			}
+			if err := qtx.CreateExamQuestion(ctx, db.CreateExamQuestionParams{
+				ExamID:      exam.ID,
+				Number:      int32(gabarito[i].Number),
+				QuestionID:  qwc.Question.ID,
+				RevisionID:  qwc.RevisionID,
+				SubjectName: sq.SubjectName,
+				Answer:      gabarito[i].Answer,
+				ChoiceOrder: choiceOrder,
+			}); err != nil {
+				return db.Exam{}, fmt.Errorf("erro ao registrar questão %d da prova: %v", gabarito[i].Number, err)
+			}
+			i++
+		}
+	}
+	return exam, nil
+}
+
+// ListExams lista as provas geradas, mais recentes primeiro
+func (s *ExamService) ListExams(ctx context.Context) ([]ExamDetail, error) {
+	exams, err := s.q.ListExams(ctx)
 	if err != nil {
 		return nil, err
 	}
+	details := make([]ExamDetail, 0, len(exams))
+	for _, e := range exams {
+		details = append(details, examDetail(e))
+	}
+	return details, nil
+}
 
-	totalQuestions := s.countTotalQuestions(subjectQuestionsList)
-	slog.InfoContext(ctx, "Total questions fetched", "count", totalQuestions)
+// GetExam retorna uma prova persistida com o conteúdo das revisões impressas
+func (s *ExamService) GetExam(ctx context.Context, id pgtype.UUID) (ExamDetail, error) {
+	exam, err := s.q.GetExam(ctx, id)
+	if err != nil {
+		return ExamDetail{}, err
+	}
+	rows, err := s.q.ListExamQuestions(ctx, id)
+	if err != nil {
+		return ExamDetail{}, err
+	}
 
-	if totalQuestions == 0 {
-		slog.ErrorContext(ctx, "Nenhuma questão encontrada para os filtros fornecidos", "filters", filters)
-		return nil, fmt.Errorf("nenhuma questão encontrada para os filtros fornecidos")
+	detail := examDetail(exam)
+	detail.Questions = make([]ExamQuestionDetail, 0, len(rows))
+	for _, row := range rows {
+		var snap RevisionSnapshot
+		if err := json.Unmarshal(row.Snapshot, &snap); err != nil {
+			return ExamDetail{}, fmt.Errorf("erro ao ler revisão da questão %d: %v", row.Number, err)
+		}
+		detail.Questions = append(detail.Questions, ExamQuestionDetail{
+			Number:      row.Number,
+			QuestionID:  row.QuestionID,
+			RevisionID:  row.RevisionID,
+			Revision:    row.Revision,
+			SubjectName: row.SubjectName,
+			Answer:      row.Answer,
+			ChoiceOrder: row.ChoiceOrder,
+			Snapshot:    snap,
+		})
 	}
+	return detail, nil
+}
 
-	// 2. Gerar PDF
-	slog.InfoContext(ctx, "Gerando PDF com as questões")
-	pdfBytes, err := s.generatePDF(subjectQuestionsList, gabarito, totalQuestions)
+// RenderExam gera novamente o PDF de uma prova persistida a partir das
+// revisões impressas, mesmo que as questões tenham sido editadas depois
+func (s *ExamService) RenderExam(ctx context.Context, id pgtype.UUID) ([]byte, error) {
+	detail, err := s.GetExam(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	slog.InfoContext(ctx, "PDF gerado com sucesso!!!", "total_questions", totalQuestions)
-	return pdfBytes, nil
+	var subjectQuestionsList []SubjectQuestions
+	var gabarito []GabaritoItem
+	for _, eq := range detail.Questions {
+		if len(subjectQuestionsList) == 0 || subjectQuestionsList[len(subjectQuestionsList)-1].SubjectName != eq.SubjectName {
+			subjectQuestionsList = append(subjectQuestionsList, SubjectQuestions{SubjectName: eq.SubjectName})
+		}
+		current := &subjectQuestionsList[len(subjectQuestionsList)-1]
+
+		choices := make([]db.Choice, 0, len(eq.Snapshot.Choices))
+		for _, c := range orderedChoices(eq.Snapshot.Choices, eq.ChoiceOrder) {
+			choices = append(choices, db.Choice{
+				ID:         c.ID,
+				QuestionID: eq.QuestionID,
+				ChoiceText: c.ChoiceText,
+				IsCorrect:  pgtype.Bool{Bool: c.IsCorrect, Valid: true},
+			})
+		}
+		current.Questions = append(current.Questions, QuestionWithChoices{
+			Question: db.GetQuestionsForExamRow{
+				ID:           eq.QuestionID,
+				Statement:    eq.Snapshot.Statement,
+				Year:         eq.Snapshot.Year,
+				Position:     eq.Snapshot.Position,
+				Level:        eq.Snapshot.Level,
+				Difficulty:   eq.Snapshot.Difficulty,
+				Modality:     eq.Snapshot.Modality,
+				FieldOfStudy: eq.Snapshot.FieldOfStudy,
+				SubjectName:  eq.SubjectName,
+			},
+			Choices:    choices,
+			RevisionID: eq.RevisionID,
+		})
+		gabarito = append(gabarito, GabaritoItem{
+			Number:  int(eq.Number),
+			Answer:  eq.Answer,
+			Subject: eq.SubjectName,
+		})
+	}
+
+	if len(gabarito) == 0 {
+		return nil, fmt.Errorf("prova sem questões")
+	}
+	return s.generatePDF(subjectQuestionsList, gabarito, len(gabarito))
+}
+
+// orderedChoices devolve as alternativas da revisão na ordem em que foram
+// impressas. Provas sem ordem registrada usam a ordem da própria revisão.
+func orderedChoices(choices []RevisionChoice, order []pgtype.UUID) []RevisionChoice {
+	if len(order) != len(choices) {
+		return choices
+	}
+	byID := make(map[pgtype.UUID]RevisionChoice, len(choices))
+	for _, c := range choices {
+		byID[c.ID] = c
+	}
+	ordered := make([]RevisionChoice, 0, len(order))
+	for _, id := range order {
+		c, ok := byID[id]
+		if !ok {
+			return choices
+		}
+		ordered = append(ordered, c)
+	}
+	return ordered
+}
+
+func examDetail(e db.Exam) ExamDetail {
+	return ExamDetail{
+		ID:        e.ID,
+		CreatedBy: e.CreatedBy,
+		CreatedAt: e.CreatedAt,
+		Filters:   json.RawMessage(e.Filters),
+	}
 }
 
 // fetchExamData busca as questões e alternativas do banco de dados
-func (s *ExamService) fetchExamData(ctx context.Context, filters GenerateExamFilters) ([]SubjectQuestions, []GabaritoItem, error) {
+func (s *ExamService) fetchExamData(ctx context.Context, qtx *db.Queries, filters GenerateExamFilters) ([]SubjectQuestions, []GabaritoItem, error) {
 	var subjectQuestionsList []SubjectQuestions
 	var gabarito []GabaritoItem
 	questionNumber := 1
@@ -140,7 +366,7 @@ func (s *ExamService) fetchExamData(ctx context.Context, filters GenerateExamFil
 	for _, subjectFilter := range filters.Subjects {
 		slog.InfoContext(ctx, "Fetching subject", "name", subjectFilter.Name)
 
-		subject, err := s.svcSubject.GetSubjectByName(ctx, subjectFilter.Name)
+		subject, err := qtx.GetSubjectByName(ctx, subjectFilter.Name)
 		if err != nil {
 			slog.ErrorContext(ctx, "Error fetching subject", "error", err)
 			return nil, nil, fmt.Errorf("error fetching subject %s: %v", subjectFilter.Name, err)
@@ -148,7 +374,7 @@ func (s *ExamService) fetchExamData(ctx context.Context, filters GenerateExamFil
 		slog.InfoContext(ctx, "Subject found", "subject", subject)
 
 		questionsWithChoices, itemsGabarito, newQuestionNumber, err := s.fetchQuestionsForSubject(
-			ctx, subject, subjectFilter, filters, questionNumber,
+			ctx, qtx, subject, subjectFilter, filters, questionNumber,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -168,9 +394,12 @@ func (s *ExamService) fetchExamData(ctx context.Context, filters GenerateExamFil
 	return subjectQuestionsList, gabarito, nil
 }
 
-// fetchQuestionsForSubject busca questões e alternativas para uma matéria específica
+// fetchQuestionsForSubject busca questões e alternativas para uma matéria
+// específica. O conteúdo de cada questão vem da revisão atual, registrada
+// com a questão travada até o fim da transação.
 func (s *ExamService) fetchQuestionsForSubject(
 	ctx context.Context,
+	qtx *db.Queries,
 	subject db.Subject,
 	subjectFilter SubjectFilter,
 	filters GenerateExamFilters,
@@ -179,17 +408,21 @@ func (s *ExamService) fetchQuestionsForSubject(
 
 	var topicID pgtype.UUID // vazio = não filtra por tópico
 
-	questions, err := s.q.GetQuestionsForExam(ctx, db.GetQuestionsForExamParams{
-		ID:           subject.ID,
-		Limit:        subjectFilter.QuestionCount,
-		TopicID:      topicID,
-		Position:     filters.Position,
-		Level:        filters.Level,
-		Difficulty:   filters.Difficulty,
-		Modality:     filters.Modality,
-		FieldOfStudy: filters.FieldOfStudy,
-		MinYear:      filters.MinYear,
-		MaxYear:      filters.MaxYear,
+	questions, err := qtx.GetQuestionsForExam(ctx, db.GetQuestionsForExamParams{
+		ID:             subject.ID,
+		Limit:          subjectFilter.QuestionCount,
+		TopicID:        topicID,
+		Position:       filters.Position,
+		Level:          filters.Level,
+		Difficulty:     filters.Difficulty,
+		Modality:       filters.Modality,
+		FieldOfStudy:   filters.FieldOfStudy,
+		MinYear:        filters.MinYear,
+		MaxYear:        filters.MaxYear,
+		Board:          filters.Board,
+		MinCorrectRate: filters.MinCorrectRate,
+		MaxCorrectRate: filters.MaxCorrectRate,
+		MinResponses:   filters.MinResponses,
 	})
 	if err != nil {
 		slog.ErrorContext(ctx, "Error fetching questions for subject", "subject", subject.Name, "error", err)
@@ -202,15 +435,42 @@ func (s *ExamService) fetchQuestionsForSubject(
 	var gabaritoItems []GabaritoItem
 
 	for _, q := range questions {
-		choices, err := s.q.ListChoicesByQuestion(ctx, q.ID)
+		rev, err := currentRevision(ctx, qtx, q.ID)
 		if err != nil {
-			slog.ErrorContext(ctx, "Error fetching choices for question", "question_id", q.ID, "error", err)
-			return nil, nil, questionNumber, fmt.Errorf("error fetching choices for question: %v", err)
+			slog.ErrorContext(ctx, "Error recording revision for question", "question_id", q.ID, "error", err)
+			return nil, nil, questionNumber, err
+		}
+		snap, err := decodeRevision(rev)
+		if err != nil {
+			return nil, nil, questionNumber, err
+		}
+		q.Statement = snap.Snapshot.Statement
+		q.Year = snap.Snapshot.Year
+		q.Position = snap.Snapshot.Position
+		q.Level = snap.Snapshot.Level
+		q.Difficulty = snap.Snapshot.Difficulty
+		q.Modality = snap.Snapshot.Modality
+		q.FieldOfStudy = snap.Snapshot.FieldOfStudy
+
+		choices := make([]db.Choice, 0, len(snap.Snapshot.Choices))
+		for _, c := range snap.Snapshot.Choices {
+			choices = append(choices, db.Choice{
+				ID:         c.ID,
+				QuestionID: q.ID,
+				ChoiceText: c.ChoiceText,
+				IsCorrect:  pgtype.Bool{Bool: c.IsCorrect, Valid: true},
+			})
+		}
+		if filters.ShuffleChoices {
+			rand.Shuffle(len(choices), func(i, j int) {
+				choices[i], choices[j] = choices[j], choices[i]
+			})
 		}
 
 		questionsWithChoices = append(questionsWithChoices, QuestionWithChoices{
-			Question: q,
-			Choices:  choices,
+			Question:   q,
+			Choices:    choices,
+			RevisionID: rev.ID,
 		})
 
 		correctAnswer := s.findCorrectAnswer(choices)
@@ -272,7 +532,7 @@ func (s *ExamService) buildCoverPage(pdf *gofpdf.Fpdf, tr func(string) string, s
 	s.buildCandidateIdentification(pdf, tr)
 
 	// Instruções
-	s.buildInstructions(pdf, tr, totalQuestions)
+	s.buildInstructions(pdf, tr, subjectQuestionsList, totalQuestions)
 
 	// Resumo das matérias
 	s.buildContentSummary(pdf, tr, subjectQuestionsList)
@@ -326,7 +586,7 @@ func (s *ExamService) buildCandidateIdentification(pdf *gofpdf.Fpdf, tr func(str
 }
 
 // buildInstructions constrói a seção de instruções
-func (s *ExamService) buildInstructions(pdf *gofpdf.Fpdf, tr func(string) string, totalQuestions int) {
+func (s *ExamService) buildInstructions(pdf *gofpdf.Fpdf, tr func(string) string, subjectQuestionsList []SubjectQuestions, totalQuestions int) {
 	pdf.SetFont("Arial", "B", 12)
 	pdf.Cell(190, 8, tr("INSTRUÇÕES"))
 	pdf.Ln(10)
@@ -338,7 +598,7 @@ func (s *ExamService) buildInstructions(pdf *gofpdf.Fpdf, tr func(string) string
 		"3. Utilize caneta esferográfica de tinta preta ou azul.",
 		"4. Não é permitido o uso de corretivo, lápis ou borracha.",
 		fmt.Sprintf("5. Esta prova contém %d questões objetivas.", totalQuestions),
-		"6. Marque apenas uma alternativa por questão.",
+		"6. " + choicesInstruction(subjectQuestionsList),
 		"7. As questões estão organizadas por disciplina/matéria.",
 	}
 
@@ -350,6 +610,26 @@ func (s *ExamService) buildInstructions(pdf *gofpdf.Fpdf, tr func(string) string
 	pdf.Ln(10)
 }
 
+// choicesInstruction descreve as alternativas das questões: a faixa de
+// letras quando todas têm a mesma quantidade, ou um aviso quando ela varia
+func choicesInstruction(subjectQuestionsList []SubjectQuestions) string {
+	count := -1
+	for _, sq := range subjectQuestionsList {
+		for _, qwc := range sq.Questions {
+			n := len(qwc.Choices)
+			if count == -1 {
+				count = n
+			} else if n != count {
+				return "A quantidade de alternativas varia entre as questões. Marque apenas uma alternativa por questão."
+			}
+		}
+	}
+	if count < 1 {
+		return "Marque apenas uma alternativa por questão."
+	}
+	return fmt.Sprintf("Cada questão possui %d alternativas (A a %c). Marque apenas uma alternativa por questão.", count, 'A'+count-1)
+}
+
 // buildContentSummary constrói o resumo de conteúdo da prova
 func (s *ExamService) buildContentSummary(pdf *gofpdf.Fpdf, tr func(string) string, subjectQuestionsList []SubjectQuestions) {
 	pdf.SetFont("Arial", "B", 12)
@@ -0,0 +1,1107 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ErrInvalidImport is returned when an import file cannot be read at all:
+// it is empty or its header is missing required columns. Problems in
+// individual rows are reported in the ImportReport instead.
+var ErrInvalidImport = errors.New("arquivo de importação inválido")
+
+// ImportOptions configures an import. CreateMissing creates the subjects
+// and topics named in the file that do not exist. DryRun validates every
+// row, duplicates included, without writing anything. Atomic commits the
+// whole file only when no row fails. Encoding and Delimiter override the
+// dialect detected in CSV files. DeactivateMissing moves to the trash the
+// questions of the file's subjects with an external_id the file does not
+// list.
+type ImportOptions struct {
+	CreateMissing     bool   `json:"create_missing"`
+	DryRun            bool   `json:"dry_run"`
+	Atomic            bool   `json:"atomic"`
+	Encoding          string `json:"encoding,omitempty"`
+	Delimiter         string `json:"delimiter,omitempty"`
+	DeactivateMissing bool   `json:"deactivate_missing,omitempty"`
+	// KeepReviewStatus keeps the review_status of JSON Lines records, for
+	// moving an already reviewed bank between environments. Otherwise
+	// imported questions wait for review as pending.
+	KeepReviewStatus bool `json:"keep_review_status,omitempty"`
+}
+
+// ImportRowError lists the problems found in one line of the file. In
+// spreadsheets, Aba names the sheet and Linha is the row number within it.
+type ImportRowError struct {
+	Aba     string   `json:"aba,omitempty"`
+	Linha   int      `json:"linha"`
+	Erros   []string `json:"erros"`
+	Valores []string `json:"valores,omitempty"`
+}
+
+// ImportDuplicate is a row skipped because a similar question exists.
+type ImportDuplicate struct {
+	Aba          string      `json:"aba,omitempty"`
+	Linha        int         `json:"linha"`
+	QuestaoID    pgtype.UUID `json:"questao_id"`
+	Similaridade float32     `json:"similaridade"`
+}
+
+// ImportPreview is a question a dry run would create.
+type ImportPreview struct {
+	Aba          string      `json:"aba,omitempty"`
+	Linha        int         `json:"linha"`
+	Enunciado    string      `json:"enunciado"`
+	Ano          int32       `json:"ano"`
+	TopicID      pgtype.UUID `json:"topic_id"`
+	Alternativas []string    `json:"alternativas"`
+	Gabarito     string      `json:"gabarito"`
+}
+
+// ImportReport summarises an import. Total counts the data rows read.
+type ImportReport struct {
+	Total   int `json:"total"`
+	Criadas int `json:"criadas"`
+	// Linhas com id ou external_id que alteraram uma questão existente; as
+	// que não mudam nada contam como ignoradas
+	Atualizadas int               `json:"atualizadas"`
+	Ignoradas   int               `json:"ignoradas"`
+	Falharam    int               `json:"falharam"`
+	Detalhes    []ImportRowError  `json:"detalhes"`
+	Duplicatas  []ImportDuplicate `json:"duplicatas,omitempty"`
+	ColunasCSV  []string          `json:"colunas_csv"`
+	// Com deactivate_missing, questões com external_id ausente do arquivo
+	// movidas para a lixeira
+	Desativadas int `json:"desativadas,omitempty"`
+	// Codificação e delimitador usados para ler o CSV
+	Codificacao string `json:"codificacao,omitempty"`
+	Delimitador string `json:"delimitador,omitempty"`
+	// Abas da planilha sem o cabeçalho de questões, que não foram lidas
+	AbasIgnoradas []string `json:"abas_ignoradas,omitempty"`
+	// Matérias e tópicos criados a partir das colunas subject e topic
+	MateriasCriadas []db.Subject `json:"materias_criadas,omitempty"`
+	TopicosCriados  []db.Topic   `json:"topicos_criados,omitempty"`
+	// Em dry_run, nada é gravado e Previa lista as questões que seriam criadas
+	DryRun bool            `json:"dry_run,omitempty"`
+	Previa []ImportPreview `json:"previa,omitempty"`
+	// Em atomic, Desfeita indica que a importação inteira foi desfeita porque
+	// ao menos uma linha falhou
+	Atomica  bool `json:"atomica,omitempty"`
+	Desfeita bool `json:"desfeita,omitempty"`
+}
+
+// ImportCSV creates questions from a CSV file. The encoding and delimiter
+// are detected unless given in opts, and the header may use Portuguese
+// column names. Each row names its topic either by topic_id or by the
+// subject and topic columns, and is validated by its modality: Certo/Errado
+// rows have no choices and answer C or E, other rows list their choices and
+// the letter of the correct one. Rows with an id, as written by ExportCSV, or
+// with the external_id of a question already imported update that question
+// instead of creating one; with DeactivateMissing, questions of the
+// subjects the file names whose external_id no row lists are moved to the
+// trash once the whole file has been read. Rows that fail are listed in the report with their line number
+// and do not stop the import, except in atomic mode, where any failure
+// undoes the whole file.
+func (s *ImportService) ImportCSV(ctx context.Context, r io.Reader, opts ImportOptions) (ImportReport, error) {
+	return s.importCSV(ctx, r, opts, ImportReport{}, nil)
+}
+
+// ImportFile creates questions from a CSV, XLSX or JSON Lines file, telling
+// them apart by the extension of filename or, without a known one, by the
+// content. See ImportCSV, ImportXLSX and ImportJSONL.
+func (s *ImportService) ImportFile(ctx context.Context, filename string, r io.Reader, opts ImportOptions) (ImportReport, error) {
+	return s.importFile(ctx, filename, r, opts, ImportReport{}, nil)
+}
+
+// importFile runs the import of a file in any of the supported formats,
+// resuming after the rows already counted in report; see importTables.
+func (s *ImportService) importFile(ctx context.Context, filename string, r io.Reader, opts ImportOptions, report ImportReport, progress func(db.Querier, ImportReport) error) (ImportReport, error) {
+	br := bufio.NewReader(r)
+	switch importFormat(filename, br) {
+	case "xlsx":
+		return s.importXLSX(ctx, br, opts, report, progress)
+	case "jsonl":
+		return s.importJSONL(ctx, br, opts, report, progress)
+	}
+	return s.importCSV(ctx, br, opts, report, progress)
+}
+
+// importFormat returns the format of an import file, "csv", "xlsx" or
+// "jsonl", by its extension or, when the name does not tell, by the zip
+// signature XLSX files start with or the opening brace of a JSON line.
+func importFormat(filename string, r *bufio.Reader) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		return "xlsx"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	case ".csv", ".txt":
+		return "csv"
+	}
+	if magic, _ := r.Peek(4); bytes.Equal(magic, []byte("PK\x03\x04")) {
+		return "xlsx"
+	}
+	if start, _ := r.Peek(1); bytes.Equal(start, []byte("{")) {
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// importCSV runs a CSV import, resuming after the rows already counted in
+// report; see importTables.
+func (s *ImportService) importCSV(ctx context.Context, r io.Reader, opts ImportOptions, report ImportReport, progress func(db.Querier, ImportReport) error) (ImportReport, error) {
+	table, dialect, err := newCSVTable(r, opts)
+	if err != nil {
+		return report, err
+	}
+	report.Codificacao = dialect.encoding
+	report.Delimitador = string(dialect.delimiter)
+	return s.importTables(ctx, []*importTable{table}, opts, report, progress)
+}
+
+// importTable is a source of question rows: a CSV file or one sheet of a
+// spreadsheet, with the layout read from its header.
+type importTable struct {
+	// sheet names the spreadsheet sheet; it is empty for CSV files.
+	sheet  string
+	layout csvLayout
+	rows   interface{ Read() ([]string, error) }
+	// line is the line of the last row read.
+	line int
+	// pending is the first data row of a CSV without header, already read
+	// while looking for one.
+	pending []string
+	// subject is the subject of rows without a subject column: the name of
+	// the sheet, so that a spreadsheet can hold one subject per sheet.
+	subject string
+}
+
+func newCSVTable(r io.Reader, opts ImportOptions) (*importTable, csvDialect, error) {
+	content, dialect, err := openCSV(r, opts)
+	if err != nil {
+		return nil, csvDialect{}, err
+	}
+	reader := csv.NewReader(content)
+	reader.Comma = dialect.delimiter
+	reader.TrimLeadingSpace = true
+	// A quantidade de colunas depende de quantas alternativas o cabeçalho declara
+	reader.FieldsPerRecord = -1
+
+	firstRow, err := reader.Read()
+	if err == io.EOF {
+		return nil, csvDialect{}, fmt.Errorf("%w: csv vazio", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, csvDialect{}, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	// Sem cabeçalho, assume o formato legado com 5 alternativas (choice_a..choice_e)
+	layout, isHeader, err := parseCSVHeader(firstRow, false)
+	if err != nil {
+		return nil, csvDialect{}, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	table := &importTable{layout: layout, rows: reader, line: 1}
+	if !isHeader {
+		table.pending, table.line = firstRow, 0
+	}
+	return table, dialect, nil
+}
+
+// next returns the next data row of the table, or io.EOF at its end. Blank
+// spreadsheet rows are skipped.
+func (t *importTable) next() ([]string, error) {
+	if t.pending != nil {
+		row := t.pending
+		t.pending = nil
+		t.line++
+		return row, nil
+	}
+	for {
+		row, err := t.rows.Read()
+		if err != nil {
+			return nil, err
+		}
+		t.line++
+		if t.sheet == "" || slices.ContainsFunc(row, func(v string) bool { return strings.TrimSpace(v) != "" }) {
+			return row, nil
+		}
+	}
+}
+
+// cells returns the spreadsheet references of the named columns in the
+// current row, as in "C5", or nil for CSV files.
+func (t *importTable) cells(columns ...string) []string {
+	if t.sheet == "" {
+		return nil
+	}
+	refs := make([]string, 0, len(columns))
+	for _, name := range columns {
+		if idx, ok := t.layout.columns[name]; ok {
+			refs = append(refs, cellName(idx, t.line))
+		}
+	}
+	return refs
+}
+
+// at prefixes msg with the cell of column in spreadsheets.
+func (t *importTable) at(column, msg string) string {
+	if refs := t.cells(column); len(refs) > 0 {
+		return refs[0] + ": " + msg
+	}
+	return msg
+}
+
+// importTables imports the rows of each table in order, skipping the data
+// rows already counted in report so that an interrupted import can resume
+// where it stopped. After each row, progress receives the report so far
+// (see step); an error from it, or the cancellation of ctx, stops the
+// import and is returned with the partial report.
+func (s *ImportService) importTables(ctx context.Context, tables []*importTable, opts ImportOptions, report ImportReport, progress func(db.Querier, ImportReport) error) (ImportReport, error) {
+	if opts.DeactivateMissing {
+		for _, table := range tables {
+			if _, ok := table.layout.columns["external_id"]; !ok {
+				return report, fmt.Errorf("%w: deactivate_missing exige a coluna external_id", ErrInvalidImport)
+			}
+		}
+	}
+	im, err := s.newRowImporter(ctx, opts, &report)
+	if err != nil {
+		return report, err
+	}
+	defer im.rollback(ctx)
+	report.ColunasCSV = tables[0].layout.headers
+	report.DryRun = opts.DryRun
+	report.Atomica = opts.Atomic
+
+	complete := true
+	for _, table := range tables {
+		for {
+			row, err := table.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				report.Total++
+				report.Falharam++
+				report.Detalhes = append(report.Detalhes, ImportRowError{
+					Aba:   table.sheet,
+					Linha: table.line + 1,
+					Erros: []string{err.Error()},
+				})
+				complete = false
+				break
+			}
+			if err := im.step(ctx, progress, func() { im.importRow(ctx, table, row) }); err != nil {
+				return im.abort(), err
+			}
+			// Registrado também nas linhas puladas numa retomada
+			im.seen(table, row)
+		}
+	}
+
+	// Um arquivo lido pela metade não lista todas as questões que tem
+	if opts.DeactivateMissing && complete {
+		if err := im.deactivateMissing(ctx); err != nil {
+			return im.abort(), err
+		}
+	}
+
+	if err := im.finish(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// rowImporter creates the questions of an import, one row at a time. In
+// dry-run and atomic modes every row runs inside the same transaction, so
+// duplicates within the file and topics created by earlier rows are taken
+// into account; dry runs always roll it back and atomic imports only
+// commit it when no row failed.
+type rowImporter struct {
+	svc      *ImportService
+	opts     ImportOptions
+	report   *ImportReport
+	resolver *TopicResolver
+	queries  db.Querier
+	tx       pgx.Tx
+
+	// Matérias e tópicos criados antes de uma retomada
+	subjects []db.Subject
+	topics   []db.Topic
+	// Linhas já importadas antes de uma retomada, a pular
+	skip int
+	// external_id das linhas lidas até aqui
+	externalIDs map[string]bool
+	// Tópicos e matérias (por nameKey) citados pelas linhas lidas, que
+	// delimitam o deactivate_missing
+	scopeTopics   map[pgtype.UUID]bool
+	scopeSubjects map[string]bool
+}
+
+func (s *ImportService) newRowImporter(ctx context.Context, opts ImportOptions, report *ImportReport) (*rowImporter, error) {
+	resolver, err := s.NewTopicResolver(ctx, opts.CreateMissing)
+	if err != nil {
+		return nil, err
+	}
+	im := &rowImporter{
+		svc:           s,
+		opts:          opts,
+		report:        report,
+		resolver:      resolver,
+		queries:       db.New(s.pool),
+		subjects:      report.MateriasCriadas,
+		topics:        report.TopicosCriados,
+		skip:          report.Total,
+		externalIDs:   make(map[string]bool),
+		scopeTopics:   make(map[pgtype.UUID]bool),
+		scopeSubjects: make(map[string]bool),
+	}
+	if opts.DryRun || opts.Atomic {
+		im.tx, err = s.BeginTx(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+		}
+		im.queries = db.New(im.tx)
+	}
+	return im, nil
+}
+
+// step imports the next data row with importRow, unless it was imported
+// before the import was interrupted, and reports the progress. It fails
+// when ctx is cancelled or progress returns an error.
+//
+// Outside dry-run and atomic modes, a row with progress to report runs in
+// a transaction of its own and progress receives that transaction, so the
+// row and the progress that counts it are committed together and a resumed
+// import never runs a committed row again. Otherwise progress receives the
+// pool.
+func (im *rowImporter) step(ctx context.Context, progress func(db.Querier, ImportReport) error, importRow func()) error {
+	if im.skip > 0 {
+		im.skip--
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if progress == nil {
+		importRow()
+		return nil
+	}
+	if im.tx != nil {
+		importRow()
+		return progress(db.New(im.svc.pool), *im.report)
+	}
+
+	tx, err := im.svc.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+	im.tx, im.queries = tx, db.New(tx)
+	defer func() { im.tx, im.queries = nil, db.New(im.svc.pool) }()
+
+	importRow()
+	if err := progress(im.queries, *im.report); err != nil {
+		return err
+	}
+	// Uma linha concluída é gravada mesmo que o cancelamento chegue agora
+	if err := tx.Commit(context.WithoutCancel(ctx)); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("erro ao confirmar linha: %w", err)
+	}
+	return nil
+}
+
+func (im *rowImporter) create(ctx context.Context, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
+	if im.tx != nil {
+		return im.svc.CreateQuestionWithChoicesTx(ctx, im.tx, input)
+	}
+	return im.svc.CreateQuestionWithChoices(ctx, input)
+}
+
+// importRow validates and creates the question of the current row of
+// table, recording the outcome in the report.
+func (im *rowImporter) importRow(ctx context.Context, table *importTable, row []string) {
+	report := im.report
+	layout, line := table.layout, table.line
+	defer im.syncCreated()
+	report.Total++
+	if len(row) != len(layout.headers) {
+		report.Falharam++
+		report.Detalhes = append(report.Detalhes, ImportRowError{
+			Aba:     table.sheet,
+			Linha:   line,
+			Erros:   []string{fmt.Sprintf("quantidade de colunas inválida (esperado: %d)", len(layout.headers))},
+			Valores: row,
+		})
+		return
+	}
+	erros := []string{}
+
+	statement := layout.value(row, "statement")
+	yearStr := layout.value(row, "year")
+	topicIDStr := layout.value(row, "topic_id")
+	subjectName := layout.value(row, "subject")
+	if _, ok := layout.columns["subject"]; !ok {
+		subjectName = table.subject
+	}
+	topicName := layout.value(row, "topic")
+	position := layout.value(row, "position")
+	level := layout.value(row, "level")
+	difficulty := layout.value(row, "difficulty")
+	modality := layout.value(row, "modality")
+	practiceArea := layout.value(row, "practice_area")
+	fieldOfStudy := layout.value(row, "field_of_study")
+	board := layout.value(row, "board")
+	choiceCountStr := layout.value(row, "choice_count")
+	idStr := layout.value(row, "id")
+	externalID := layout.value(row, "external_id")
+	correctChoice := strings.ToUpper(layout.value(row, "correct_choice"))
+
+	// Valida campos obrigatórios da questão
+	var missing []string
+	for _, name := range requiredRowFields {
+		if layout.value(row, name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		msg := "todos os campos da questão são obrigatórios"
+		if refs := table.cells(missing...); len(refs) > 0 {
+			msg += fmt.Sprintf(" (células vazias: %s)", strings.Join(refs, ", "))
+		}
+		erros = append(erros, msg)
+	}
+	if topicIDStr == "" && (subjectName == "" || topicName == "") {
+		erros = append(erros, "informe topic_id ou as colunas subject e topic")
+	}
+
+	// Alternativas vazias ao final são ignoradas (questões com menos alternativas),
+	// mas não pode haver lacunas entre as preenchidas
+	choiceTexts := make([]string, 0, len(layout.choices))
+	for _, idx := range layout.choices {
+		choiceTexts = append(choiceTexts, strings.TrimSpace(row[idx]))
+	}
+	for len(choiceTexts) > 0 && choiceTexts[len(choiceTexts)-1] == "" {
+		choiceTexts = choiceTexts[:len(choiceTexts)-1]
+	}
+
+	// Questões Certo/Errado trazem só o enunciado e a resposta C ou E; as de
+	// múltipla escolha trazem as alternativas e a letra da correta
+	var choices []ChoiceInput
+	if isTrueFalseModality(modality) {
+		modality = ModalityTrueFalse
+		if len(choiceTexts) > 0 {
+			filled := fmt.Sprintf("choice_%c", 'a'+slices.IndexFunc(choiceTexts, func(t string) bool { return t != "" }))
+			erros = append(erros, table.at(filled, "questões Certo/Errado não têm alternativas; informe apenas C ou E em correct_choice"))
+		}
+		if isTrue, ok := parseTrueFalseAnswer(correctChoice); ok {
+			choices = trueFalseChoices(isTrue)
+		} else {
+			erros = append(erros, table.at("correct_choice", "correct_choice deve ser C (certo) ou E (errado) em questões Certo/Errado"))
+		}
+	} else {
+		if slices.Contains(choiceTexts, "") {
+			gap := fmt.Sprintf("choice_%c", 'a'+slices.Index(choiceTexts, ""))
+			erros = append(erros, table.at(gap, "as alternativas devem ser preenchidas em sequência, sem lacunas"))
+		}
+		if len(choiceTexts) < MinChoiceCount {
+			erros = append(erros, fmt.Sprintf("a questão deve ter ao menos %d alternativas", MinChoiceCount))
+		}
+
+		// Valida correct_choice contra as alternativas preenchidas
+		lastLetter := rune('A' + max(len(choiceTexts), 1) - 1)
+		if len(correctChoice) != 1 || rune(correctChoice[0]) < 'A' || rune(correctChoice[0]) > lastLetter {
+			erros = append(erros, table.at("correct_choice", fmt.Sprintf("correct_choice deve ser uma letra entre A e %c", lastLetter)))
+		}
+
+		// Monta as choices com o indicador de qual é correta
+		for i, text := range choiceTexts {
+			choices = append(choices, ChoiceInput{
+				Text:      text,
+				IsCorrect: correctChoice == string(rune('A'+i)),
+			})
+		}
+	}
+
+	year64, err := strconv.ParseInt(yearStr, 10, 32)
+	if err != nil {
+		erros = append(erros, table.at("year", "year inválido"))
+	}
+
+	id := pgtype.UUID{}
+	if idStr != "" {
+		if err := id.Scan(idStr); err != nil {
+			erros = append(erros, table.at("id", "id inválido"))
+		}
+	}
+
+	if utf8.RuneCountInString(externalID) > maxExternalIDLength {
+		erros = append(erros, table.at("external_id", fmt.Sprintf("external_id deve ter no máximo %d caracteres", maxExternalIDLength)))
+	}
+	if im.externalIDs[externalID] {
+		erros = append(erros, table.at("external_id", fmt.Sprintf("external_id repetido no arquivo: %s", externalID)))
+	}
+
+	topicID := pgtype.UUID{}
+	if topicIDStr != "" {
+		if err := topicID.Scan(topicIDStr); err != nil {
+			erros = append(erros, table.at("topic_id", "topic_id inválido"))
+		}
+	}
+
+	choiceCount := pgtype.Int4{}
+	if choiceCountStr != "" {
+		n, err := strconv.ParseInt(choiceCountStr, 10, 32)
+		if err != nil || n < MinChoiceCount || n > MaxChoiceCount {
+			erros = append(erros, table.at("choice_count", fmt.Sprintf("choice_count deve estar entre %d e %d", MinChoiceCount, MaxChoiceCount)))
+		} else if modality == ModalityTrueFalse && n != MinChoiceCount {
+			erros = append(erros, table.at("choice_count", fmt.Sprintf("choice_count deve ser %d ou vazio em questões Certo/Errado", MinChoiceCount)))
+		}
+		choiceCount = pgtype.Int4{Int32: int32(n), Valid: true}
+	}
+
+	// Resolve o tópico pelo nome apenas em linhas válidas, para não criar
+	// matérias e tópicos a partir de linhas que serão rejeitadas
+	if len(erros) == 0 && !topicID.Valid {
+		topicID, err = im.resolver.Resolve(ctx, im.beginner(), subjectName, topicName)
+		if err != nil {
+			erros = append(erros, err.Error())
+		}
+	}
+
+	// Sem id, o external_id identifica a questão de uma importação anterior
+	if len(erros) == 0 && !id.Valid && externalID != "" {
+		found, err := im.queries.GetQuestionIDByExternalID(ctx, pgtype.Text{String: externalID, Valid: true})
+		switch {
+		case err == nil:
+			id = found
+		case !errors.Is(err, pgx.ErrNoRows):
+			erros = append(erros, fmt.Sprintf("erro ao buscar external_id: %v", err))
+		}
+	}
+
+	if len(erros) == 0 {
+		question := db.Question{
+			Statement:    statement,
+			Year:         int32(year64),
+			TopicID:      topicID,
+			Position:     pgtype.Text{String: position, Valid: true},
+			Level:        pgtype.Text{String: level, Valid: true},
+			Difficulty:   pgtype.Text{String: difficulty, Valid: true},
+			Modality:     pgtype.Text{String: modality, Valid: true},
+			PracticeArea: pgtype.Text{String: practiceArea, Valid: true},
+			FieldOfStudy: pgtype.Text{String: fieldOfStudy, Valid: true},
+			Board:        pgtype.Text{String: board, Valid: board != ""},
+			ChoiceCount:  choiceCount,
+			ExternalID:   pgtype.Text{String: externalID, Valid: externalID != ""},
+		}
+
+		input := QuestionWithChoicesInput{Question: question, Choices: choices}
+		if id.Valid {
+			err = im.updateQuestion(ctx, table.layout, id, input)
+		} else {
+			err = im.createQuestion(ctx, table.sheet, line, input)
+		}
+		if err != nil {
+			erros = append(erros, err.Error())
+		}
+	}
+
+	if len(erros) > 0 {
+		report.Falharam++
+		report.Detalhes = append(report.Detalhes, ImportRowError{
+			Aba:     table.sheet,
+			Linha:   line,
+			Erros:   erros,
+			Valores: row,
+		})
+	}
+}
+
+// createQuestion creates the question of a valid row and records the
+// outcome: created, previewed in dry runs, or skipped as a duplicate. Other
+// failures are returned for the row to report.
+func (im *rowImporter) createQuestion(ctx context.Context, sheet string, line int, input QuestionWithChoicesInput) error {
+	report := im.report
+	_, _, err := im.create(ctx, input)
+
+	// Verifica se é erro de duplicidade (exata ou por similaridade)
+	var similar *SimilarQuestionsError
+	switch {
+	case errors.Is(err, ErrQuestionAlreadyExists):
+		report.Ignoradas++
+	case errors.As(err, &similar):
+		report.Ignoradas++
+		report.Duplicatas = append(report.Duplicatas, ImportDuplicate{
+			Aba:          sheet,
+			Linha:        line,
+			QuestaoID:    similar.Matches[0].ID,
+			Similaridade: similar.Matches[0].Score,
+		})
+	case err != nil:
+		return err
+	default:
+		report.Criadas++
+		if im.opts.DryRun {
+			preview := ImportPreview{
+				Aba:       sheet,
+				Linha:     line,
+				Enunciado: input.Question.Statement,
+				Ano:       input.Question.Year,
+				TopicID:   input.Question.TopicID,
+			}
+			for i, choice := range input.Choices {
+				preview.Alternativas = append(preview.Alternativas, choice.Text)
+				if choice.IsCorrect {
+					preview.Gabarito = answerLetter(input.Question, i)
+				}
+			}
+			report.Previa = append(report.Previa, preview)
+		}
+	}
+	return nil
+}
+
+// updateQuestion overwrites the existing question id with a row of a file
+// exported by ExportCSV or with its external_id. Columns the file lacks, or
+// an empty external_id, keep their current values, and the choices are
+// rewritten in place so that their IDs, and the answers recorded against
+// them, survive. Rows that change nothing are counted as ignored.
+func (im *rowImporter) updateQuestion(ctx context.Context, layout csvLayout, id pgtype.UUID, input QuestionWithChoicesInput) error {
+	changed := false
+	err := im.inRow(ctx, func(qtx *db.Queries) error {
+		current, err := qtx.GetQuestionForUpdate(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("questão %s não encontrada; remova o id para criá-la", id.String())
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao buscar questão: %w", err)
+		}
+
+		// Colunas ausentes do arquivo mantêm o valor atual
+		question := input.Question
+		question.ID = id
+		question.Explanation = current.Explanation
+		if _, ok := layout.columns["board"]; !ok {
+			question.Board = current.Board
+		}
+		if _, ok := layout.columns["choice_count"]; !ok {
+			question.ChoiceCount = current.ChoiceCount
+		}
+		if !question.ExternalID.Valid {
+			question.ExternalID = current.ExternalID
+		}
+
+		existing, err := qtx.ListChoicesByQuestion(ctx, id)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar alternativas: %w", err)
+		}
+		choices := slices.Clone(input.Choices)
+		same := sameQuestionFields(current, question) && len(existing) == len(choices)
+		for i := range choices {
+			if i < len(existing) {
+				choices[i].ID = existing[i].ID
+				same = same && existing[i].ChoiceText == choices[i].Text && existing[i].IsCorrect.Bool == choices[i].IsCorrect
+			}
+		}
+		if same && question.ExternalID == current.ExternalID {
+			return nil
+		}
+
+		changed = true
+		if question.ExternalID != current.ExternalID {
+			// O external_id não pode pertencer a outra questão
+			other, err := qtx.GetQuestionIDByExternalID(ctx, question.ExternalID)
+			if err == nil {
+				return fmt.Errorf("external_id %s já pertence à questão %s", question.ExternalID.String, other.String())
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("erro ao buscar external_id: %w", err)
+			}
+			if err := qtx.SetQuestionExternalID(ctx, db.SetQuestionExternalIDParams{ID: id, ExternalID: question.ExternalID}); err != nil {
+				return fmt.Errorf("erro ao definir external_id: %w", err)
+			}
+		}
+		if same {
+			return nil
+		}
+		_, _, err = replaceQuestionWithChoices(ctx, qtx, QuestionWithChoicesInput{Question: question, Choices: choices})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if changed {
+		im.report.Atualizadas++
+	} else {
+		im.report.Ignoradas++
+	}
+	return nil
+}
+
+// inRow runs fn for one row in a transaction of its own or, in dry-run and
+// atomic modes, under a savepoint of the import transaction.
+func (im *rowImporter) inRow(ctx context.Context, fn func(qtx *db.Queries) error) error {
+	return inTx(ctx, im.beginner(), fn)
+}
+
+// beginner returns the import transaction, under which new transactions
+// are savepoints, or the pool when rows commit on their own.
+func (im *rowImporter) beginner() beginner {
+	if im.tx != nil {
+		return im.tx
+	}
+	return im.svc.pool
+}
+
+// sameQuestionFields reports whether a and b hold the same question fields
+// a CSV row can set.
+func sameQuestionFields(a, b db.Question) bool {
+	return a.Statement == b.Statement && a.Year == b.Year && a.TopicID == b.TopicID &&
+		a.Position == b.Position && a.Level == b.Level && a.Difficulty == b.Difficulty &&
+		a.Modality == b.Modality && a.PracticeArea == b.PracticeArea && a.FieldOfStudy == b.FieldOfStudy &&
+		a.Board == b.Board && a.ChoiceCount == b.ChoiceCount
+}
+
+// seen records the external_id of a row read from the file and the topic
+// and subject it names, which bound deactivateMissing.
+func (im *rowImporter) seen(table *importTable, row []string) {
+	if externalID := table.layout.value(row, "external_id"); externalID != "" {
+		im.externalIDs[externalID] = true
+	}
+	topicID := pgtype.UUID{}
+	if err := topicID.Scan(table.layout.value(row, "topic_id")); err == nil && topicID.Valid {
+		im.scopeTopics[topicID] = true
+	}
+	subjectName := table.layout.value(row, "subject")
+	if _, ok := table.layout.columns["subject"]; !ok {
+		subjectName = table.subject
+	}
+	if key := nameKey(subjectName); key != "" {
+		im.scopeSubjects[key] = true
+	}
+}
+
+// deactivateMissing moves to the trash, in one transaction, the questions
+// with an external_id that no row of the file listed, so that the bank
+// mirrors a source that dropped them. Only questions in the subjects the
+// file names, by topic_id or by subject, are considered, so that files
+// from other sources are left alone, and a file without any external_id
+// is refused instead of emptying those subjects. The questions can be
+// restored from the trash.
+func (im *rowImporter) deactivateMissing(ctx context.Context) error {
+	if len(im.externalIDs) == 0 {
+		return fmt.Errorf("%w: deactivate_missing exige external_id preenchido em ao menos uma linha", ErrInvalidImport)
+	}
+	params := db.ListQuestionIDsMissingExternalIDsParams{
+		ExternalIds: make([]string, 0, len(im.externalIDs)),
+		SubjectIds:  []pgtype.UUID{},
+		TopicIds:    make([]pgtype.UUID, 0, len(im.scopeTopics)),
+	}
+	for externalID := range im.externalIDs {
+		params.ExternalIds = append(params.ExternalIds, externalID)
+	}
+	for topicID := range im.scopeTopics {
+		params.TopicIds = append(params.TopicIds, topicID)
+	}
+	// Matérias que não existem não têm questões a desativar
+	for key := range im.scopeSubjects {
+		if subject, ok := im.resolver.subjects[key]; ok {
+			params.SubjectIds = append(params.SubjectIds, subject.ID)
+		}
+	}
+	return im.inRow(ctx, func(qtx *db.Queries) error {
+		missing, err := qtx.ListQuestionIDsMissingExternalIDs(ctx, params)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar questões ausentes do arquivo: %w", err)
+		}
+		for _, id := range missing {
+			if err := softDelete(ctx, qtx, TrashKindQuestion, id); err != nil {
+				return fmt.Errorf("erro ao desativar questão %s: %w", id.String(), err)
+			}
+		}
+		im.report.Desativadas = len(missing)
+		return nil
+	})
+}
+
+// syncCreated copies the subjects and topics created so far to the report.
+func (im *rowImporter) syncCreated() {
+	im.report.MateriasCriadas = append(slices.Clip(im.subjects), im.resolver.CreatedSubjects...)
+	im.report.TopicosCriados = append(slices.Clip(im.topics), im.resolver.CreatedTopics...)
+}
+
+// finish commits an atomic import without failures. A failed atomic import
+// is rolled back and reported as undone.
+func (im *rowImporter) finish(ctx context.Context) error {
+	if !im.opts.Atomic || im.opts.DryRun {
+		return nil
+	}
+	if im.report.Falharam > 0 {
+		im.abort()
+		return nil
+	}
+	if err := im.tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar importação: %w", err)
+	}
+	return nil
+}
+
+// abort marks an atomic import as undone, since its transaction will be
+// rolled back, and returns the report.
+func (im *rowImporter) abort() ImportReport {
+	if im.opts.Atomic && !im.opts.DryRun {
+		im.report.Desfeita = true
+		im.report.Criadas = 0
+		im.report.Atualizadas = 0
+		im.report.Desativadas = 0
+		im.report.MateriasCriadas = nil
+		im.report.TopicosCriados = nil
+	}
+	return *im.report
+}
+
+func (im *rowImporter) rollback(ctx context.Context) {
+	if im.tx != nil {
+		im.tx.Rollback(context.WithoutCancel(ctx))
+	}
+}
+
+// ExportCSV writes the questions matching filters as CSV, in the legacy
+// column layout with the id and external_id columns in front and the board
+// and choice_count columns before the choices, so that the file can be
+// edited in a spreadsheet and imported again to update every field of the
+// same questions. Columns choice_f onwards are only added when a question has
+// more than five choices, and Certo/Errado questions are written without
+// choices and with C or E as the answer, as the import reads them. The
+// questions are loaded before anything is written, so an error leaves w
+// untouched.
+func (s *ImportService) ExportCSV(ctx context.Context, filters QuestionFilter, w io.Writer) error {
+	q := db.New(s.pool)
+	questions, err := listQuestionsByFilters(ctx, q, filters)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar questões: %w", err)
+	}
+	ids := make([]pgtype.UUID, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+	}
+	choices, err := q.ListChoicesByQuestions(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar alternativas: %w", err)
+	}
+	byQuestion := make(map[pgtype.UUID][]db.Choice, len(questions))
+	width := 5
+	for _, c := range choices {
+		byQuestion[c.QuestionID] = append(byQuestion[c.QuestionID], c)
+		width = max(width, len(byQuestion[c.QuestionID]))
+	}
+
+	// Mesmo layout do formato legado: campos da questão, alternativas e gabarito
+	fields := legacyCSVHeaders[:slices.Index(legacyCSVHeaders, "choice_a")]
+	header := append([]string{"id", "external_id"}, fields...)
+	header = append(header, "board", "choice_count")
+	for i := 0; i < width; i++ {
+		header = append(header, fmt.Sprintf("choice_%c", 'a'+i))
+	}
+	header = append(header, "correct_choice")
+
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, question := range questions {
+		record := []string{
+			question.ID.String(),
+			question.ExternalID.String,
+			question.Statement,
+			strconv.Itoa(int(question.Year)),
+			question.TopicID.String(),
+			question.Position.String,
+			question.Level.String,
+			question.Difficulty.String,
+			question.Modality.String,
+			question.PracticeArea.String,
+			question.FieldOfStudy.String,
+			question.Board.String,
+			"",
+		}
+		if question.ChoiceCount.Valid {
+			record[len(record)-1] = strconv.Itoa(int(question.ChoiceCount.Int32))
+		}
+		trueFalse := question.Modality.String == ModalityTrueFalse
+		correct := ""
+		for i := 0; i < width; i++ {
+			text := ""
+			if cs := byQuestion[question.ID]; i < len(cs) {
+				if !trueFalse {
+					text = cs[i].ChoiceText
+				}
+				if cs[i].IsCorrect.Bool {
+					correct = answerLetter(question, i)
+				}
+			}
+			record = append(record, text)
+		}
+		if err := out.Write(append(record, correct)); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// legacyCSVHeaders is the column order assumed when the CSV has no header:
+// the question fields, choice_a..choice_e and correct_choice.
+var legacyCSVHeaders = []string{
+	"statement", "year", "topic_id", "position", "level", "difficulty",
+	"modality", "practice_area", "field_of_study",
+	"choice_a", "choice_b", "choice_c", "choice_d", "choice_e", "correct_choice",
+}
+
+// requiredCSVHeaders must be present in every CSV header, along with
+// either topic_id or both subject and topic.
+var requiredCSVHeaders = []string{
+	"statement", "year", "position", "level", "difficulty",
+	"modality", "practice_area", "field_of_study", "correct_choice",
+}
+
+// trueFalseModalities are the normalized modality names read as
+// Certo/Errado.
+var trueFalseModalities = []string{"certo errado", "certo ou errado", "c e", "ce"}
+
+// isTrueFalseModality reports whether the modality of a row names
+// Certo/Errado, ignoring case, accents and punctuation.
+func isTrueFalseModality(v string) bool {
+	return slices.Contains(trueFalseModalities, NormalizeStatement(v))
+}
+
+// parseTrueFalseAnswer reads the correct_choice of a Certo/Errado row: C or
+// E, or the words certo and errado.
+func parseTrueFalseAnswer(v string) (isTrue, ok bool) {
+	switch NormalizeStatement(v) {
+	case "c", "certo":
+		return true, true
+	case "e", "errado":
+		return false, true
+	}
+	return false, false
+}
+
+// trueFalseChoices returns the two choices of a Certo/Errado question,
+// Certo first, with the answer marked correct.
+func trueFalseChoices(isTrue bool) []ChoiceInput {
+	return []ChoiceInput{
+		{Text: "Certo", IsCorrect: isTrue},
+		{Text: "Errado", IsCorrect: !isTrue},
+	}
+}
+
+// answerLetter returns how the answer key names choice i of question: its
+// letter, or C or E for the Certo and Errado choices of a Certo/Errado
+// question.
+func answerLetter(question db.Question, i int) string {
+	if question.Modality.String == ModalityTrueFalse && i < 2 {
+		return []string{"C", "E"}[i]
+	}
+	return string(rune('A' + i))
+}
+
+// maxExternalIDLength is the size of the external_id column.
+const maxExternalIDLength = 100
+
+// requiredRowFields are the question fields every row must fill.
+var requiredRowFields = []string{
+	"statement", "year", "position", "level", "difficulty",
+	"modality", "practice_area", "field_of_study",
+}
+
+// csvLayout maps the CSV columns to their position in a row.
+type csvLayout struct {
+	headers []string
+	columns map[string]int
+	// choices holds the positions of choice_a, choice_b, ... in letter order.
+	choices []int
+}
+
+// value returns the trimmed value of the named column, or "" when the
+// layout has no such column or the row is too short to have it.
+func (l csvLayout) value(row []string, name string) string {
+	idx, ok := l.columns[name]
+	if !ok || idx >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[idx])
+}
+
+// parseCSVHeader reads the column layout from the first row of a CSV. The
+// header may list the columns in any order, by their names or Portuguese
+// aliases (see headerName), with the optional id, external_id, board and
+// choice_count columns and from choice_a up to choice_j; unknown columns
+// are ignored; files with only Certo/Errado questions need no choice
+// columns. The topic is given
+// by topic_id, by the subject and topic names, or by both, in which case
+// rows with a topic_id ignore the names; with sheetSubject, the name of the
+// spreadsheet sheet stands in for a missing subject column. When the first
+// row is not a header, the legacy layout is returned and isHeader is false.
+func parseCSVHeader(row []string, sheetSubject bool) (layout csvLayout, isHeader bool, err error) {
+	names := make([]string, len(row))
+	for i, v := range row {
+		names[i] = headerName(v)
+	}
+	if !slices.Contains(names, "statement") {
+		return newCSVLayout(legacyCSVHeaders), false, nil
+	}
+	for i, name := range names {
+		if name != "" && slices.Contains(names[i+1:], name) {
+			return csvLayout{}, true, fmt.Errorf("coluna duplicada no cabeçalho: %s", name)
+		}
+	}
+
+	layout = newCSVLayout(names)
+	for _, name := range requiredCSVHeaders {
+		if _, ok := layout.columns[name]; !ok {
+			return csvLayout{}, true, fmt.Errorf("coluna obrigatória ausente no cabeçalho: %s", name)
+		}
+	}
+	_, hasTopicID := layout.columns["topic_id"]
+	_, hasSubject := layout.columns["subject"]
+	_, hasTopic := layout.columns["topic"]
+	if !hasTopicID && !((hasSubject || sheetSubject) && hasTopic) {
+		return csvLayout{}, true, errors.New("o cabeçalho deve ter a coluna topic_id ou as colunas subject e topic")
+	}
+	return layout, true, nil
+}
+
+func newCSVLayout(headers []string) csvLayout {
+	layout := csvLayout{headers: headers, columns: make(map[string]int, len(headers))}
+	for i, name := range headers {
+		layout.columns[name] = i
+	}
+	for i := 0; i < MaxChoiceCount; i++ {
+		idx, ok := layout.columns[fmt.Sprintf("choice_%c", 'a'+i)]
+		if !ok {
+			break
+		}
+		layout.choices = append(layout.choices, idx)
+	}
+	return layout
+}
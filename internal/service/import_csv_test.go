@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseCSVHeader(t *testing.T) {
+	tests := []struct {
+		name         string
+		row          []string
+		sheetSubject bool
+		wantHeader   bool
+		wantErr      string
+		wantColumns  []string
+		wantChoices  int
+	}{
+		{
+			name:        "legacy row without header",
+			row:         []string{"Qual é a capital?", "2020", "topic", "Analista", "Superior", "Fácil", "Múltipla escolha", "TI", "Computação", "a", "b", "c", "d", "e", "A"},
+			wantColumns: legacyCSVHeaders,
+			wantChoices: 5,
+		},
+		{
+			name:        "topic_id",
+			row:         []string{" Statement ", "YEAR", "topic_id", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "correct_choice"},
+			wantHeader:  true,
+			wantColumns: []string{"statement", "year", "topic_id", "choice_b", "correct_choice"},
+			wantChoices: 2,
+		},
+		{
+			name:        "subject and topic names",
+			row:         []string{"statement", "year", "subject", "topic", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "choice_c", "correct_choice"},
+			wantHeader:  true,
+			wantColumns: []string{"subject", "topic"},
+			wantChoices: 3,
+		},
+		{
+			name:        "topic_id with names",
+			row:         []string{"statement", "year", "topic_id", "subject", "topic", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "correct_choice"},
+			wantHeader:  true,
+			wantColumns: []string{"topic_id", "subject", "topic"},
+			wantChoices: 2,
+		},
+		{
+			name:        "portuguese aliases",
+			row:         []string{"Enunciado", "Ano", "Matéria", "Tópico", "Cargo", "Nível", "Dificuldade", "Modalidade", "Área de atuação", "Formação", "Banca", "Alternativa A", "Alternativa B", "Gabarito"},
+			wantHeader:  true,
+			wantColumns: []string{"statement", "year", "subject", "topic", "practice_area", "field_of_study", "board", "choice_a", "choice_b", "correct_choice"},
+			wantChoices: 2,
+		},
+		{
+			name:         "sheet name stands in for subject",
+			row:          []string{"statement", "year", "topic", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "correct_choice"},
+			sheetSubject: true,
+			wantHeader:   true,
+			wantColumns:  []string{"topic"},
+			wantChoices:  2,
+		},
+		{
+			name:        "choices stop at the first missing letter",
+			row:         []string{"statement", "year", "topic_id", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "choice_d", "correct_choice"},
+			wantHeader:  true,
+			wantColumns: []string{"choice_a", "choice_b", "choice_d"},
+			wantChoices: 2,
+		},
+		{
+			name:       "duplicate column",
+			row:        []string{"statement", "Enunciado", "year"},
+			wantHeader: true,
+			wantErr:    "coluna duplicada no cabeçalho: statement",
+		},
+		{
+			name:       "missing required column",
+			row:        []string{"statement", "year", "topic_id", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b"},
+			wantHeader: true,
+			wantErr:    "coluna obrigatória ausente no cabeçalho: correct_choice",
+		},
+		{
+			name:       "topic without subject",
+			row:        []string{"statement", "year", "topic", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "choice_a", "choice_b", "correct_choice"},
+			wantHeader: true,
+			wantErr:    "o cabeçalho deve ter a coluna topic_id ou as colunas subject e topic",
+		},
+		{
+			name:        "certo/errado file without choice columns",
+			row:         []string{"statement", "year", "topic_id", "position", "level", "difficulty", "modality", "practice_area", "field_of_study", "correct_choice"},
+			wantHeader:  true,
+			wantColumns: []string{"statement", "modality", "correct_choice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, isHeader, err := parseCSVHeader(tt.row, tt.sheetSubject)
+			if isHeader != tt.wantHeader {
+				t.Errorf("isHeader = %v, want %v", isHeader, tt.wantHeader)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, name := range tt.wantColumns {
+				idx, ok := layout.columns[name]
+				if !ok {
+					t.Errorf("column %q missing from layout", name)
+					continue
+				}
+				if isHeader && headerName(tt.row[idx]) != name {
+					t.Errorf("column %q points to %q", name, tt.row[idx])
+				}
+			}
+			if len(layout.choices) != tt.wantChoices {
+				t.Errorf("len(choices) = %d, want %d", len(layout.choices), tt.wantChoices)
+			}
+		})
+	}
+}
+
+func TestCSVLayoutValue(t *testing.T) {
+	layout := newCSVLayout([]string{"statement", "subject", "topic"})
+	row := []string{"  Enunciado  ", " Direito Constitucional ", "Direitos fundamentais"}
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"statement", "Enunciado"},
+		{"subject", "Direito Constitucional"},
+		{"topic", "Direitos fundamentais"},
+		{"topic_id", ""},
+	}
+	for _, tt := range tests {
+		if got := layout.value(row, tt.column); got != tt.want {
+			t.Errorf("value(%q) = %q, want %q", tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestIsTrueFalseModality(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{ModalityTrueFalse, true},
+		{"Certo/Errado", true},
+		{"certo ou errado", true},
+		{"CERTO-ERRADO", true},
+		{"C/E", true},
+		{"CE", true},
+		{"Múltipla escolha", false},
+		{"certo", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isTrueFalseModality(tt.in); got != tt.want {
+			t.Errorf("isTrueFalseModality(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrueFalseAnswer(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantTrue   bool
+		wantParsed bool
+	}{
+		{"C", true, true},
+		{"c", true, true},
+		{" Certo ", true, true},
+		{"CERTO", true, true},
+		{"E", false, true},
+		{"errado", false, true},
+		{"Errado.", false, true},
+		{"A", false, false},
+		{"verdadeiro", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		isTrue, ok := parseTrueFalseAnswer(tt.in)
+		if isTrue != tt.wantTrue || ok != tt.wantParsed {
+			t.Errorf("parseTrueFalseAnswer(%q) = (%v, %v), want (%v, %v)", tt.in, isTrue, ok, tt.wantTrue, tt.wantParsed)
+		}
+	}
+}
+
+func TestTrueFalseChoices(t *testing.T) {
+	for _, isTrue := range []bool{true, false} {
+		choices := trueFalseChoices(isTrue)
+		if choices[0].Text != "Certo" || choices[1].Text != "Errado" {
+			t.Errorf("trueFalseChoices(%v) = %+v, want Certo then Errado", isTrue, choices)
+		}
+		if choices[0].IsCorrect != isTrue || choices[1].IsCorrect == isTrue {
+			t.Errorf("trueFalseChoices(%v) marks the wrong answer", isTrue)
+		}
+	}
+}
+
+func TestRowImporterSeen(t *testing.T) {
+	topicID := "6f1c1a52-4d7e-4b1f-9a55-2a3b4c5d6e7f"
+	im := &rowImporter{
+		externalIDs:   make(map[string]bool),
+		scopeTopics:   make(map[pgtype.UUID]bool),
+		scopeSubjects: make(map[string]bool),
+	}
+	csvTable := &importTable{layout: newCSVLayout([]string{"external_id", "topic_id", "subject"})}
+	im.seen(csvTable, []string{"Q1", topicID, ""})
+	im.seen(csvTable, []string{"", "", " Português "})
+	sheet := &importTable{layout: newCSVLayout([]string{"external_id", "topic"}), subject: "Direito Civil"}
+	im.seen(sheet, []string{"Q2", "Contratos"})
+
+	if !reflect.DeepEqual(im.externalIDs, map[string]bool{"Q1": true, "Q2": true}) {
+		t.Errorf("externalIDs = %v", im.externalIDs)
+	}
+	if len(im.scopeTopics) != 1 {
+		t.Errorf("scopeTopics = %v, want only %s", im.scopeTopics, topicID)
+	}
+	wantSubjects := map[string]bool{nameKey("Português"): true, nameKey("Direito Civil"): true}
+	if !reflect.DeepEqual(im.scopeSubjects, wantSubjects) {
+		t.Errorf("scopeSubjects = %v, want %v", im.scopeSubjects, wantSubjects)
+	}
+}
+
+func TestDeactivateMissingWithoutExternalIDs(t *testing.T) {
+	im := &rowImporter{externalIDs: make(map[string]bool)}
+	if err := im.deactivateMissing(context.Background()); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("deactivateMissing() error = %v, want ErrInvalidImport", err)
+	}
+}
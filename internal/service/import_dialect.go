@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// csvEncodings are the encodings accepted by the encoding option, keyed by
+// the names users may give them.
+var csvEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8BOM,
+	"utf8":         unicode.UTF8BOM,
+	"utf-16":       unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"windows-1252": charmap.Windows1252,
+	"cp1252":       charmap.Windows1252,
+	"latin1":       charmap.ISO8859_1,
+	"latin-1":      charmap.ISO8859_1,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// csvDelimiters are the delimiters tried when detecting the dialect, in
+// order of preference on a tie.
+var csvDelimiters = []rune{',', ';', '\t', '|'}
+
+// dialectSampleSize is how much of the file is read to detect the dialect.
+const dialectSampleSize = 64 << 10
+
+// csvDialect is the encoding and delimiter of a CSV file.
+type csvDialect struct {
+	encoding  string
+	delimiter rune
+}
+
+// openCSV returns the content of a CSV file decoded to UTF-8, without byte
+// order mark, and its dialect. The encoding and delimiter come from opts
+// when given; otherwise a byte order mark decides the encoding, content
+// that is not valid UTF-8 is read as Windows-1252, the encoding of
+// spreadsheets exported by Excel in Brazil, and the delimiter is the most
+// frequent of comma, semicolon, tab and pipe in the first line.
+func openCSV(r io.Reader, opts ImportOptions) (io.Reader, csvDialect, error) {
+	raw := bufio.NewReaderSize(r, dialectSampleSize)
+	sample, _ := raw.Peek(dialectSampleSize)
+
+	var dialect csvDialect
+	if opts.Encoding != "" {
+		dialect.encoding = strings.ToLower(strings.TrimSpace(opts.Encoding))
+		if _, ok := csvEncodings[dialect.encoding]; !ok {
+			return nil, csvDialect{}, fmt.Errorf("%w: encoding não suportado: %s", ErrInvalidImport, opts.Encoding)
+		}
+	} else {
+		dialect.encoding = detectEncoding(sample)
+	}
+	decoded := bufio.NewReaderSize(transform.NewReader(raw, csvEncodings[dialect.encoding].NewDecoder()), dialectSampleSize)
+
+	if opts.Delimiter != "" {
+		delimiter, err := parseDelimiter(opts.Delimiter)
+		if err != nil {
+			return nil, csvDialect{}, err
+		}
+		dialect.delimiter = delimiter
+	} else {
+		text, _ := decoded.Peek(dialectSampleSize)
+		dialect.delimiter = detectDelimiter(text)
+	}
+	return decoded, dialect, nil
+}
+
+func detectEncoding(sample []byte) string {
+	switch {
+	case bytes.HasPrefix(sample, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}), bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		return "utf-16"
+	}
+	// A amostra pode terminar no meio de um caractere
+	if utf8.Valid(trimPartialRune(sample)) {
+		return "utf-8"
+	}
+	return "windows-1252"
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence at the end of b.
+func trimPartialRune(b []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// detectDelimiter counts the candidate delimiters outside quotes in the
+// first line of text.
+func detectDelimiter(text []byte) rune {
+	counts := make(map[rune]int, len(csvDelimiters))
+	quoted := false
+	for _, r := range string(text) {
+		if r == '"' {
+			quoted = !quoted
+			continue
+		}
+		if quoted {
+			continue
+		}
+		if r == '\n' {
+			break
+		}
+		counts[r]++
+	}
+	best := csvDelimiters[0]
+	for _, d := range csvDelimiters[1:] {
+		if counts[d] > counts[best] {
+			best = d
+		}
+	}
+	return best
+}
+
+// parseDelimiter reads the delimiter option: a single character, or "tab".
+func parseDelimiter(v string) (rune, error) {
+	if strings.EqualFold(v, "tab") || v == `\t` {
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(v)
+	if size != len(v) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("%w: delimiter inválido: %q", ErrInvalidImport, v)
+	}
+	return r, nil
+}
+
+// csvHeaderAliases maps the Portuguese column names accepted in headers to
+// the canonical ones, after headerName normalization.
+var csvHeaderAliases = map[string]string{
+	"enunciado":                  "statement",
+	"questao":                    "statement",
+	"pergunta":                   "statement",
+	"ano":                        "year",
+	"id_topico":                  "topic_id",
+	"topico_id":                  "topic_id",
+	"materia":                    "subject",
+	"disciplina":                 "subject",
+	"topico":                     "topic",
+	"assunto":                    "topic",
+	"cargo":                      "position",
+	"nivel":                      "level",
+	"escolaridade":               "level",
+	"dificuldade":                "difficulty",
+	"modalidade":                 "modality",
+	"area":                       "practice_area",
+	"area_de_atuacao":            "practice_area",
+	"formacao":                   "field_of_study",
+	"area_de_formacao":           "field_of_study",
+	"area_de_conhecimento":       "field_of_study",
+	"banca":                      "board",
+	"quantidade_de_alternativas": "choice_count",
+	"qtd_alternativas":           "choice_count",
+	"gabarito":                   "correct_choice",
+	"resposta":                   "correct_choice",
+	"resposta_correta":           "correct_choice",
+	"alternativa_correta":        "correct_choice",
+	"id_externo":                 "external_id",
+	"codigo_externo":             "external_id",
+}
+
+func init() {
+	for i := 0; i < MaxChoiceCount; i++ {
+		letter := string(rune('a' + i))
+		csvHeaderAliases["alternativa_"+letter] = "choice_" + letter
+		csvHeaderAliases["opcao_"+letter] = "choice_" + letter
+	}
+}
+
+// headerName returns the canonical name of a header column. Names are
+// matched ignoring case, accents and punctuation, so "Área de atuação" and
+// "area_de_atuacao" both name practice_area.
+func headerName(v string) string {
+	name := strings.ReplaceAll(NormalizeStatement(v), " ", "_")
+	if canonical, ok := csvHeaderAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
@@ -0,0 +1,78 @@
+package service
+
+import "testing"
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []byte
+		want   string
+	}{
+		{"empty", nil, "utf-8"},
+		{"ascii", []byte("statement,year\n"), "utf-8"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFenunciado;ano\n"), "utf-8"},
+		{"utf-8 accents", []byte("Área de atuação\n"), "utf-8"},
+		{"utf-8 cut mid rune", []byte("atua\xC3"), "utf-8"},
+		{"utf-16 little endian", []byte{0xFF, 0xFE, 'a', 0}, "utf-16"},
+		{"utf-16 big endian", []byte{0xFE, 0xFF, 0, 'a'}, "utf-16"},
+		{"windows-1252 accents", []byte("atua\xE7\xE3o;quest\xE3o\n"), "windows-1252"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectEncoding(tt.sample); got != tt.want {
+				t.Errorf("detectEncoding(%q) = %q, want %q", tt.sample, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want rune
+	}{
+		{"empty", "", ','},
+		{"comma", "statement,year,topic_id\n", ','},
+		{"semicolon", "enunciado;ano;topico\n", ';'},
+		{"tab", "statement\tyear\ttopic_id\n", '\t'},
+		{"pipe", "statement|year|topic_id\n", '|'},
+		{"quoted commas ignored", "\"a, b, c\";ano;topico\n", ';'},
+		{"first line only", "statement;year\na,b,c,d,e\n", ';'},
+		{"tie keeps earlier candidate", "a,b;c\n", ','},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectDelimiter([]byte(tt.text)); got != tt.want {
+				t.Errorf("detectDelimiter(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"statement", "statement"},
+		{"  Statement ", "statement"},
+		{"Enunciado", "statement"},
+		{"Área de atuação", "practice_area"},
+		{"area_de_atuacao", "practice_area"},
+		{"ID Tópico", "topic_id"},
+		{"Matéria", "subject"},
+		{"Qtd. Alternativas", "choice_count"},
+		{"Gabarito", "correct_choice"},
+		{"Alternativa A", "choice_a"},
+		{"opção j", "choice_j"},
+		{"Código Externo", "external_id"},
+		{"Comentário", "comentario"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := headerName(tt.in); got != tt.want {
+			t.Errorf("headerName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
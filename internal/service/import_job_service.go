@@ -0,0 +1,365 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Statuses of a background import job.
+const (
+	ImportJobQueued    = "queued"
+	ImportJobRunning   = "running"
+	ImportJobFinished  = "finished"
+	ImportJobFailed    = "failed"
+	ImportJobCancelled = "cancelled"
+)
+
+// MaxImportFileSize caps the size of the files accepted by background
+// imports.
+const MaxImportFileSize = 256 << 20
+
+// importJobPollInterval is how often the worker looks for queued jobs
+// besides being woken up by new submissions.
+const importJobPollInterval = 5 * time.Second
+
+// ErrImportJobClosed is returned when cancelling a job that already ended.
+var ErrImportJobClosed = errors.New("a importação já foi encerrada")
+
+// ImportJobStatus is the progress of a background import.
+type ImportJobStatus struct {
+	ID              pgtype.UUID        `json:"id"`
+	Filename        string             `json:"filename"`
+	Options         ImportOptions      `json:"options"`
+	Status          string             `json:"status"`
+	CreatedBy       string             `json:"created_by"`
+	Processed       int32              `json:"processed"`
+	Created         int32              `json:"created"`
+	Ignored         int32              `json:"ignored"`
+	Failed          int32              `json:"failed"`
+	Error           pgtype.Text        `json:"error"`
+	CancelRequested bool               `json:"cancel_requested"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	StartedAt       pgtype.Timestamptz `json:"started_at"`
+	FinishedAt      pgtype.Timestamptz `json:"finished_at"`
+	Report          *ImportReport      `json:"report,omitempty"`
+}
+
+// ImportJobService runs CSV imports in the background. Jobs and their
+// files are stored in the database, so queued jobs and jobs interrupted by
+// a restart are picked up again when the worker starts.
+type ImportJobService struct {
+	pool    *pgxpool.Pool
+	q       db.Querier
+	imports *ImportService
+
+	wake    chan struct{}
+	mu      sync.Mutex
+	cancels map[pgtype.UUID]context.CancelFunc
+}
+
+// NewImportJobService creates a new ImportJobService. Jobs only run once
+// Run is started.
+func NewImportJobService(pool *pgxpool.Pool, imports *ImportService) *ImportJobService {
+	return &ImportJobService{
+		pool:    pool,
+		q:       db.New(pool),
+		imports: imports,
+		wake:    make(chan struct{}, 1),
+		cancels: make(map[pgtype.UUID]context.CancelFunc),
+	}
+}
+
+// SubmitImport stores the file and queues its import on behalf of the
+// request user.
+func (s *ImportJobService) SubmitImport(ctx context.Context, filename string, content []byte, opts ImportOptions) (ImportJobStatus, error) {
+	options, err := json.Marshal(opts)
+	if err != nil {
+		return ImportJobStatus{}, err
+	}
+
+	var job db.ImportJob
+	err = inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		job, err = qtx.CreateImportJob(ctx, db.CreateImportJobParams{
+			Filename:  filename,
+			Options:   options,
+			CreatedBy: UserFromContext(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao criar importação: %w", err)
+		}
+		if err := qtx.CreateImportJobFile(ctx, db.CreateImportJobFileParams{JobID: job.ID, Content: content}); err != nil {
+			return fmt.Errorf("erro ao salvar arquivo: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportJobStatus{}, err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return importJobStatus(job, false)
+}
+
+// GetImportJob returns the progress of a job and its report so far.
+func (s *ImportJobService) GetImportJob(ctx context.Context, id pgtype.UUID) (ImportJobStatus, error) {
+	job, err := s.q.GetImportJob(ctx, id)
+	if err != nil {
+		return ImportJobStatus{}, err
+	}
+	return importJobStatus(job, true)
+}
+
+// ListImportJobs returns every job, newest first, without their reports.
+func (s *ImportJobService) ListImportJobs(ctx context.Context) ([]ImportJobStatus, error) {
+	jobs, err := s.q.ListImportJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]ImportJobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		status, err := importJobStatus(job, false)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CancelImportJob cancels a queued job right away and stops a running one
+// after its current row. Rows already imported stay, unless the job is a
+// dry run or an atomic import.
+func (s *ImportJobService) CancelImportJob(ctx context.Context, id pgtype.UUID) (ImportJobStatus, error) {
+	job, err := s.q.RequestImportJobCancel(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := s.q.GetImportJob(ctx, id); err != nil {
+			return ImportJobStatus{}, err
+		}
+		return ImportJobStatus{}, ErrImportJobClosed
+	}
+	if err != nil {
+		return ImportJobStatus{}, err
+	}
+
+	if job.Status == ImportJobCancelled {
+		if err := s.q.DeleteImportJobFile(ctx, id); err != nil {
+			return ImportJobStatus{}, fmt.Errorf("erro ao descartar arquivo: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.cancels[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+	return importJobStatus(job, false)
+}
+
+// ImportJobReport returns the report of a job, empty while it has not
+// finished its first row.
+func (s *ImportJobService) ImportJobReport(ctx context.Context, id pgtype.UUID) (ImportReport, error) {
+	job, err := s.q.GetImportJob(ctx, id)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	var report ImportReport
+	if job.Report != nil {
+		if err := json.Unmarshal(job.Report, &report); err != nil {
+			return ImportReport{}, fmt.Errorf("erro ao ler relatório: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// Run processes queued jobs one at a time until ctx is cancelled. Jobs
+// left running by a previous process are queued again first.
+func (s *ImportJobService) Run(ctx context.Context) {
+	n, err := s.q.RequeueRunningImportJobs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error requeuing import jobs", "error", err)
+	} else if n > 0 {
+		slog.InfoContext(ctx, "Interrupted import jobs requeued", "count", n)
+	}
+
+	ticker := time.NewTicker(importJobPollInterval)
+	defer ticker.Stop()
+	for {
+		for s.runNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNext claims and runs the oldest queued job, reporting whether there
+// was one.
+func (s *ImportJobService) runNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	job, err := s.q.ClaimImportJob(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error claiming import job", "error", err)
+		return false
+	}
+	s.runJob(ctx, job)
+	return true
+}
+
+func (s *ImportJobService) runJob(ctx context.Context, job db.ImportJob) {
+	slog.InfoContext(ctx, "Running import job", "job_id", job.ID, "filename", job.Filename)
+
+	var opts ImportOptions
+	if err := json.Unmarshal(job.Options, &opts); err != nil {
+		s.finishJob(ctx, job.ID, ImportJobFailed, ImportReport{}, fmt.Errorf("erro ao ler opções: %w", err))
+		return
+	}
+	// Importações linha a linha retomam da última linha gravada; dry_run e
+	// atomic recomeçam do início, pois a transação anterior foi perdida
+	var report ImportReport
+	if job.Report != nil && !opts.DryRun && !opts.Atomic {
+		if err := json.Unmarshal(job.Report, &report); err != nil {
+			s.finishJob(ctx, job.ID, ImportJobFailed, ImportReport{}, fmt.Errorf("erro ao ler relatório: %w", err))
+			return
+		}
+	}
+	if job.CancelRequested {
+		s.finishJob(ctx, job.ID, ImportJobCancelled, report, nil)
+		return
+	}
+	content, err := s.q.GetImportJobFile(ctx, job.ID)
+	if err != nil {
+		s.finishJob(ctx, job.ID, ImportJobFailed, report, fmt.Errorf("erro ao ler arquivo: %w", err))
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(WithUser(ctx, job.CreatedBy))
+	s.mu.Lock()
+	s.cancels[job.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.cancels, job.ID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	// O progresso é gravado na transação da própria linha, de modo que uma
+	// retomada nunca repete linhas já gravadas
+	report, err = s.imports.importFile(jobCtx, job.Filename, bytes.NewReader(content), opts, report, func(q db.Querier, r ImportReport) error {
+		if err := q.UpdateImportJobProgress(ctx, db.UpdateImportJobProgressParams{
+			ID:        job.ID,
+			Processed: int32(r.Total),
+			Created:   int32(r.Criadas),
+			Ignored:   int32(r.Ignoradas),
+			Failed:    int32(r.Falharam),
+			Report:    encodeImportReport(r),
+		}); err != nil {
+			return fmt.Errorf("erro ao salvar progresso: %w", err)
+		}
+		// Cobre cancelamentos pedidos antes de o job registrar sua função de
+		// cancelamento; a linha atual ainda é gravada
+		if current, err := s.q.GetImportJob(ctx, job.ID); err == nil && current.CancelRequested {
+			cancel()
+		}
+		return nil
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		// O servidor está encerrando: o job segue como running e é retomado
+		// na próxima inicialização
+		slog.InfoContext(ctx, "Import job interrupted", "job_id", job.ID)
+	case errors.Is(err, context.Canceled):
+		s.finishJob(ctx, job.ID, ImportJobCancelled, report, nil)
+	case err != nil:
+		s.finishJob(ctx, job.ID, ImportJobFailed, report, err)
+	default:
+		s.finishJob(ctx, job.ID, ImportJobFinished, report, nil)
+	}
+}
+
+// finishJob records the final state of a job and discards its file.
+func (s *ImportJobService) finishJob(ctx context.Context, id pgtype.UUID, status string, report ImportReport, jobErr error) {
+	params := db.FinishImportJobParams{
+		ID:        id,
+		Status:    status,
+		Processed: int32(report.Total),
+		Created:   int32(report.Criadas),
+		Ignored:   int32(report.Ignoradas),
+		Failed:    int32(report.Falharam),
+		Report:    encodeImportReport(report),
+	}
+	if jobErr != nil {
+		params.Error = pgtype.Text{String: jobErr.Error(), Valid: true}
+	}
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.FinishImportJob(ctx, params); err != nil {
+			return err
+		}
+		return qtx.DeleteImportJobFile(ctx, id)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Error finishing import job", "job_id", id, "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "Import job finished", "job_id", id, "status", status, "processed", report.Total, "created", report.Criadas, "failed", report.Falharam)
+}
+
+func importJobStatus(job db.ImportJob, withReport bool) (ImportJobStatus, error) {
+	status := ImportJobStatus{
+		ID:              job.ID,
+		Filename:        job.Filename,
+		Status:          job.Status,
+		CreatedBy:       job.CreatedBy,
+		Processed:       job.Processed,
+		Created:         job.Created,
+		Ignored:         job.Ignored,
+		Failed:          job.Failed,
+		Error:           job.Error,
+		CancelRequested: job.CancelRequested,
+		CreatedAt:       job.CreatedAt,
+		StartedAt:       job.StartedAt,
+		FinishedAt:      job.FinishedAt,
+	}
+	if err := json.Unmarshal(job.Options, &status.Options); err != nil {
+		return ImportJobStatus{}, fmt.Errorf("erro ao ler opções: %w", err)
+	}
+	if withReport && job.Report != nil {
+		status.Report = &ImportReport{}
+		if err := json.Unmarshal(job.Report, status.Report); err != nil {
+			return ImportJobStatus{}, fmt.Errorf("erro ao ler relatório: %w", err)
+		}
+	}
+	return status, nil
+}
+
+func encodeImportReport(report ImportReport) []byte {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil
+	}
+	return data
+}
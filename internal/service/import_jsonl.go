@@ -0,0 +1,321 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// QuestionRecord is one line of the JSON Lines format: a question with the
+// names of its subject and topic, its choices in order and its legal
+// references. Exporting a bank and importing the file elsewhere recreates
+// every question as it was, ID included; the review status is kept only
+// with ImportOptions.KeepReviewStatus.
+type QuestionRecord struct {
+	ID              pgtype.UUID      `json:"id"`
+	Statement       string           `json:"statement"`
+	Year            int32            `json:"year"`
+	Subject         string           `json:"subject"`
+	Topic           string           `json:"topic"`
+	TopicID         pgtype.UUID      `json:"topic_id"`
+	Position        pgtype.Text      `json:"position"`
+	Level           pgtype.Text      `json:"level"`
+	Difficulty      pgtype.Text      `json:"difficulty"`
+	Modality        pgtype.Text      `json:"modality"`
+	PracticeArea    pgtype.Text      `json:"practice_area"`
+	FieldOfStudy    pgtype.Text      `json:"field_of_study"`
+	Board           pgtype.Text      `json:"board"`
+	ChoiceCount     pgtype.Int4      `json:"choice_count"`
+	Explanation     pgtype.Text      `json:"explanation"`
+	ExternalID      pgtype.Text      `json:"external_id"`
+	ReviewStatus    string           `json:"review_status"`
+	Choices         []ChoiceRecord   `json:"choices"`
+	LegalReferences []LegalReference `json:"legal_references"`
+}
+
+// ChoiceRecord is a choice of a QuestionRecord.
+type ChoiceRecord struct {
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+// exportBatchSize is how many questions ExportQuestions loads at a time.
+const exportBatchSize = 500
+
+// ExportQuestions passes every active question of the bank to fn, a batch
+// at a time and ordered by ID, so that the bank can be streamed without
+// being loaded at once. All batches are read from the same snapshot.
+func (s *ImportService) ExportQuestions(ctx context.Context, fn func([]QuestionRecord) error) error {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := db.New(tx)
+
+	after := pgtype.UUID{}
+	for {
+		rows, err := q.ListQuestionsForExport(ctx, db.ListQuestionsForExportParams{After: after, BatchSize: exportBatchSize})
+		if err != nil {
+			return fmt.Errorf("erro ao buscar questões: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		ids := make([]pgtype.UUID, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ID
+		}
+		choices, err := q.ListChoicesByQuestions(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar alternativas: %w", err)
+		}
+		refs, err := q.ListLegalReferencesByQuestions(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar referências legais: %w", err)
+		}
+
+		records := make([]QuestionRecord, len(rows))
+		index := make(map[pgtype.UUID]*QuestionRecord, len(rows))
+		for i, row := range rows {
+			records[i] = QuestionRecord{
+				ID:              row.ID,
+				Statement:       row.Statement,
+				Year:            row.Year,
+				Subject:         row.SubjectName,
+				Topic:           row.TopicName,
+				TopicID:         row.TopicID,
+				Position:        row.Position,
+				Level:           row.Level,
+				Difficulty:      row.Difficulty,
+				Modality:        row.Modality,
+				PracticeArea:    row.PracticeArea,
+				FieldOfStudy:    row.FieldOfStudy,
+				Board:           row.Board,
+				ChoiceCount:     row.ChoiceCount,
+				Explanation:     row.Explanation,
+				ExternalID:      row.ExternalID,
+				ReviewStatus:    row.ReviewStatus,
+				Choices:         []ChoiceRecord{},
+				LegalReferences: []LegalReference{},
+			}
+			index[row.ID] = &records[i]
+		}
+		for _, c := range choices {
+			record := index[c.QuestionID]
+			record.Choices = append(record.Choices, ChoiceRecord{Text: c.ChoiceText, IsCorrect: c.IsCorrect.Bool})
+		}
+		for _, ref := range refs {
+			record := index[ref.QuestionID]
+			record.LegalReferences = append(record.LegalReferences, LegalReference{
+				Law:       ref.Law,
+				Article:   ref.Article.String,
+				Paragraph: ref.Paragraph.String,
+			})
+		}
+
+		if err := fn(records); err != nil {
+			return err
+		}
+		if len(rows) < exportBatchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].ID
+	}
+}
+
+// ImportJSONL creates questions from a JSON Lines file with a
+// QuestionRecord per line, as written by the export. The topic is taken
+// from topic_id when it exists in this bank and from the subject and topic
+// names otherwise, so files move between environments; questions whose ID
+// or external_id already exists are skipped, which makes importing a file
+// again harmless. Lines are read one at a time and reported like CSV rows.
+// DeactivateMissing is not supported, since the file holds the whole bank
+// rather than the questions of one source.
+func (s *ImportService) ImportJSONL(ctx context.Context, r io.Reader, opts ImportOptions) (ImportReport, error) {
+	return s.importJSONL(ctx, r, opts, ImportReport{}, nil)
+}
+
+func (s *ImportService) importJSONL(ctx context.Context, r io.Reader, opts ImportOptions, report ImportReport, progress func(db.Querier, ImportReport) error) (ImportReport, error) {
+	if opts.DeactivateMissing {
+		return report, fmt.Errorf("%w: deactivate_missing exige a coluna external_id de um CSV ou XLSX", ErrInvalidImport)
+	}
+	im, err := s.newRowImporter(ctx, opts, &report)
+	if err != nil {
+		return report, err
+	}
+	defer im.rollback(ctx)
+	report.DryRun = opts.DryRun
+	report.Atomica = opts.Atomic
+
+	// Linhas longas são comuns (enunciados e comentários extensos), por isso
+	// ReadBytes em vez de bufio.Scanner, que limita o tamanho da linha
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return im.abort(), fmt.Errorf("erro ao ler arquivo: %w", readErr)
+		}
+		if line == 1 {
+			data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := im.step(ctx, progress, func() { im.importRecord(ctx, line, data) }); err != nil {
+				return im.abort(), err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if err := im.finish(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// importRecord validates and creates the question of one JSON line,
+// recording the outcome in the report.
+func (im *rowImporter) importRecord(ctx context.Context, line int, data []byte) {
+	report := im.report
+	defer im.syncCreated()
+	report.Total++
+
+	var rec QuestionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		report.Falharam++
+		report.Detalhes = append(report.Detalhes, ImportRowError{
+			Linha: line,
+			Erros: []string{fmt.Sprintf("json inválido: %v", err)},
+		})
+		return
+	}
+
+	erros := []string{}
+	if strings.TrimSpace(rec.Statement) == "" {
+		erros = append(erros, "statement é obrigatório")
+	}
+	if rec.Year == 0 {
+		erros = append(erros, "year é obrigatório")
+	}
+	if !rec.TopicID.Valid && (strings.TrimSpace(rec.Subject) == "" || strings.TrimSpace(rec.Topic) == "") {
+		erros = append(erros, "informe topic_id ou subject e topic")
+	}
+	if rec.ReviewStatus != "" && !isReviewStatus(rec.ReviewStatus) {
+		erros = append(erros, fmt.Sprintf("review_status inválido: %s", rec.ReviewStatus))
+	}
+	if utf8.RuneCountInString(rec.ExternalID.String) > maxExternalIDLength {
+		erros = append(erros, fmt.Sprintf("external_id deve ter no máximo %d caracteres", maxExternalIDLength))
+	}
+	if rec.ChoiceCount.Valid {
+		if err := validateChoiceCount(rec.ChoiceCount.Int32); err != nil {
+			erros = append(erros, err.Error())
+		}
+	}
+	refs := make([]LegalReference, 0, len(rec.LegalReferences))
+	for i, ref := range rec.LegalReferences {
+		ref, err := ref.normalize()
+		if err != nil {
+			erros = append(erros, fmt.Sprintf("legal_references[%d]: %v", i, err))
+		}
+		refs = append(refs, ref)
+	}
+
+	// Questões com o mesmo ID já estão no banco: uma reimportação não as duplica
+	if len(erros) == 0 && rec.ID.Valid {
+		if _, err := im.queries.GetQuestion(ctx, rec.ID); err == nil {
+			report.Ignoradas++
+			report.Duplicatas = append(report.Duplicatas, ImportDuplicate{Linha: line, QuestaoID: rec.ID, Similaridade: 1})
+			return
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			erros = append(erros, fmt.Sprintf("erro ao buscar questão: %v", err))
+		}
+	}
+	if len(erros) == 0 && rec.ExternalID.String != "" {
+		if id, err := im.queries.GetQuestionIDByExternalID(ctx, rec.ExternalID); err == nil {
+			report.Ignoradas++
+			report.Duplicatas = append(report.Duplicatas, ImportDuplicate{Linha: line, QuestaoID: id, Similaridade: 1})
+			return
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			erros = append(erros, fmt.Sprintf("erro ao buscar external_id: %v", err))
+		}
+	}
+
+	// O topic_id só vale se existir neste banco; vindo de outro ambiente, o
+	// tópico é encontrado pelos nomes
+	topicID := pgtype.UUID{}
+	if len(erros) == 0 && rec.TopicID.Valid {
+		_, err := im.queries.GetTopic(ctx, rec.TopicID)
+		switch {
+		case err == nil:
+			topicID = rec.TopicID
+		case !errors.Is(err, pgx.ErrNoRows):
+			erros = append(erros, fmt.Sprintf("erro ao buscar tópico: %v", err))
+		}
+	}
+	if len(erros) == 0 && !topicID.Valid {
+		var err error
+		topicID, err = im.resolver.Resolve(ctx, im.beginner(), rec.Subject, rec.Topic)
+		if err != nil {
+			erros = append(erros, err.Error())
+		}
+	}
+
+	// Sem KeepReviewStatus a questão importada entra na fila de revisão
+	reviewStatus := ""
+	if im.opts.KeepReviewStatus {
+		reviewStatus = rec.ReviewStatus
+	}
+
+	if len(erros) == 0 {
+		choices := make([]ChoiceInput, len(rec.Choices))
+		for i, c := range rec.Choices {
+			choices[i] = ChoiceInput{Text: c.Text, IsCorrect: c.IsCorrect}
+		}
+		if err := im.createQuestion(ctx, "", line, QuestionWithChoicesInput{
+			Question: db.Question{
+				ID:           rec.ID,
+				Statement:    rec.Statement,
+				Year:         rec.Year,
+				TopicID:      topicID,
+				Position:     rec.Position,
+				Level:        rec.Level,
+				Difficulty:   rec.Difficulty,
+				Modality:     rec.Modality,
+				PracticeArea: rec.PracticeArea,
+				FieldOfStudy: rec.FieldOfStudy,
+				Board:        rec.Board,
+				ChoiceCount:  rec.ChoiceCount,
+				Explanation:  rec.Explanation,
+				ExternalID:   pgtype.Text{String: rec.ExternalID.String, Valid: rec.ExternalID.String != ""},
+				ReviewStatus: reviewStatus,
+			},
+			Choices:         choices,
+			LegalReferences: refs,
+		}); err != nil {
+			erros = append(erros, err.Error())
+		}
+	}
+
+	if len(erros) > 0 {
+		report.Falharam++
+		report.Detalhes = append(report.Detalhes, ImportRowError{
+			Linha:   line,
+			Erros:   erros,
+			Valores: []string{string(bytes.TrimSpace(data))},
+		})
+	}
+}
@@ -23,16 +23,21 @@ func NewImportService(pool *pgxpool.Pool) *ImportService {
 	return &ImportService{pool: pool}
 }
 
-// ChoiceInput represents a choice to be created.
+// ChoiceInput represents a choice to be created. ID is only set when an
+// existing choice is being rewritten.
 type ChoiceInput struct {
+	ID        pgtype.UUID
 	Text      string
 	IsCorrect bool
 }
 
-// QuestionWithChoicesInput represents a question with its choices.
+// QuestionWithChoicesInput represents a question with its choices. When
+// importing, Question.ID and Question.ReviewStatus, if set, are kept, and
+// LegalReferences are attached to the new question.
 type QuestionWithChoicesInput struct {
-	Question db.Question
-	Choices  []ChoiceInput
+	Question        db.Question
+	Choices         []ChoiceInput
+	LegalReferences []LegalReference
 }
 
 // ImportResult represents the result of importing a single question.
@@ -46,26 +51,15 @@ type ImportResult struct {
 // ErrQuestionAlreadyExists is returned when a question with the same statement already exists.
 var ErrQuestionAlreadyExists = errors.New("questão já existe no banco de dados")
 
+// ErrQuestionIDInUse is returned when an imported question keeps an ID that
+// another question, possibly one in the trash, already has.
+var ErrQuestionIDInUse = errors.New("id de questão já está em uso")
+
 // CreateQuestionWithChoices creates a question and its choices in a single transaction.
 // If any operation fails, the entire transaction is rolled back.
-// Returns ErrQuestionAlreadyExists if a question with the same statement already exists.
+// Returns ErrQuestionAlreadyExists if a question with the same statement already exists,
+// or a SimilarQuestionsError if a near-duplicate does.
 func (s *ImportService) CreateQuestionWithChoices(ctx context.Context, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
-	// Validate: must have exactly 5 choices for multiple choice
-	if len(input.Choices) != 5 {
-		return db.Question{}, nil, errors.New("questão de múltipla escolha deve ter exatamente 5 alternativas (A-E)")
-	}
-
-	// Validate: exactly one correct answer
-	correctCount := 0
-	for _, c := range input.Choices {
-		if c.IsCorrect {
-			correctCount++
-		}
-	}
-	if correctCount != 1 {
-		return db.Question{}, nil, fmt.Errorf("deve haver exatamente 1 alternativa correta, encontrado: %d", correctCount)
-	}
-
 	// Start transaction
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -73,95 +67,70 @@ func (s *ImportService) CreateQuestionWithChoices(ctx context.Context, input Que
 	}
 	defer tx.Rollback(ctx) // Will be no-op if committed
 
-	// Create queries with transaction
-	qtx := db.New(tx)
-
-	// Check if question already exists by statement
-	exists, err := qtx.QuestionExistsByStatement(ctx, input.Question.Statement)
+	question, choices, err := createQuestionWithChoices(ctx, db.New(tx), input)
 	if err != nil {
-		return db.Question{}, nil, fmt.Errorf("erro ao verificar duplicidade: %w", err)
+		return db.Question{}, nil, err
 	}
-	if exists {
-		return db.Question{}, nil, ErrQuestionAlreadyExists
+
+	// Commit transaction
+	if err := tx.Commit(ctx); err != nil {
+		return db.Question{}, nil, fmt.Errorf("erro ao confirmar transação: %w", err)
 	}
 
-	// Create question
-	question, err := qtx.CreateQuestion(ctx, db.CreateQuestionParams{
-		Statement:    input.Question.Statement,
-		Year:         input.Question.Year,
-		TopicID:      input.Question.TopicID,
-		Position:     input.Question.Position,
-		Level:        input.Question.Level,
-		Difficulty:   input.Question.Difficulty,
-		Modality:     input.Question.Modality,
-		PracticeArea: input.Question.PracticeArea,
-		FieldOfStudy: input.Question.FieldOfStudy,
-	})
+	return question, choices, nil
+}
+
+// CreateQuestionWithChoicesTx creates a question and its choices inside tx,
+// started with BeginTx, so that a whole import can be committed or rolled
+// back at once. The row runs under a savepoint: when it fails, only its own
+// changes are undone and tx remains usable for the following rows.
+func (s *ImportService) CreateQuestionWithChoicesTx(ctx context.Context, tx pgx.Tx, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
+	savepoint, err := tx.Begin(ctx)
 	if err != nil {
-		return db.Question{}, nil, fmt.Errorf("erro ao criar questão: %w", err)
+		return db.Question{}, nil, fmt.Errorf("erro ao criar savepoint: %w", err)
 	}
+	defer savepoint.Rollback(ctx)
 
-	// Create choices
-	choices := make([]db.Choice, 0, len(input.Choices))
-	for i, c := range input.Choices {
-		choice, err := qtx.CreateChoice(ctx, db.CreateChoiceParams{
-			QuestionID: question.ID,
-			ChoiceText: c.Text,
-			IsCorrect:  pgtype.Bool{Bool: c.IsCorrect, Valid: true},
-		})
-		if err != nil {
-			return db.Question{}, nil, fmt.Errorf("erro ao criar alternativa %d: %w", i+1, err)
-		}
-		choices = append(choices, choice)
+	question, choices, err := createQuestionWithChoices(ctx, db.New(savepoint), input)
+	if err != nil {
+		return db.Question{}, nil, err
 	}
 
-	// Commit transaction
-	if err := tx.Commit(ctx); err != nil {
-		return db.Question{}, nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	if err := savepoint.Commit(ctx); err != nil {
+		return db.Question{}, nil, fmt.Errorf("erro ao liberar savepoint: %w", err)
 	}
-
 	return question, choices, nil
 }
 
-// CreateTrueFalseQuestion creates a true/false question with exactly 2 choices.
-func (s *ImportService) CreateTrueFalseQuestion(ctx context.Context, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
-	// Validate: must have exactly 2 choices for true/false
-	if len(input.Choices) != 2 {
-		return db.Question{}, nil, errors.New("questão de verdadeiro/falso deve ter exatamente 2 alternativas")
+func createQuestionWithChoices(ctx context.Context, qtx *db.Queries, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
+	// Validate: the number of choices defined for the question or its banca,
+	// and exactly one correct answer
+	if err := validateQuestionChoices(ctx, qtx, input.Question, input.Choices); err != nil {
+		return db.Question{}, nil, err
 	}
 
-	// Validate: exactly one correct answer
-	correctCount := 0
-	for _, c := range input.Choices {
-		if c.IsCorrect {
-			correctCount++
-		}
+	// Check if question already exists by statement
+	exists, err := qtx.QuestionExistsByStatement(ctx, input.Question.Statement)
+	if err != nil {
+		return db.Question{}, nil, fmt.Errorf("erro ao verificar duplicidade: %w", err)
+	}
+	if exists {
+		return db.Question{}, nil, ErrQuestionAlreadyExists
 	}
-	if correctCount != 1 {
-		return db.Question{}, nil, fmt.Errorf("deve haver exatamente 1 alternativa correta, encontrado: %d", correctCount)
+	if err := checkSimilar(ctx, qtx, input.Question.Statement); err != nil {
+		return db.Question{}, nil, err
 	}
 
-	// Start transaction
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return db.Question{}, nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	// Create question, keeping the ID of questions exported from another bank
+	var question db.Question
+	if input.Question.ID.Valid {
+		question, err = qtx.CreateQuestionWithID(ctx, createQuestionWithIDParams(input.Question))
+		if isUniqueViolation(err) {
+			return db.Question{}, nil, fmt.Errorf("%w: %s; remova o id para criar uma nova questão", ErrQuestionIDInUse, input.Question.ID.String())
+		}
+	} else {
+		question, err = qtx.CreateQuestion(ctx, createQuestionParams(input.Question))
 	}
-	defer tx.Rollback(ctx)
-
-	qtx := db.New(tx)
-
-	// Create question
-	question, err := qtx.CreateQuestion(ctx, db.CreateQuestionParams{
-		Statement:    input.Question.Statement,
-		Year:         input.Question.Year,
-		TopicID:      input.Question.TopicID,
-		Position:     input.Question.Position,
-		Level:        input.Question.Level,
-		Difficulty:   input.Question.Difficulty,
-		Modality:     input.Question.Modality,
-		PracticeArea: input.Question.PracticeArea,
-		FieldOfStudy: input.Question.FieldOfStudy,
-	})
 	if err != nil {
 		return db.Question{}, nil, fmt.Errorf("erro ao criar questão: %w", err)
 	}
@@ -180,10 +149,26 @@ func (s *ImportService) CreateTrueFalseQuestion(ctx context.Context, input Quest
 		choices = append(choices, choice)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return db.Question{}, nil, fmt.Errorf("erro ao confirmar transação: %w", err)
+	if status := input.Question.ReviewStatus; status != "" && status != question.ReviewStatus {
+		question, err = qtx.SetQuestionReviewStatus(ctx, db.SetQuestionReviewStatusParams{ID: question.ID, ReviewStatus: status})
+		if err != nil {
+			return db.Question{}, nil, fmt.Errorf("erro ao definir status de revisão: %w", err)
+		}
+	}
+	for _, ref := range input.LegalReferences {
+		if _, err := qtx.CreateLegalReference(ctx, db.CreateLegalReferenceParams{
+			QuestionID: question.ID,
+			Law:        ref.Law,
+			Article:    pgtype.Text{String: ref.Article, Valid: ref.Article != ""},
+			Paragraph:  pgtype.Text{String: ref.Paragraph, Valid: ref.Paragraph != ""},
+		}); err != nil {
+			return db.Question{}, nil, fmt.Errorf("erro ao criar referência legal: %w", err)
+		}
 	}
 
+	if _, err := recordRevision(ctx, qtx, question.ID, RevisionReasonImport); err != nil {
+		return db.Question{}, nil, err
+	}
 	return question, choices, nil
 }
 
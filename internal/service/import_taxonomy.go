@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ErrUnknownTopic is returned when an imported row names a subject or topic
+// that does not exist and missing ones are not being created, or when a
+// question is saved under a topic that does not exist or is in the trash.
+var ErrUnknownTopic = errors.New("matéria ou tópico não encontrado")
+
+// TopicResolver resolves the subject and topic names of imported rows to a
+// topic. Names are matched ignoring case, accents and punctuation, so
+// "Direito Constitucional" and "direito constitucional" are the same
+// subject. When createMissing is set, unknown subjects and topics are
+// created with the spelling of the first row that names them.
+type TopicResolver struct {
+	createMissing bool
+	subjects      map[string]db.Subject
+	topics        map[topicRef]db.Topic
+
+	// CreatedSubjects and CreatedTopics list what the resolver created.
+	CreatedSubjects []db.Subject
+	CreatedTopics   []db.Topic
+}
+
+type topicRef struct {
+	subjectID pgtype.UUID
+	key       string
+}
+
+// NewTopicResolver loads the current subjects and topics for an import.
+func (s *ImportService) NewTopicResolver(ctx context.Context, createMissing bool) (*TopicResolver, error) {
+	q := db.New(s.pool)
+	subjects, err := q.ListSubjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar matérias: %w", err)
+	}
+	topics, err := q.ListTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar tópicos: %w", err)
+	}
+
+	r := &TopicResolver{
+		createMissing: createMissing,
+		subjects:      make(map[string]db.Subject, len(subjects)),
+		topics:        make(map[topicRef]db.Topic, len(topics)),
+	}
+	for _, subject := range subjects {
+		if _, ok := r.subjects[nameKey(subject.Name)]; !ok {
+			r.subjects[nameKey(subject.Name)] = subject
+		}
+	}
+	for _, topic := range topics {
+		ref := topicRef{subjectID: topic.SubjectID, key: nameKey(topic.Name)}
+		if _, ok := r.topics[ref]; !ok {
+			r.topics[ref] = topic
+		}
+	}
+	return r, nil
+}
+
+// maxTaxonomyNameLength is the length of the name columns of subjects and
+// topics.
+const maxTaxonomyNameLength = 100
+
+// Resolve returns the ID of the named topic within the named subject,
+// creating them through tx when missing and allowed. Each create runs under
+// a savepoint, so a failed one leaves a shared import transaction usable
+// for the rows that follow.
+func (r *TopicResolver) Resolve(ctx context.Context, tx beginner, subjectName, topicName string) (pgtype.UUID, error) {
+	subjectName, topicName = strings.TrimSpace(subjectName), strings.TrimSpace(topicName)
+	if nameKey(subjectName) == "" || nameKey(topicName) == "" {
+		return pgtype.UUID{}, fmt.Errorf("%w: subject e topic são obrigatórios", ErrUnknownTopic)
+	}
+	if utf8.RuneCountInString(subjectName) > maxTaxonomyNameLength {
+		return pgtype.UUID{}, fmt.Errorf("nome da matéria excede %d caracteres", maxTaxonomyNameLength)
+	}
+	if utf8.RuneCountInString(topicName) > maxTaxonomyNameLength {
+		return pgtype.UUID{}, fmt.Errorf("nome do tópico excede %d caracteres", maxTaxonomyNameLength)
+	}
+
+	subject, ok := r.subjects[nameKey(subjectName)]
+	if !ok {
+		if !r.createMissing {
+			return pgtype.UUID{}, fmt.Errorf("%w: matéria %q", ErrUnknownTopic, subjectName)
+		}
+		var created db.Subject
+		err := inTx(ctx, tx, func(qtx *db.Queries) error {
+			var err error
+			created, err = qtx.CreateSubject(ctx, subjectName)
+			return err
+		})
+		if err != nil {
+			return pgtype.UUID{}, fmt.Errorf("erro ao criar matéria %q: %w", subjectName, err)
+		}
+		subject = created
+		r.subjects[nameKey(subjectName)] = created
+		r.CreatedSubjects = append(r.CreatedSubjects, created)
+	}
+
+	ref := topicRef{subjectID: subject.ID, key: nameKey(topicName)}
+	topic, ok := r.topics[ref]
+	if !ok {
+		if !r.createMissing {
+			return pgtype.UUID{}, fmt.Errorf("%w: tópico %q em %s", ErrUnknownTopic, topicName, subject.Name)
+		}
+		var created db.Topic
+		err := inTx(ctx, tx, func(qtx *db.Queries) error {
+			var err error
+			created, err = qtx.CreateTopic(ctx, db.CreateTopicParams{SubjectID: subject.ID, Name: topicName})
+			return err
+		})
+		if err != nil {
+			return pgtype.UUID{}, fmt.Errorf("erro ao criar tópico %q: %w", topicName, err)
+		}
+		topic = created
+		r.topics[ref] = created
+		r.CreatedTopics = append(r.CreatedTopics, created)
+	}
+	return topic.ID, nil
+}
+
+// nameKey compares subject and topic names ignoring case, accents and
+// punctuation.
+func nameKey(name string) string {
+	return NormalizeStatement(name)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Direito Constitucional", "direito constitucional", true},
+		{"Língua Portuguesa", "lingua portuguesa", true},
+		{"Direitos  fundamentais.", "Direitos fundamentais", true},
+		{"Controle de constitucionalidade", "Controle-de-constitucionalidade", true},
+		{"Direito Civil", "Direito Penal", false},
+		{"Crase", "Crases", false},
+	}
+	for _, tt := range tests {
+		if got := nameKey(tt.a) == nameKey(tt.b); got != tt.same {
+			t.Errorf("nameKey(%q) == nameKey(%q) is %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestTopicResolverResolve(t *testing.T) {
+	subject := db.Subject{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Name: "Direito Constitucional"}
+	topic := db.Topic{ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, SubjectID: subject.ID, Name: "Direitos fundamentais"}
+	newResolver := func(createMissing bool) *TopicResolver {
+		return &TopicResolver{
+			createMissing: createMissing,
+			subjects:      map[string]db.Subject{nameKey(subject.Name): subject},
+			topics:        map[topicRef]db.Topic{{subjectID: subject.ID, key: nameKey(topic.Name)}: topic},
+		}
+	}
+	long := strings.Repeat("á", maxTaxonomyNameLength+1)
+
+	tests := []struct {
+		name          string
+		createMissing bool
+		subject       string
+		topic         string
+		wantErr       string
+	}{
+		{name: "known names", subject: "direito constitucional", topic: " DIREITOS FUNDAMENTAIS "},
+		{name: "missing names", subject: "", topic: "Direitos fundamentais", wantErr: "subject e topic são obrigatórios"},
+		{name: "unknown subject", subject: "Direito Penal", topic: "Crimes", wantErr: `matéria "Direito Penal"`},
+		{name: "unknown topic", subject: "Direito Constitucional", topic: "Controle", wantErr: `tópico "Controle"`},
+		{name: "subject too long", createMissing: true, subject: long, topic: "Crimes", wantErr: "nome da matéria excede"},
+		{name: "topic too long", createMissing: true, subject: "Direito Constitucional", topic: long, wantErr: "nome do tópico excede"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Sem beginner: nenhum caso pode chegar a criar matéria ou tópico
+			r := newResolver(tt.createMissing)
+			id, err := r.Resolve(context.Background(), nil, tt.subject, tt.topic)
+			if tt.wantErr == "" {
+				if err != nil || id != topic.ID {
+					t.Fatalf("Resolve() = (%v, %v), want (%v, nil)", id, err, topic.ID)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Resolve() err = %v, want %q", err, tt.wantErr)
+			}
+			if len(r.CreatedSubjects) > 0 || len(r.CreatedTopics) > 0 {
+				t.Errorf("Resolve() created %v and %v", r.CreatedSubjects, r.CreatedTopics)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ImportXLSX creates questions from an XLSX spreadsheet. Every sheet whose
+// first row has the same columns as the CSV format is imported, in order;
+// other sheets, such as instructions, are listed as ignored. A sheet
+// without a subject column holds the questions of the subject named after
+// it, so a spreadsheet may have one sheet per subject with only the topic
+// column. Failing rows are reported by sheet and row, with the cell of
+// each problem where it can be pinned down.
+func (s *ImportService) ImportXLSX(ctx context.Context, r io.Reader, opts ImportOptions) (ImportReport, error) {
+	return s.importXLSX(ctx, r, opts, ImportReport{}, nil)
+}
+
+func (s *ImportService) importXLSX(ctx context.Context, r io.Reader, opts ImportOptions, report ImportReport, progress func(db.Querier, ImportReport) error) (ImportReport, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return report, fmt.Errorf("%w: planilha ilegível: %v", ErrInvalidImport, err)
+	}
+	defer f.Close()
+
+	var tables []*importTable
+	for _, sheet := range f.GetSheetList() {
+		// Valores brutos, sem a formatação de exibição (ex.: 2.024 em vez de 2024)
+		rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return report, fmt.Errorf("%w: aba %s: %v", ErrInvalidImport, sheet, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		layout, isHeader, err := parseCSVHeader(rows[0], true)
+		if !isHeader {
+			if !slices.Contains(report.AbasIgnoradas, sheet) {
+				report.AbasIgnoradas = append(report.AbasIgnoradas, sheet)
+			}
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("%w: aba %s: %v", ErrInvalidImport, sheet, err)
+		}
+
+		table := &importTable{
+			sheet:  sheet,
+			layout: layout,
+			rows:   &sheetRows{rows: rows[1:], width: len(layout.headers)},
+			line:   1,
+		}
+		if _, ok := layout.columns["subject"]; !ok {
+			table.subject = sheet
+		}
+		tables = append(tables, table)
+	}
+	if len(tables) == 0 {
+		return report, fmt.Errorf("%w: nenhuma aba com o cabeçalho de questões", ErrInvalidImport)
+	}
+	return s.importTables(ctx, tables, opts, report, progress)
+}
+
+// sheetRows reads the rows of a sheet. The spreadsheet omits empty cells at
+// the end of a row, so rows are padded to the width of the header.
+type sheetRows struct {
+	rows  [][]string
+	width int
+}
+
+func (s *sheetRows) Read() ([]string, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	if len(row) < s.width {
+		row = append(row, make([]string, s.width-len(row))...)
+	}
+	return row, nil
+}
+
+// cellName returns the spreadsheet reference of a cell, as in "C5", from
+// its zero-based column and its row number.
+func cellName(column, line int) string {
+	name, err := excelize.CoordinatesToCellName(column+1, line)
+	if err != nil {
+		return fmt.Sprintf("linha %d", line)
+	}
+	return name
+}
@@ -0,0 +1,310 @@
+package service
+
+import "math"
+
+// IRT models. Both use the logistic metric (no 1.7 scaling constant).
+const (
+	IRTModel2PL = "2PL"
+	IRTModel3PL = "3PL"
+)
+
+// ItemParams are the parameters of an item: discrimination A, difficulty B
+// and pseudo-guessing C, which is zero in the 2PL model.
+type ItemParams struct {
+	A float64 `json:"discrimination"`
+	B float64 `json:"difficulty"`
+	C float64 `json:"guessing"`
+}
+
+// Bounds that keep the estimates finite on small or degenerate samples.
+const (
+	irtMinA, irtMaxA = 0.05, 4.0
+	irtMinB, irtMaxB = -5.0, 5.0
+	irtMinC, irtMaxC = 0.0, 0.5
+)
+
+// Priors regularising the item estimates: log-normal on A, normal on B and
+// a Beta on C centred on the chance of guessing among the item's choices.
+const (
+	irtPriorLogASD  = 0.5
+	irtPriorBSD     = 2.0
+	irtPriorCWeight = 20.0
+)
+
+// Convergence settings of the EM cycles and of the Fisher scoring inside
+// each M-step.
+const (
+	irtMaxEMCycles      = 200
+	irtEMTolerance      = 1e-4
+	irtNewtonIterations = 10
+)
+
+// prob returns the probability of a correct answer at ability theta.
+func (p ItemParams) prob(theta float64) float64 {
+	return p.C + (1-p.C)/(1+math.Exp(-p.A*(theta-p.B)))
+}
+
+// information returns the Fisher information of the item at theta.
+func (p ItemParams) information(theta float64) float64 {
+	pr := p.prob(theta)
+	if pr <= 0 || pr >= 1 {
+		return 0
+	}
+	num := (pr - p.C) / (1 - p.C)
+	return p.A * p.A * num * num * (1 - pr) / pr
+}
+
+// quadrature is a fixed grid of ability points with standard normal
+// weights, used to integrate over the ability distribution.
+type quadrature struct {
+	points  []float64
+	weights []float64
+}
+
+func newQuadrature() quadrature {
+	const n = 41
+	q := quadrature{points: make([]float64, n), weights: make([]float64, n)}
+	total := 0.0
+	for k := range n {
+		x := -4 + 8*float64(k)/float64(n-1)
+		q.points[k] = x
+		q.weights[k] = math.Exp(-x * x / 2)
+		total += q.weights[k]
+	}
+	for k := range q.weights {
+		q.weights[k] /= total
+	}
+	return q
+}
+
+// irtResponse is one scored answer of a person to an item.
+type irtResponse struct {
+	item    int
+	correct bool
+}
+
+// calibrationResult holds the estimates of a calibration run.
+type calibrationResult struct {
+	items     []ItemParams
+	cycles    int
+	converged bool
+}
+
+// calibrate estimates item parameters by marginal maximum likelihood with
+// the EM algorithm of Bock and Aitkin. persons holds the responses of each
+// person; choices holds, per item, the number of choices, used to centre
+// the guessing prior in the 3PL model.
+func calibrate(model string, persons [][]irtResponse, choices []int) calibrationResult {
+	q := newQuadrature()
+	nItems := len(choices)
+	items := make([]ItemParams, nItems)
+	for i := range items {
+		items[i] = ItemParams{A: 1}
+		if model == IRTModel3PL {
+			items[i].C = 1 / float64(max(choices[i], 2))
+		}
+	}
+
+	nq := len(q.points)
+	// Expected number of persons (n) and of correct answers (r) per item
+	// at each quadrature point.
+	n := make([][]float64, nItems)
+	r := make([][]float64, nItems)
+	for i := range n {
+		n[i] = make([]float64, nq)
+		r[i] = make([]float64, nq)
+	}
+	post := make([]float64, nq)
+
+	result := calibrationResult{}
+	for cycle := 1; cycle <= irtMaxEMCycles; cycle++ {
+		result.cycles = cycle
+		for i := range n {
+			clear(n[i])
+			clear(r[i])
+		}
+
+		// E-step: posterior ability distribution of each person.
+		for _, responses := range persons {
+			total := 0.0
+			for k, theta := range q.points {
+				l := q.weights[k]
+				for _, resp := range responses {
+					p := items[resp.item].prob(theta)
+					if resp.correct {
+						l *= p
+					} else {
+						l *= 1 - p
+					}
+				}
+				post[k] = l
+				total += l
+			}
+			if total == 0 {
+				continue
+			}
+			for _, resp := range responses {
+				for k := range post {
+					w := post[k] / total
+					n[resp.item][k] += w
+					if resp.correct {
+						r[resp.item][k] += w
+					}
+				}
+			}
+		}
+
+		// M-step: each item separately.
+		change := 0.0
+		for i := range items {
+			prev := items[i]
+			items[i] = maximiseItem(model, prev, q.points, n[i], r[i], choices[i])
+			change = max(change, math.Abs(items[i].A-prev.A), math.Abs(items[i].B-prev.B), math.Abs(items[i].C-prev.C))
+		}
+		if change < irtEMTolerance {
+			result.converged = true
+			break
+		}
+	}
+	result.items = items
+	return result
+}
+
+// maximiseItem runs Fisher scoring on the expected complete-data
+// log-likelihood of one item plus its log-prior.
+func maximiseItem(model string, p ItemParams, points, n, r []float64, choices int) ItemParams {
+	estimateC := model == IRTModel3PL
+	mean := 1 / float64(max(choices, 2))
+	alpha := irtPriorCWeight*mean + 1
+	beta := irtPriorCWeight*(1-mean) + 1
+
+	for range irtNewtonIterations {
+		var g [3]float64
+		var info [3][3]float64
+		for k, theta := range points {
+			if n[k] == 0 {
+				continue
+			}
+			pr := p.prob(theta)
+			pr = min(max(pr, 1e-9), 1-1e-9)
+			s := (pr - p.C) / (1 - p.C)
+			d := [3]float64{
+				(1 - p.C) * s * (1 - s) * (theta - p.B),
+				-(1 - p.C) * s * (1 - s) * p.A,
+				1 - s,
+			}
+			resid := (r[k] - n[k]*pr) / (pr * (1 - pr))
+			weight := n[k] / (pr * (1 - pr))
+			for a := range 3 {
+				g[a] += resid * d[a]
+				for b := range 3 {
+					info[a][b] += weight * d[a] * d[b]
+				}
+			}
+		}
+
+		// Priors.
+		logA := math.Log(p.A)
+		g[0] += -logA/(irtPriorLogASD*irtPriorLogASD*p.A) - 1/p.A
+		info[0][0] += 1 / (irtPriorLogASD * irtPriorLogASD * p.A * p.A)
+		g[1] += -p.B / (irtPriorBSD * irtPriorBSD)
+		info[1][1] += 1 / (irtPriorBSD * irtPriorBSD)
+		if estimateC {
+			c := min(max(p.C, 1e-6), 1-1e-6)
+			g[2] += (alpha-1)/c - (beta-1)/(1-c)
+			info[2][2] += (alpha-1)/(c*c) + (beta-1)/((1-c)*(1-c))
+		}
+
+		size := 2
+		if estimateC {
+			size = 3
+		}
+		step, ok := solve(info, g, size)
+		if !ok {
+			break
+		}
+		next := ItemParams{
+			A: clamp(p.A+step[0], irtMinA, irtMaxA),
+			B: clamp(p.B+step[1], irtMinB, irtMaxB),
+			C: p.C,
+		}
+		if estimateC {
+			next.C = clamp(p.C+step[2], irtMinC, irtMaxC)
+		}
+		done := math.Abs(next.A-p.A) < 1e-6 && math.Abs(next.B-p.B) < 1e-6 && math.Abs(next.C-p.C) < 1e-6
+		p = next
+		if done {
+			break
+		}
+	}
+	return p
+}
+
+// solve returns x with m·x = v for the leading size×size block, using
+// Gaussian elimination with partial pivoting.
+func solve(m [3][3]float64, v [3]float64, size int) ([3]float64, bool) {
+	var x [3]float64
+	for col := range size {
+		pivot := col
+		for row := col + 1; row < size; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return x, false
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		v[col], v[pivot] = v[pivot], v[col]
+		for row := col + 1; row < size; row++ {
+			f := m[row][col] / m[col][col]
+			for k := col; k < size; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+			v[row] -= f * v[col]
+		}
+	}
+	for row := size - 1; row >= 0; row-- {
+		sum := v[row]
+		for k := row + 1; k < size; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x, true
+}
+
+// estimateTheta returns the expected a posteriori ability estimate of a
+// person and its standard error, under a standard normal prior.
+func estimateTheta(items []ItemParams, correct []bool) (theta, se float64) {
+	q := newQuadrature()
+	post := make([]float64, len(q.points))
+	total := 0.0
+	for k, t := range q.points {
+		l := q.weights[k]
+		for i, item := range items {
+			p := item.prob(t)
+			if correct[i] {
+				l *= p
+			} else {
+				l *= 1 - p
+			}
+		}
+		post[k] = l
+		total += l
+	}
+	if total == 0 {
+		return 0, 1
+	}
+	for k, t := range q.points {
+		theta += t * post[k] / total
+	}
+	for k, t := range q.points {
+		se += (t - theta) * (t - theta) * post[k] / total
+	}
+	return theta, math.Sqrt(se)
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return min(max(x, lo), hi)
+}
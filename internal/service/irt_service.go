@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+const (
+	// DefaultMinCalibrationResponses is how many answers a question needs
+	// to be calibrated when no other minimum is given.
+	DefaultMinCalibrationResponses = 30
+	// DefaultCATMaxItems and DefaultCATTargetSE are the stopping rules of
+	// an adaptive test when the session does not set its own.
+	DefaultCATMaxItems = 20
+	DefaultCATTargetSE = 0.3
+)
+
+// Statuses of an adaptive test session.
+const (
+	CATStatusActive   = "active"
+	CATStatusFinished = "finished"
+)
+
+var (
+	// ErrInvalidCalibration is returned for an unknown model or when no
+	// question has enough answers to be calibrated.
+	ErrInvalidCalibration = errors.New("calibração inválida")
+	// ErrInvalidCAT is returned when an adaptive test cannot start or an
+	// answer does not fit the session.
+	ErrInvalidCAT = errors.New("teste adaptativo inválido")
+)
+
+// CalibrationSummary reports a calibration run.
+type CalibrationSummary struct {
+	Model      string             `json:"model"`
+	Persons    int                `json:"persons"`
+	Items      int                `json:"items"`
+	Skipped    int                `json:"skipped"`
+	Cycles     int                `json:"cycles"`
+	Converged  bool               `json:"converged"`
+	Parameters []db.ItemParameter `json:"parameters"`
+}
+
+// CATChoice is a choice as shown to the candidate, without the answer.
+type CATChoice struct {
+	ID         pgtype.UUID `json:"id"`
+	Letter     string      `json:"letter"`
+	ChoiceText string      `json:"choice_text"`
+}
+
+// CATQuestion is the question a candidate must answer next.
+type CATQuestion struct {
+	Number     int32       `json:"number"`
+	QuestionID pgtype.UUID `json:"question_id"`
+	Statement  string      `json:"statement"`
+	Choices    []CATChoice `json:"choices"`
+}
+
+// CATState is an adaptive test session with its answers so far and, while
+// it is active, the next question. Once finished, Session.Theta is the
+// final ability score and Session.Se its standard error.
+type CATState struct {
+	Session   db.CatSession            `json:"session"`
+	Responses []db.ListCATResponsesRow `json:"responses"`
+	Next      *CATQuestion             `json:"next,omitempty"`
+}
+
+// CATInput configures a new adaptive test. A zero MaxItems or TargetSE
+// uses the defaults; an invalid SubjectID draws from every subject.
+type CATInput struct {
+	Candidate string
+	SubjectID pgtype.UUID
+	MaxItems  int32
+	TargetSE  float64
+}
+
+// IRTService calibrates Item Response Theory parameters from the recorded
+// exam answers and runs computerized adaptive tests on top of them.
+type IRTService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewIRTService creates a new IRTService.
+func NewIRTService(pool *pgxpool.Pool) *IRTService {
+	return &IRTService{pool: pool, q: db.New(pool)}
+}
+
+// Calibrate estimates the 2PL or 3PL parameters of every active question
+// with at least minResponses recorded answers and stores them, replacing
+// previous estimates. Blank answers count as wrong.
+func (s *IRTService) Calibrate(ctx context.Context, model string, minResponses int) (CalibrationSummary, error) {
+	if model != IRTModel2PL && model != IRTModel3PL {
+		return CalibrationSummary{}, fmt.Errorf("%w: modelo deve ser %s ou %s", ErrInvalidCalibration, IRTModel2PL, IRTModel3PL)
+	}
+	if minResponses <= 0 {
+		minResponses = DefaultMinCalibrationResponses
+	}
+
+	rows, err := s.q.ListCalibrationResponses(ctx)
+	if err != nil {
+		return CalibrationSummary{}, fmt.Errorf("erro ao buscar respostas: %w", err)
+	}
+	counts := make(map[pgtype.UUID]int)
+	for _, row := range rows {
+		counts[row.QuestionID]++
+	}
+	summary := CalibrationSummary{Model: model, Parameters: []db.ItemParameter{}}
+	index := make(map[pgtype.UUID]int)
+	var ids []pgtype.UUID
+	for _, row := range rows {
+		if _, seen := index[row.QuestionID]; seen || counts[row.QuestionID] < minResponses {
+			continue
+		}
+		index[row.QuestionID] = len(ids)
+		ids = append(ids, row.QuestionID)
+	}
+	summary.Skipped = len(counts) - len(ids)
+	if len(ids) == 0 {
+		return summary, fmt.Errorf("%w: nenhuma questão tem ao menos %d respostas", ErrInvalidCalibration, minResponses)
+	}
+
+	var persons [][]irtResponse
+	for i := 0; i < len(rows); {
+		attempt := rows[i].AttemptID
+		var responses []irtResponse
+		for ; i < len(rows) && rows[i].AttemptID == attempt; i++ {
+			if item, ok := index[rows[i].QuestionID]; ok {
+				responses = append(responses, irtResponse{item: item, correct: rows[i].IsCorrect})
+			}
+		}
+		if len(responses) > 0 {
+			persons = append(persons, responses)
+		}
+	}
+	choices := make([]int, len(ids))
+	for i, id := range ids {
+		qi, err := s.q.GetQuestionIntegrity(ctx, id)
+		if err != nil {
+			return summary, fmt.Errorf("erro ao buscar alternativas: %w", err)
+		}
+		choices[i] = int(qi.ExpectedChoices)
+	}
+
+	result := calibrate(model, persons, choices)
+	summary.Persons = len(persons)
+	summary.Items = len(ids)
+	summary.Cycles = result.cycles
+	summary.Converged = result.converged
+
+	err = inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		for i, id := range ids {
+			p := result.items[i]
+			param, err := qtx.UpsertItemParameters(ctx, db.UpsertItemParametersParams{
+				QuestionID:     id,
+				Model:          model,
+				Discrimination: p.A,
+				Difficulty:     p.B,
+				Guessing:       p.C,
+				Responses:      int32(counts[id]),
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao salvar parâmetros: %w", err)
+			}
+			summary.Parameters = append(summary.Parameters, param)
+		}
+		return nil
+	})
+	if err != nil {
+		return CalibrationSummary{}, err
+	}
+	return summary, nil
+}
+
+func (s *IRTService) ListItemParameters(ctx context.Context) ([]db.ItemParameter, error) {
+	return s.q.ListItemParameters(ctx)
+}
+
+func (s *IRTService) GetItemParameters(ctx context.Context, questionID pgtype.UUID) (db.ItemParameter, error) {
+	return s.q.GetItemParameters(ctx, questionID)
+}
+
+// StartCAT opens an adaptive test and picks its first question, the one
+// with the most information at the average ability.
+func (s *IRTService) StartCAT(ctx context.Context, input CATInput) (CATState, error) {
+	input.Candidate = strings.TrimSpace(input.Candidate)
+	if input.Candidate == "" {
+		return CATState{}, fmt.Errorf("%w: candidato é obrigatório", ErrInvalidCAT)
+	}
+	if input.MaxItems == 0 {
+		input.MaxItems = DefaultCATMaxItems
+	}
+	if input.TargetSE == 0 {
+		input.TargetSE = DefaultCATTargetSE
+	}
+	if input.MaxItems < 1 || input.TargetSE < 0 {
+		return CATState{}, fmt.Errorf("%w: max_items deve ser positivo e target_se não negativo", ErrInvalidCAT)
+	}
+
+	var state CATState
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if input.SubjectID.Valid {
+			if _, err := qtx.GetSubject(ctx, input.SubjectID); err != nil {
+				return err
+			}
+		}
+		session, err := qtx.CreateCATSession(ctx, db.CreateCATSessionParams{
+			Candidate: input.Candidate,
+			SubjectID: input.SubjectID,
+			MaxItems:  input.MaxItems,
+			TargetSe:  input.TargetSE,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao criar sessão: %w", err)
+		}
+		next, ok, err := selectCATItem(ctx, qtx, session, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: nenhuma questão calibrada disponível", ErrInvalidCAT)
+		}
+		state.Session = session
+		state.Responses = []db.ListCATResponsesRow{}
+		state.Next, err = administer(ctx, qtx, session.ID, 1, next)
+		return err
+	})
+	if err != nil {
+		return CATState{}, err
+	}
+	return state, nil
+}
+
+// AnswerCAT scores the answer to the pending question, updates the ability
+// estimate and either picks the question with the most information at the
+// new estimate or finishes the test. The test finishes when it reaches its
+// maximum length, when the standard error drops to the target, or when no
+// question is left. An invalid choiceID records a blank, wrong answer.
+func (s *IRTService) AnswerCAT(ctx context.Context, sessionID, choiceID pgtype.UUID) (CATState, error) {
+	var state CATState
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		session, err := qtx.GetCATSessionForUpdate(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if session.Status != CATStatusActive {
+			return fmt.Errorf("%w: a sessão já foi encerrada", ErrInvalidCAT)
+		}
+		responses, err := qtx.ListCATResponses(ctx, sessionID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar respostas: %w", err)
+		}
+		if len(responses) == 0 || responses[len(responses)-1].IsCorrect.Valid {
+			return fmt.Errorf("%w: nenhuma questão aguardando resposta", ErrInvalidCAT)
+		}
+		pending := &responses[len(responses)-1]
+
+		correct := false
+		if choiceID.Valid {
+			choices, err := qtx.ListChoicesByQuestion(ctx, pending.QuestionID)
+			if err != nil {
+				return fmt.Errorf("erro ao buscar alternativas: %w", err)
+			}
+			found := false
+			for _, c := range choices {
+				if c.ID == choiceID {
+					found, correct = true, c.IsCorrect.Bool
+				}
+			}
+			if !found {
+				return fmt.Errorf("%w: a alternativa não pertence à questão %d", ErrInvalidCAT, pending.Number)
+			}
+		}
+		pending.ChoiceID = choiceID
+		pending.IsCorrect = pgtype.Bool{Bool: correct, Valid: true}
+
+		items := make([]ItemParams, len(responses))
+		scores := make([]bool, len(responses))
+		administered := make(map[pgtype.UUID]bool, len(responses))
+		for i, r := range responses {
+			items[i] = ItemParams{A: r.Discrimination, B: r.Difficulty, C: r.Guessing}
+			scores[i] = r.IsCorrect.Bool
+			administered[r.QuestionID] = true
+		}
+		theta, se := estimateTheta(items, scores)
+		pending.Theta = pgtype.Float8{Float64: theta, Valid: true}
+		pending.Se = pgtype.Float8{Float64: se, Valid: true}
+		if err := qtx.AnswerCATResponse(ctx, db.AnswerCATResponseParams{
+			SessionID: sessionID,
+			Number:    pending.Number,
+			ChoiceID:  choiceID,
+			IsCorrect: pending.IsCorrect,
+			Theta:     pending.Theta,
+			Se:        pending.Se,
+		}); err != nil {
+			return fmt.Errorf("erro ao registrar resposta: %w", err)
+		}
+
+		session.Theta, session.Se = theta, se
+		var next db.ListCATItemsRow
+		more := len(responses) < int(session.MaxItems) && se > session.TargetSe
+		if more {
+			next, more, err = selectCATItem(ctx, qtx, session, administered)
+			if err != nil {
+				return err
+			}
+		}
+		status, finishedAt := CATStatusActive, pgtype.Timestamptz{}
+		if !more {
+			status, finishedAt = CATStatusFinished, pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+		state.Session, err = qtx.UpdateCATSession(ctx, db.UpdateCATSessionParams{
+			ID:         sessionID,
+			Status:     status,
+			Theta:      theta,
+			Se:         se,
+			FinishedAt: finishedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar sessão: %w", err)
+		}
+		state.Responses = responses
+		if more {
+			state.Next, err = administer(ctx, qtx, sessionID, int32(len(responses)+1), next)
+		}
+		return err
+	})
+	if err != nil {
+		return CATState{}, err
+	}
+	return state, nil
+}
+
+// GetCAT returns an adaptive test session with its answers and, while it
+// is active, the pending question.
+func (s *IRTService) GetCAT(ctx context.Context, sessionID pgtype.UUID) (CATState, error) {
+	session, err := s.q.GetCATSession(ctx, sessionID)
+	if err != nil {
+		return CATState{}, err
+	}
+	responses, err := s.q.ListCATResponses(ctx, sessionID)
+	if err != nil {
+		return CATState{}, err
+	}
+	state := CATState{Session: session, Responses: responses}
+	if n := len(responses); n > 0 && !responses[n-1].IsCorrect.Valid {
+		pending := responses[n-1]
+		state.Responses = responses[:n-1]
+		state.Next, err = catQuestion(ctx, s.q, pending.Number, pending.QuestionID)
+		if err != nil {
+			return CATState{}, err
+		}
+	}
+	return state, nil
+}
+
+// selectCATItem returns the calibrated question with the most information
+// at the session's current ability estimate among those not administered.
+func selectCATItem(ctx context.Context, q db.Querier, session db.CatSession, administered map[pgtype.UUID]bool) (db.ListCATItemsRow, bool, error) {
+	items, err := q.ListCATItems(ctx, session.SubjectID)
+	if err != nil {
+		return db.ListCATItemsRow{}, false, fmt.Errorf("erro ao buscar questões calibradas: %w", err)
+	}
+	var (
+		best     db.ListCATItemsRow
+		bestInfo = -1.0
+	)
+	for _, item := range items {
+		if administered[item.QuestionID] {
+			continue
+		}
+		info := ItemParams{A: item.Discrimination, B: item.Difficulty, C: item.Guessing}.information(session.Theta)
+		if info > bestInfo {
+			best, bestInfo = item, info
+		}
+	}
+	return best, bestInfo >= 0, nil
+}
+
+// administer records the question as pending in the session and returns
+// it as shown to the candidate.
+func administer(ctx context.Context, qtx *db.Queries, sessionID pgtype.UUID, number int32, item db.ListCATItemsRow) (*CATQuestion, error) {
+	if err := qtx.CreateCATResponse(ctx, db.CreateCATResponseParams{SessionID: sessionID, Number: number, QuestionID: item.QuestionID}); err != nil {
+		return nil, fmt.Errorf("erro ao registrar questão aplicada: %w", err)
+	}
+	return catQuestion(ctx, qtx, number, item.QuestionID)
+}
+
+func catQuestion(ctx context.Context, q db.Querier, number int32, questionID pgtype.UUID) (*CATQuestion, error) {
+	question, err := q.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar questão: %w", err)
+	}
+	choices, err := q.ListChoicesByQuestion(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alternativas: %w", err)
+	}
+	out := &CATQuestion{Number: number, QuestionID: questionID, Statement: question.Statement, Choices: make([]CATChoice, 0, len(choices))}
+	for i, c := range choices {
+		out.Choices = append(out.Choices, CATChoice{ID: c.ID, Letter: string(rune('A' + i)), ChoiceText: c.ChoiceText})
+	}
+	return out, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestItemParamsProb(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     ItemParams
+		theta float64
+		want  float64
+	}{
+		{"2PL at difficulty", ItemParams{A: 1.5, B: 0.5}, 0.5, 0.5},
+		{"3PL at difficulty", ItemParams{A: 1, B: -1, C: 0.2}, -1, 0.6},
+		{"one logit above", ItemParams{A: 1}, 1, 1 / (1 + math.Exp(-1))},
+		{"far below tends to guessing", ItemParams{A: 2, C: 0.25}, -20, 0.25},
+		{"far above tends to one", ItemParams{A: 2, C: 0.25}, 20, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.prob(tt.theta); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("prob(%v) = %v, want %v", tt.theta, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemParamsInformation(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     ItemParams
+		theta float64
+		want  float64
+	}{
+		// a²·p·(1−p) at the difficulty of a 2PL item
+		{"2PL at difficulty", ItemParams{A: 2, B: 1}, 1, 1},
+		{"saturated", ItemParams{A: 4}, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.information(tt.theta); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("information(%v) = %v, want %v", tt.theta, got, tt.want)
+			}
+		})
+	}
+
+	// Guessing lowers the information an item carries.
+	if with, without := (ItemParams{A: 1, C: 0.2}).information(0), (ItemParams{A: 1}).information(0); with >= without {
+		t.Errorf("information with guessing = %v, want below %v", with, without)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      [3][3]float64
+		v      [3]float64
+		size   int
+		want   [3]float64
+		wantOK bool
+	}{
+		{
+			name:   "2x2",
+			m:      [3][3]float64{{2, 1}, {1, 3}},
+			v:      [3]float64{3, 5},
+			size:   2,
+			want:   [3]float64{0.8, 1.4},
+			wantOK: true,
+		},
+		{
+			name:   "3x3 needing a pivot",
+			m:      [3][3]float64{{0, 1, 1}, {2, 0, 1}, {1, 1, 0}},
+			v:      [3]float64{5, 5, 3},
+			size:   3,
+			want:   [3]float64{1, 2, 3},
+			wantOK: true,
+		},
+		{
+			name: "singular",
+			m:    [3][3]float64{{1, 2}, {2, 4}},
+			v:    [3]float64{1, 2},
+			size: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := solve(tt.m, tt.v, tt.size)
+			if ok != tt.wantOK {
+				t.Fatalf("solve() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			for i := range tt.size {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("solve() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestEstimateTheta(t *testing.T) {
+	items := []ItemParams{{A: 1, B: -1}, {A: 1.2, B: 0}, {A: 0.8, B: 1}, {A: 1.5, B: 0.5}}
+	tests := []struct {
+		name    string
+		correct []bool
+		check   func(theta, se float64) bool
+	}{
+		{"no items returns the prior", nil, func(theta, se float64) bool {
+			return math.Abs(theta) < 1e-9 && math.Abs(se-1) < 0.05
+		}},
+		{"all correct is above average", []bool{true, true, true, true}, func(theta, se float64) bool {
+			return theta > 0.5 && se < 1
+		}},
+		{"all wrong is below average", []bool{false, false, false, false}, func(theta, se float64) bool {
+			return theta < -0.5 && se < 1
+		}},
+		{"easy right, hard wrong is in between", []bool{true, true, false, false}, func(theta, se float64) bool {
+			return theta > -0.5 && theta < 0.5
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.correct == nil {
+				theta, se := estimateTheta(nil, nil)
+				if !tt.check(theta, se) {
+					t.Errorf("estimateTheta() = (%v, %v)", theta, se)
+				}
+				return
+			}
+			theta, se := estimateTheta(items, tt.correct)
+			if !tt.check(theta, se) {
+				t.Errorf("estimateTheta() = (%v, %v)", theta, se)
+			}
+		})
+	}
+
+	// Answering more items right never lowers the estimate.
+	prev := math.Inf(-1)
+	for right := range len(items) + 1 {
+		correct := make([]bool, len(items))
+		for i := range right {
+			correct[i] = true
+		}
+		theta, _ := estimateTheta(items, correct)
+		if theta <= prev {
+			t.Errorf("estimateTheta with %d right = %v, want above %v", right, theta, prev)
+		}
+		prev = theta
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	truth := []ItemParams{
+		{A: 0.8, B: -1.5},
+		{A: 1.2, B: -0.5},
+		{A: 1.0, B: 0},
+		{A: 1.6, B: 0.5},
+		{A: 1.0, B: 1.5},
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	persons := make([][]irtResponse, 3000)
+	for i := range persons {
+		theta := rng.NormFloat64()
+		for j, item := range truth {
+			persons[i] = append(persons[i], irtResponse{item: j, correct: rng.Float64() < item.prob(theta)})
+		}
+	}
+	choices := []int{5, 5, 5, 5, 5}
+
+	tests := []struct {
+		name  string
+		model string
+		tolA  float64
+		tolB  float64
+	}{
+		{"2PL", IRTModel2PL, 0.3, 0.25},
+		{"3PL", IRTModel3PL, 0.6, 0.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := calibrate(tt.model, persons, choices)
+			if !result.converged {
+				t.Errorf("calibrate() did not converge in %d cycles", result.cycles)
+			}
+			if len(result.items) != len(truth) {
+				t.Fatalf("len(items) = %d, want %d", len(result.items), len(truth))
+			}
+			for i, got := range result.items {
+				want := truth[i]
+				if math.Abs(got.A-want.A) > tt.tolA || math.Abs(got.B-want.B) > tt.tolB {
+					t.Errorf("item %d = %+v, want close to %+v", i, got, want)
+				}
+				if tt.model == IRTModel2PL && got.C != 0 {
+					t.Errorf("item %d guessing = %v, want 0 in the 2PL model", i, got.C)
+				}
+				if got.C < irtMinC || got.C > irtMaxC {
+					t.Errorf("item %d guessing = %v, out of bounds", i, got.C)
+				}
+			}
+			for i := 1; i < len(result.items); i++ {
+				if result.items[i].B <= result.items[i-1].B {
+					t.Errorf("difficulties out of order: %+v", result.items)
+					break
+				}
+			}
+		})
+	}
+}
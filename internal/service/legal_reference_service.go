@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ErrInvalidLegalReference is returned when a legal reference has no law.
+var ErrInvalidLegalReference = errors.New("referência legal inválida")
+
+// LegalReference identifies a statute and, optionally, an article and a
+// paragraph of it. When used as a filter, empty article or paragraph match
+// any value.
+type LegalReference struct {
+	Law       string `json:"law"`
+	Article   string `json:"article"`
+	Paragraph string `json:"paragraph"`
+}
+
+// String describes the reference, e.g. "Lei 8.112/1990, art. 37, § 1º".
+func (r LegalReference) String() string {
+	s := r.Law
+	if r.Article != "" {
+		s += ", art. " + r.Article
+	}
+	if r.Paragraph != "" {
+		s += ", " + r.Paragraph
+	}
+	return s
+}
+
+func (r LegalReference) normalize() (LegalReference, error) {
+	r.Law = strings.TrimSpace(r.Law)
+	r.Article = strings.TrimSpace(r.Article)
+	r.Paragraph = strings.TrimSpace(r.Paragraph)
+	if r.Law == "" {
+		return r, fmt.Errorf("%w: a lei é obrigatória", ErrInvalidLegalReference)
+	}
+	return r, nil
+}
+
+// LegalReferenceService attaches statutes to questions so that the
+// questions citing a law can be found and sent back to review when the law
+// changes.
+type LegalReferenceService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewLegalReferenceService creates a new LegalReferenceService.
+func NewLegalReferenceService(pool *pgxpool.Pool) *LegalReferenceService {
+	return &LegalReferenceService{pool: pool, q: db.New(pool)}
+}
+
+// AddLegalReference attaches a reference to an active question.
+func (s *LegalReferenceService) AddLegalReference(ctx context.Context, questionID pgtype.UUID, ref LegalReference) (db.LegalReference, error) {
+	ref, err := ref.normalize()
+	if err != nil {
+		return db.LegalReference{}, err
+	}
+	if _, err := s.q.GetQuestion(ctx, questionID); err != nil {
+		return db.LegalReference{}, err
+	}
+	return s.q.CreateLegalReference(ctx, db.CreateLegalReferenceParams{
+		QuestionID: questionID,
+		Law:        ref.Law,
+		Article:    pgtype.Text{String: ref.Article, Valid: ref.Article != ""},
+		Paragraph:  pgtype.Text{String: ref.Paragraph, Valid: ref.Paragraph != ""},
+	})
+}
+
+func (s *LegalReferenceService) ListLegalReferences(ctx context.Context, questionID pgtype.UUID) ([]db.LegalReference, error) {
+	return s.q.ListLegalReferencesByQuestion(ctx, questionID)
+}
+
+func (s *LegalReferenceService) DeleteLegalReference(ctx context.Context, questionID, id pgtype.UUID) error {
+	n, err := s.q.DeleteLegalReference(ctx, db.DeleteLegalReferenceParams{ID: id, QuestionID: questionID})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListQuestionsCiting returns the active questions citing the law, and the
+// article and paragraph when given. The law is matched ignoring case.
+func (s *LegalReferenceService) ListQuestionsCiting(ctx context.Context, ref LegalReference) ([]db.Question, error) {
+	ref, err := ref.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return listQuestionsCiting(ctx, s.q, ref)
+}
+
+// MarkForReview sends every active question citing the reference back to
+// the review queue: each one moves to changes_requested, which keeps it out
+// of exams, and gets a review entry by the request user with the comment.
+// When comment is empty, a note naming the reference is used.
+func (s *LegalReferenceService) MarkForReview(ctx context.Context, ref LegalReference, comment string) ([]db.Question, error) {
+	ref, err := ref.normalize()
+	if err != nil {
+		return nil, err
+	}
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		comment = "Referência legal alterada: " + ref.String()
+	}
+
+	var marked []db.Question
+	err = inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		questions, err := listQuestionsCiting(ctx, qtx, ref)
+		if err != nil {
+			return err
+		}
+		marked = make([]db.Question, 0, len(questions))
+		for _, q := range questions {
+			question, err := qtx.SetQuestionReviewStatus(ctx, db.SetQuestionReviewStatusParams{
+				ID:           q.ID,
+				ReviewStatus: ReviewStatusChangesRequested,
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao atualizar status da questão: %w", err)
+			}
+			if _, err := qtx.CreateQuestionReview(ctx, db.CreateQuestionReviewParams{
+				QuestionID: q.ID,
+				Reviewer:   UserFromContext(ctx),
+				Action:     ReviewActionRequestChanges,
+				Comment:    pgtype.Text{String: comment, Valid: true},
+			}); err != nil {
+				return fmt.Errorf("erro ao registrar revisão: %w", err)
+			}
+			marked = append(marked, question)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marked, nil
+}
+
+func listQuestionsCiting(ctx context.Context, q db.Querier, ref LegalReference) ([]db.Question, error) {
+	return q.ListQuestionsByLegalReference(ctx, db.ListQuestionsByLegalReferenceParams{
+		Law:       ref.Law,
+		Article:   pgtype.Text{String: ref.Article, Valid: ref.Article != ""},
+		Paragraph: pgtype.Text{String: ref.Paragraph, Valid: ref.Paragraph != ""},
+	})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeStatement reduces a statement to lowercase words without accents,
+// punctuation or repeated whitespace. Two statements that differ only in
+// quoting, spacing or accentuation normalize to the same string.
+func NormalizeStatement(statement string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, statement)
+	if err != nil {
+		stripped = statement
+	}
+
+	var b strings.Builder
+	b.Grow(len(stripped))
+	pendingSpace := false
+	for _, r := range strings.ToLower(stripped) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingSpace = true
+			continue
+		}
+		if pendingSpace && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		pendingSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestNormalizeStatement(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"lowercase", "Qual É a Capital", "qual e a capital"},
+		{"accents", "Ação, coração e pêssego", "acao coracao e pessego"},
+		{"cedilla", "Atenção à exceção", "atencao a excecao"},
+		{"punctuation", "Assinale: (a) certo; (b) errado!", "assinale a certo b errado"},
+		{"quotes", "“Lei” e \"lei\"", "lei e lei"},
+		{"whitespace", "  muitos\t\tespaços \n aqui  ", "muitos espacos aqui"},
+		{"digits", "Art. 5º, inciso XXXV", "art 5º inciso xxxv"},
+		{"only punctuation", "?!.", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeStatement(tt.in); got != tt.want {
+				t.Errorf("NormalizeStatement(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// MaxBulkUpdate caps how many questions a single bulk update may touch.
+const MaxBulkUpdate = 2000
+
+// ErrInvalidBulkUpdate is returned when a bulk update has no selection, no
+// changes, or selects too many questions.
+var ErrInvalidBulkUpdate = errors.New("edição em lote inválida")
+
+// errDryRun rolls back the transaction of a dry-run bulk update.
+var errDryRun = errors.New("dry run")
+
+// BulkSelection picks the questions of a bulk update, either by ID or by
+// metadata filters. Exactly one of them must be given.
+type BulkSelection struct {
+	IDs    []pgtype.UUID   `json:"ids,omitempty"`
+	Filter *QuestionFilter `json:"filter,omitempty"`
+}
+
+// BulkChanges holds the metadata set on every selected question. Nil or
+// null fields keep their current value.
+type BulkChanges struct {
+	TopicID      *pgtype.UUID `json:"topic_id,omitempty"`
+	Year         *pgtype.Int4 `json:"year,omitempty"`
+	Position     *pgtype.Text `json:"position,omitempty"`
+	Level        *pgtype.Text `json:"level,omitempty"`
+	Difficulty   *pgtype.Text `json:"difficulty,omitempty"`
+	Modality     *pgtype.Text `json:"modality,omitempty"`
+	PracticeArea *pgtype.Text `json:"practice_area,omitempty"`
+	FieldOfStudy *pgtype.Text `json:"field_of_study,omitempty"`
+	Board        *pgtype.Text `json:"board,omitempty"`
+}
+
+// BulkUpdateResult lists the questions of a bulk update as they are, or
+// would be on a dry run, after the changes.
+type BulkUpdateResult struct {
+	DryRun    bool           `json:"dry_run"`
+	Affected  int            `json:"affected"`
+	Questions []db.Question  `json:"questions"`
+	Audit     *db.BulkUpdate `json:"audit,omitempty"`
+}
+
+// BulkUpdateQuestions applies the same metadata changes to every selected
+// question in one transaction. Each question gets a revision and must
+// still pass the answer-key rules, so changing the banca or modality fails
+// when it would leave a question with the wrong number of choices. The
+// operation is logged with its author, selection and changes. On a dry run
+// the update is rolled back and the result previews the affected rows.
+func (s *QuestionService) BulkUpdateQuestions(ctx context.Context, selection BulkSelection, changes BulkChanges, dryRun bool) (BulkUpdateResult, error) {
+	params, err := changes.params()
+	if err != nil {
+		return BulkUpdateResult{}, err
+	}
+
+	result := BulkUpdateResult{DryRun: dryRun}
+	err = inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		ids, err := bulkSelect(ctx, qtx, selection)
+		if err != nil {
+			return err
+		}
+		if changes.TopicID != nil && changes.TopicID.Valid {
+			if _, err := qtx.GetTopic(ctx, *changes.TopicID); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("%w: tópico não encontrado", ErrInvalidBulkUpdate)
+				}
+				return err
+			}
+		}
+
+		params.Ids = ids
+		questions, err := qtx.BulkUpdateQuestions(ctx, params)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar questões: %w", err)
+		}
+		for _, q := range questions {
+			if err := checkAnswerKey(ctx, qtx, q.ID); err != nil {
+				return fmt.Errorf("questão %s: %w", q.ID.String(), err)
+			}
+			if _, err := recordRevision(ctx, qtx, q.ID, RevisionReasonBulkUpdate); err != nil {
+				return err
+			}
+		}
+		result.Affected = len(questions)
+		result.Questions = questions
+		if dryRun {
+			return errDryRun
+		}
+
+		audit, err := recordBulkUpdate(ctx, qtx, selection, changes, questions)
+		if err != nil {
+			return err
+		}
+		result.Audit = &audit
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return BulkUpdateResult{}, err
+	}
+	return result, nil
+}
+
+// ListBulkUpdates returns the audit log of bulk updates, newest first.
+func (s *QuestionService) ListBulkUpdates(ctx context.Context) ([]db.BulkUpdate, error) {
+	return s.svc.ListBulkUpdates(ctx)
+}
+
+// bulkSelect resolves the selection to the IDs of active questions.
+func bulkSelect(ctx context.Context, qtx *db.Queries, selection BulkSelection) ([]pgtype.UUID, error) {
+	var questions []db.Question
+	switch {
+	case len(selection.IDs) > 0 && selection.Filter != nil:
+		return nil, fmt.Errorf("%w: informe ids ou filtro, não ambos", ErrInvalidBulkUpdate)
+	case len(selection.IDs) > 0:
+		var err error
+		questions, err = qtx.ListQuestionsByIDs(ctx, selection.IDs)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar questões: %w", err)
+		}
+		if len(questions) != len(selection.IDs) {
+			return nil, fmt.Errorf("%w: %d de %d questões não encontradas ou repetidas",
+				ErrInvalidBulkUpdate, len(selection.IDs)-len(questions), len(selection.IDs))
+		}
+	case selection.Filter != nil && !selection.Filter.empty():
+		var err error
+		questions, err = listQuestionsByFilters(ctx, qtx, *selection.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar questões: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: informe ids ou ao menos um filtro", ErrInvalidBulkUpdate)
+	}
+
+	if len(questions) > MaxBulkUpdate {
+		return nil, fmt.Errorf("%w: %d questões selecionadas, máximo: %d", ErrInvalidBulkUpdate, len(questions), MaxBulkUpdate)
+	}
+	ids := make([]pgtype.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	return ids, nil
+}
+
+func (c BulkChanges) params() (db.BulkUpdateQuestionsParams, error) {
+	var params db.BulkUpdateQuestionsParams
+	set := false
+	if c.TopicID != nil && c.TopicID.Valid {
+		params.TopicID, set = *c.TopicID, true
+	}
+	if c.Year != nil && c.Year.Valid {
+		params.Year, set = *c.Year, true
+	}
+	for _, f := range []struct {
+		src *pgtype.Text
+		dst *pgtype.Text
+	}{
+		{c.Position, &params.Position},
+		{c.Level, &params.Level},
+		{c.Difficulty, &params.Difficulty},
+		{c.Modality, &params.Modality},
+		{c.PracticeArea, &params.PracticeArea},
+		{c.FieldOfStudy, &params.FieldOfStudy},
+		{c.Board, &params.Board},
+	} {
+		if f.src != nil && f.src.Valid {
+			*f.dst, set = *f.src, true
+		}
+	}
+	if !set {
+		return params, fmt.Errorf("%w: nenhuma alteração informada", ErrInvalidBulkUpdate)
+	}
+	return params, nil
+}
+
+func recordBulkUpdate(ctx context.Context, qtx *db.Queries, selection BulkSelection, changes BulkChanges, questions []db.Question) (db.BulkUpdate, error) {
+	selectionJSON, err := json.Marshal(selection)
+	if err != nil {
+		return db.BulkUpdate{}, err
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return db.BulkUpdate{}, err
+	}
+	ids := make([]pgtype.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	audit, err := qtx.CreateBulkUpdate(ctx, db.CreateBulkUpdateParams{
+		Author:      UserFromContext(ctx),
+		Selection:   selectionJSON,
+		Changes:     changesJSON,
+		QuestionIds: ids,
+	})
+	if err != nil {
+		return db.BulkUpdate{}, fmt.Errorf("erro ao registrar edição em lote: %w", err)
+	}
+	return audit, nil
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ErrInvalidChoices is returned when the choices of a question break the
+// answer-key rules: every choice needs a text and exactly one must be correct.
+var ErrInvalidChoices = errors.New("alternativas inválidas")
+
+// QuestionPatch holds the fields of a partial question update. Nil fields
+// keep their current value; a nil Choices keeps the current choices.
+type QuestionPatch struct {
+	Statement    *string
+	Year         *int32
+	TopicID      *pgtype.UUID
+	Position     *pgtype.Text
+	Level        *pgtype.Text
+	Difficulty   *pgtype.Text
+	Modality     *pgtype.Text
+	PracticeArea *pgtype.Text
+	FieldOfStudy *pgtype.Text
+	Explanation  *pgtype.Text
+	Board        *pgtype.Text
+	ChoiceCount  *pgtype.Int4
+	Choices      []ChoiceInput
+}
+
+// CreateQuestionWithChoices creates a question and all its choices in one
+// transaction, after checking the choice count and the correct answer.
+// Unless allowSimilar is set, it fails with a SimilarQuestionsError when a
+// near-duplicate statement already exists.
+func (s *QuestionService) CreateQuestionWithChoices(ctx context.Context, input QuestionWithChoicesInput, allowSimilar bool) (db.Question, []db.Choice, error) {
+	var (
+		question db.Question
+		choices  []db.Choice
+	)
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if err := validateQuestionChoices(ctx, qtx, input.Question, input.Choices); err != nil {
+			return err
+		}
+		if !allowSimilar {
+			if err := checkSimilar(ctx, qtx, input.Question.Statement); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		question, err = qtx.CreateQuestion(ctx, createQuestionParams(input.Question))
+		if err != nil {
+			return fmt.Errorf("erro ao criar questão: %w", err)
+		}
+		if choices, err = applyChoices(ctx, qtx, question.ID, input.Choices); err != nil {
+			return err
+		}
+		_, err = recordRevision(ctx, qtx, question.ID, RevisionReasonCreate)
+		return err
+	})
+	if err != nil {
+		return db.Question{}, nil, err
+	}
+	return question, choices, nil
+}
+
+// ReplaceQuestionWithChoices overwrites a question and its whole list of
+// choices in one transaction. Choices carrying the ID of an existing choice
+// are updated in place, choices without ID are created, and existing
+// choices left out are moved to the trash. The list order becomes the
+// letter order.
+func (s *QuestionService) ReplaceQuestionWithChoices(ctx context.Context, id pgtype.UUID, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
+	input.Question.ID = id
+	var (
+		question db.Question
+		choices  []db.Choice
+	)
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.GetQuestionForUpdate(ctx, id); err != nil {
+			return err
+		}
+		var err error
+		question, choices, err = replaceQuestionWithChoices(ctx, qtx, input)
+		return err
+	})
+	if err != nil {
+		return db.Question{}, nil, err
+	}
+	return question, choices, nil
+}
+
+// PatchQuestionWithChoices applies a partial update to a question and,
+// when given, replaces its choices, in one transaction. The result must
+// still satisfy the choice count and correct answer rules.
+func (s *QuestionService) PatchQuestionWithChoices(ctx context.Context, id pgtype.UUID, patch QuestionPatch) (db.Question, []db.Choice, error) {
+	var (
+		question db.Question
+		choices  []db.Choice
+	)
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		current, err := qtx.GetQuestionForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		input := QuestionWithChoicesInput{Question: patch.apply(current), Choices: patch.Choices}
+		if input.Choices == nil {
+			existing, err := qtx.ListChoicesByQuestion(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, c := range existing {
+				input.Choices = append(input.Choices, ChoiceInput{ID: c.ID, Text: c.ChoiceText, IsCorrect: c.IsCorrect.Bool})
+			}
+		}
+		question, choices, err = replaceQuestionWithChoices(ctx, qtx, input)
+		return err
+	})
+	if err != nil {
+		return db.Question{}, nil, err
+	}
+	return question, choices, nil
+}
+
+func (p QuestionPatch) apply(q db.Question) db.Question {
+	if p.Statement != nil {
+		q.Statement = *p.Statement
+	}
+	if p.Year != nil {
+		q.Year = *p.Year
+	}
+	if p.TopicID != nil {
+		q.TopicID = *p.TopicID
+	}
+	if p.Position != nil {
+		q.Position = *p.Position
+	}
+	if p.Level != nil {
+		q.Level = *p.Level
+	}
+	if p.Difficulty != nil {
+		q.Difficulty = *p.Difficulty
+	}
+	if p.Modality != nil {
+		q.Modality = *p.Modality
+	}
+	if p.PracticeArea != nil {
+		q.PracticeArea = *p.PracticeArea
+	}
+	if p.FieldOfStudy != nil {
+		q.FieldOfStudy = *p.FieldOfStudy
+	}
+	if p.Explanation != nil {
+		q.Explanation = *p.Explanation
+	}
+	if p.Board != nil {
+		q.Board = *p.Board
+	}
+	if p.ChoiceCount != nil {
+		q.ChoiceCount = *p.ChoiceCount
+	}
+	return q
+}
+
+func replaceQuestionWithChoices(ctx context.Context, qtx *db.Queries, input QuestionWithChoicesInput) (db.Question, []db.Choice, error) {
+	if err := validateQuestionChoices(ctx, qtx, input.Question, input.Choices); err != nil {
+		return db.Question{}, nil, err
+	}
+	question, err := qtx.UpdateQuestion(ctx, updateQuestionParams(input.Question))
+	if err != nil {
+		return db.Question{}, nil, fmt.Errorf("erro ao atualizar questão: %w", err)
+	}
+	choices, err := applyChoices(ctx, qtx, question.ID, input.Choices)
+	if err != nil {
+		return db.Question{}, nil, err
+	}
+	if _, err := recordRevision(ctx, qtx, question.ID, RevisionReasonUpdate); err != nil {
+		return db.Question{}, nil, err
+	}
+	// Recording the revision may have sent the question back to review.
+	if question, err = qtx.GetQuestion(ctx, question.ID); err != nil {
+		return db.Question{}, nil, err
+	}
+	return question, choices, nil
+}
+
+// validateQuestionChoices checks that the question's topic is active and
+// the choices against the count expected for the question and the answer
+// rules.
+func validateQuestionChoices(ctx context.Context, q db.Querier, question db.Question, choices []ChoiceInput) error {
+	if err := requireActiveTopic(ctx, q, question.TopicID); err != nil {
+		return err
+	}
+	if question.ChoiceCount.Valid {
+		if err := validateChoiceCount(question.ChoiceCount.Int32); err != nil {
+			return err
+		}
+	}
+	expected, err := resolveChoiceCount(ctx, q, question)
+	if err != nil {
+		return err
+	}
+	if len(choices) != expected {
+		return fmt.Errorf("%w: a questão deve ter exatamente %d alternativas (%s), encontrado: %d",
+			ErrChoiceCount, expected, choiceRange(expected), len(choices))
+	}
+	return validateChoices(choices)
+}
+
+// validateChoices checks that every choice has a text and exactly one is
+// correct.
+func validateChoices(choices []ChoiceInput) error {
+	correctCount := 0
+	for i, c := range choices {
+		if strings.TrimSpace(c.Text) == "" {
+			return fmt.Errorf("%w: a alternativa %c está vazia", ErrInvalidChoices, 'A'+i)
+		}
+		if c.IsCorrect {
+			correctCount++
+		}
+	}
+	if correctCount != 1 {
+		return fmt.Errorf("%w: deve haver exatamente 1 alternativa correta, encontrado: %d", ErrInvalidChoices, correctCount)
+	}
+	return nil
+}
+
+// applyChoices makes the active choices of a question match the given list,
+// in order. Inputs with an ID update that choice, inputs without one create
+// a new choice, and active choices left out are moved to the trash.
+func applyChoices(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID, inputs []ChoiceInput) ([]db.Choice, error) {
+	current, err := qtx.ListChoicesByQuestion(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar alternativas: %w", err)
+	}
+	existing := make(map[pgtype.UUID]bool, len(current))
+	for _, c := range current {
+		existing[c.ID] = true
+	}
+
+	kept := make(map[pgtype.UUID]bool, len(inputs))
+	order := make([]pgtype.UUID, 0, len(inputs))
+	for i, c := range inputs {
+		isCorrect := pgtype.Bool{Bool: c.IsCorrect, Valid: true}
+		if c.ID.Valid {
+			if !existing[c.ID] || kept[c.ID] {
+				return nil, fmt.Errorf("%w: a alternativa %c não pertence à questão ou está repetida", ErrInvalidChoices, 'A'+i)
+			}
+			if _, err := qtx.UpdateChoice(ctx, db.UpdateChoiceParams{ID: c.ID, ChoiceText: c.Text, IsCorrect: isCorrect}); err != nil {
+				return nil, fmt.Errorf("erro ao atualizar alternativa %d: %w", i+1, err)
+			}
+			kept[c.ID] = true
+			order = append(order, c.ID)
+			continue
+		}
+		created, err := qtx.CreateChoice(ctx, db.CreateChoiceParams{QuestionID: questionID, ChoiceText: c.Text, IsCorrect: isCorrect})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar alternativa %d: %w", i+1, err)
+		}
+		order = append(order, created.ID)
+	}
+
+	for _, c := range current {
+		if !kept[c.ID] {
+			if err := softDelete(ctx, qtx, TrashKindChoice, c.ID); err != nil {
+				return nil, fmt.Errorf("erro ao remover alternativa: %w", err)
+			}
+		}
+	}
+	if err := setChoicePositions(ctx, qtx, order); err != nil {
+		return nil, err
+	}
+	return qtx.ListChoicesByQuestion(ctx, questionID)
+}
+
+func createQuestionParams(q db.Question) db.CreateQuestionParams {
+	return db.CreateQuestionParams{
+		Statement:           q.Statement,
+		Year:                q.Year,
+		TopicID:             q.TopicID,
+		Position:            q.Position,
+		Level:               q.Level,
+		Difficulty:          q.Difficulty,
+		Modality:            q.Modality,
+		PracticeArea:        q.PracticeArea,
+		FieldOfStudy:        q.FieldOfStudy,
+		NormalizedStatement: NormalizeStatement(q.Statement),
+		Explanation:         q.Explanation,
+		Board:               q.Board,
+		ChoiceCount:         q.ChoiceCount,
+		ExternalID:          q.ExternalID,
+	}
+}
+
+func createQuestionWithIDParams(q db.Question) db.CreateQuestionWithIDParams {
+	p := createQuestionParams(q)
+	return db.CreateQuestionWithIDParams{
+		ID:                  q.ID,
+		Statement:           p.Statement,
+		Year:                p.Year,
+		TopicID:             p.TopicID,
+		Position:            p.Position,
+		Level:               p.Level,
+		Difficulty:          p.Difficulty,
+		Modality:            p.Modality,
+		PracticeArea:        p.PracticeArea,
+		FieldOfStudy:        p.FieldOfStudy,
+		NormalizedStatement: p.NormalizedStatement,
+		Explanation:         p.Explanation,
+		Board:               p.Board,
+		ChoiceCount:         p.ChoiceCount,
+		ExternalID:          p.ExternalID,
+	}
+}
+
+func updateQuestionParams(q db.Question) db.UpdateQuestionParams {
+	return db.UpdateQuestionParams{
+		ID:                  q.ID,
+		Statement:           q.Statement,
+		Year:                q.Year,
+		TopicID:             q.TopicID,
+		Position:            q.Position,
+		Level:               q.Level,
+		Difficulty:          q.Difficulty,
+		Modality:            q.Modality,
+		PracticeArea:        q.PracticeArea,
+		FieldOfStudy:        q.FieldOfStudy,
+		NormalizedStatement: NormalizeStatement(q.Statement),
+		Explanation:         q.Explanation,
+		Board:               q.Board,
+		ChoiceCount:         q.ChoiceCount,
+	}
+}
+
+// requireActiveTopic fails with ErrUnknownTopic when the topic a question is
+// saved under does not exist or is in the trash, where purging it would
+// take the question along.
+func requireActiveTopic(ctx context.Context, q db.Querier, topicID pgtype.UUID) error {
+	_, err := q.GetTopic(ctx, topicID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: tópico %s não existe ou está na lixeira", ErrUnknownTopic, topicID.String())
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao buscar tópico: %w", err)
+	}
+	return nil
+}
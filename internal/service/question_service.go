@@ -4,39 +4,69 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
 )
 
 type QuestionService struct {
-	svc db.Querier
+	pool *pgxpool.Pool
+	svc  db.Querier
 }
 
 type QuestionFilter struct {
-	TopicID      *pgtype.UUID
-	Position     *pgtype.Text
-	Level        *pgtype.Text
-	Difficulty   *pgtype.Text
-	Modality     *pgtype.Text
-	PracticeArea *pgtype.Text
-	FieldOfStudy *pgtype.Text
-}
-
-func NewQuestionService(svc db.Querier) *QuestionService {
-	return &QuestionService{svc: svc}
-}
-
-func (s *QuestionService) CreateQuestion(ctx context.Context, question db.Question) (db.Question, error) {
-	row, err := s.svc.CreateQuestion(ctx, db.CreateQuestionParams{
-		Statement:    question.Statement,
-		Year:         question.Year,
-		TopicID:      question.TopicID,
-		Position:     question.Position,
-		Level:        question.Level,
-		Difficulty:   question.Difficulty,
-		Modality:     question.Modality,
-		PracticeArea: question.PracticeArea,
-		FieldOfStudy: question.FieldOfStudy,
+	Year         *pgtype.Int4 `json:"year,omitempty"`
+	TopicID      *pgtype.UUID `json:"topic_id,omitempty"`
+	Position     *pgtype.Text `json:"position,omitempty"`
+	Level        *pgtype.Text `json:"level,omitempty"`
+	Difficulty   *pgtype.Text `json:"difficulty,omitempty"`
+	Modality     *pgtype.Text `json:"modality,omitempty"`
+	PracticeArea *pgtype.Text `json:"practice_area,omitempty"`
+	FieldOfStudy *pgtype.Text `json:"field_of_study,omitempty"`
+}
+
+// empty reports whether the filter matches every question.
+func (f QuestionFilter) empty() bool {
+	for _, t := range []*pgtype.Text{f.Position, f.Level, f.Difficulty, f.Modality, f.PracticeArea, f.FieldOfStudy} {
+		if t != nil && t.Valid {
+			return false
+		}
+	}
+	return (f.Year == nil || !f.Year.Valid) && (f.TopicID == nil || !f.TopicID.Valid)
+}
+
+func NewQuestionService(pool *pgxpool.Pool) *QuestionService {
+	return &QuestionService{pool: pool, svc: db.New(pool)}
+}
+
+// CreateQuestion creates a question and records its first revision.
+// Unless allowSimilar is set, it fails with a SimilarQuestionsError when a
+// near-duplicate statement already exists.
+func (s *QuestionService) CreateQuestion(ctx context.Context, question db.Question, allowSimilar bool) (db.Question, error) {
+	if question.ChoiceCount.Valid {
+		if err := validateChoiceCount(question.ChoiceCount.Int32); err != nil {
+			return db.Question{}, err
+		}
+	}
+
+	var row db.Question
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if err := requireActiveTopic(ctx, qtx, question.TopicID); err != nil {
+			return err
+		}
+		if !allowSimilar {
+			if err := checkSimilar(ctx, qtx, question.Statement); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		row, err = qtx.CreateQuestion(ctx, createQuestionParams(question))
+		if err != nil {
+			return err
+		}
+		_, err = recordRevision(ctx, qtx, row.ID, RevisionReasonCreate)
+		return err
 	})
 	if err != nil {
 		return db.Question{}, err
@@ -52,24 +82,52 @@ func (s *QuestionService) GetQuestion(ctx context.Context, id pgtype.UUID) (db.Q
 	return s.svc.GetQuestion(ctx, id)
 }
 
+// UpdateQuestion overwrites a question and appends the new content to its history.
 func (s *QuestionService) UpdateQuestion(ctx context.Context, question db.Question) (db.Question, error) {
-	arg := db.UpdateQuestionParams{
-		ID:           question.ID,
-		Statement:    question.Statement,
-		Year:         question.Year,
-		TopicID:      question.TopicID,
-		Position:     question.Position,
-		Level:        question.Level,
-		Difficulty:   question.Difficulty,
-		Modality:     question.Modality,
-		PracticeArea: question.PracticeArea,
-		FieldOfStudy: question.FieldOfStudy,
+	if question.ChoiceCount.Valid {
+		if err := validateChoiceCount(question.ChoiceCount.Int32); err != nil {
+			return db.Question{}, err
+		}
+	}
+
+	var row db.Question
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if err := requireActiveTopic(ctx, qtx, question.TopicID); err != nil {
+			return err
+		}
+		var err error
+		row, err = qtx.UpdateQuestion(ctx, updateQuestionParams(question))
+		if err != nil {
+			return err
+		}
+		// Changing the banca, modality or choice count changes how many
+		// choices the question must have.
+		if err := checkAnswerKey(ctx, qtx, row.ID); err != nil {
+			return err
+		}
+		if _, err := recordRevision(ctx, qtx, row.ID, RevisionReasonUpdate); err != nil {
+			return err
+		}
+		// Recording the revision may have sent the question back to review.
+		row, err = qtx.GetQuestion(ctx, row.ID)
+		return err
+	})
+	if err != nil {
+		return db.Question{}, err
 	}
-	return s.svc.UpdateQuestion(ctx, arg)
+	return row, nil
 }
 
+// DeleteQuestion moves a question and its choices to the trash.
 func (s *QuestionService) DeleteQuestion(ctx context.Context, id pgtype.UUID) error {
-	return s.svc.DeleteQuestion(ctx, id)
+	return inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		return softDelete(ctx, qtx, TrashKindQuestion, id)
+	})
+}
+
+// DeleteQuestionImpact reports what DeleteQuestion would move to the trash.
+func (s *QuestionService) DeleteQuestionImpact(ctx context.Context, id pgtype.UUID) (DeleteImpact, error) {
+	return deleteImpact(ctx, s.svc, TrashKindQuestion, id)
 }
 
 func (s *QuestionService) ListQuestionsByTopic(ctx context.Context, topicID pgtype.UUID) ([]db.Question, error) {
@@ -77,9 +135,16 @@ func (s *QuestionService) ListQuestionsByTopic(ctx context.Context, topicID pgty
 }
 
 func (s *QuestionService) ListQuestionsByFilters(ctx context.Context, filters QuestionFilter) ([]db.Question, error) {
+	return listQuestionsByFilters(ctx, s.svc, filters)
+}
+
+func listQuestionsByFilters(ctx context.Context, q db.Querier, filters QuestionFilter) ([]db.Question, error) {
 	params := db.ListQuestionsByFiltersParams{}
 
 	// Convert pointer fields to values, using empty/invalid values when nil
+	if filters.Year != nil {
+		params.Year = *filters.Year
+	}
 	if filters.TopicID != nil {
 		params.TopicID = *filters.TopicID
 	}
@@ -102,7 +167,7 @@ func (s *QuestionService) ListQuestionsByFilters(ctx context.Context, filters Qu
 		params.FieldOfStudy = *filters.FieldOfStudy
 	}
 
-	row, err := s.svc.ListQuestionsByFilters(ctx, params)
+	row, err := q.ListQuestionsByFilters(ctx, params)
 	if err != nil {
 		return []db.Question{}, err
 	}
@@ -113,6 +178,9 @@ func (s *QuestionService) ListQuestionsByFiltersWithChoices(ctx context.Context,
 	params := db.ListQuestionsByFiltersWithChoicesParams{}
 
 	// Convert pointer fields to values, using empty/invalid values when nil
+	if filters.Year != nil {
+		params.Year = *filters.Year
+	}
 	if filters.TopicID != nil {
 		params.TopicID = *filters.TopicID
 	}
@@ -141,3 +209,61 @@ func (s *QuestionService) ListQuestionsByFiltersWithChoices(ctx context.Context,
 	}
 	return row, nil
 }
+
+// SearchResult is a page of full-text search hits.
+type SearchResult struct {
+	Total   int64                   `json:"total"`
+	Results []db.SearchQuestionsRow `json:"results"`
+}
+
+// SearchQuestions runs a Portuguese full-text search over statements,
+// choices and explanations, combined with the metadata filters. The query
+// accepts web search syntax: quoted phrases, OR and -exclusion. Results are
+// ranked by relevance and carry highlighted snippets.
+func (s *QuestionService) SearchQuestions(ctx context.Context, query string, filters QuestionFilter, limit, offset int32) (SearchResult, error) {
+	params := db.SearchQuestionsParams{Query: query, Limit: limit, Offset: offset}
+	if filters.Year != nil {
+		params.Year = *filters.Year
+	}
+	if filters.TopicID != nil {
+		params.TopicID = *filters.TopicID
+	}
+	if filters.Position != nil {
+		params.Position = *filters.Position
+	}
+	if filters.Level != nil {
+		params.Level = *filters.Level
+	}
+	if filters.Difficulty != nil {
+		params.Difficulty = *filters.Difficulty
+	}
+	if filters.Modality != nil {
+		params.Modality = *filters.Modality
+	}
+	if filters.PracticeArea != nil {
+		params.PracticeArea = *filters.PracticeArea
+	}
+	if filters.FieldOfStudy != nil {
+		params.FieldOfStudy = *filters.FieldOfStudy
+	}
+
+	total, err := s.svc.CountSearchQuestions(ctx, db.CountSearchQuestionsParams{
+		Query:        params.Query,
+		Year:         params.Year,
+		Level:        params.Level,
+		Difficulty:   params.Difficulty,
+		Modality:     params.Modality,
+		PracticeArea: params.PracticeArea,
+		FieldOfStudy: params.FieldOfStudy,
+		TopicID:      params.TopicID,
+		Position:     params.Position,
+	})
+	if err != nil {
+		return SearchResult{}, err
+	}
+	results, err := s.svc.SearchQuestions(ctx, params)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	return SearchResult{Total: total, Results: results}, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Categories of a question report.
+const (
+	ReportCategoryWrongKey  = "wrong_key"
+	ReportCategoryTypo      = "typo"
+	ReportCategoryOutdated  = "outdated"
+	ReportCategoryDuplicate = "duplicate"
+)
+
+// Statuses of a question report. Open and in_review reports count towards
+// suspending the question; resolved and dismissed ones are closed.
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusInReview  = "in_review"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// DefaultReportSuspensionThreshold is how many open reports suspend a
+// question from exam generation when no other threshold is configured.
+const DefaultReportSuspensionThreshold = 3
+
+// ErrInvalidReport is returned when a report or a moderation decision is
+// malformed.
+var ErrInvalidReport = errors.New("reporte inválido")
+
+var reportCategories = map[string]bool{
+	ReportCategoryWrongKey:  true,
+	ReportCategoryTypo:      true,
+	ReportCategoryOutdated:  true,
+	ReportCategoryDuplicate: true,
+}
+
+var reportStatuses = map[string]bool{
+	ReportStatusOpen:      true,
+	ReportStatusInReview:  true,
+	ReportStatusResolved:  true,
+	ReportStatusDismissed: true,
+}
+
+// ReportResult is a report together with whether its question is
+// suspended from exam generation after the change.
+type ReportResult struct {
+	Report    db.QuestionReport `json:"report"`
+	Suspended bool              `json:"question_suspended"`
+}
+
+// ReportFilter selects reports in the moderation queue. An empty Status
+// lists the reports still open or in review.
+type ReportFilter struct {
+	Status     pgtype.Text
+	Category   pgtype.Text
+	QuestionID pgtype.UUID
+}
+
+// ReportService handles error reports filed by students and teachers and
+// their moderation. A question with suspendAfter or more open reports is
+// suspended from exam generation until moderators close enough of them.
+type ReportService struct {
+	pool         *pgxpool.Pool
+	q            db.Querier
+	suspendAfter int
+}
+
+// NewReportService creates a new ReportService. A suspendAfter of zero or
+// less disables automatic suspension.
+func NewReportService(pool *pgxpool.Pool, suspendAfter int) *ReportService {
+	return &ReportService{pool: pool, q: db.New(pool), suspendAfter: suspendAfter}
+}
+
+// ReportQuestion files a report on a question on behalf of the request
+// user and suspends the question if it reached the threshold.
+func (s *ReportService) ReportQuestion(ctx context.Context, questionID pgtype.UUID, category, comment string) (ReportResult, error) {
+	category = strings.TrimSpace(category)
+	comment = strings.TrimSpace(comment)
+	if !reportCategories[category] {
+		return ReportResult{}, fmt.Errorf("%w: categoria deve ser wrong_key, typo, outdated ou duplicate", ErrInvalidReport)
+	}
+	if comment == "" {
+		return ReportResult{}, fmt.Errorf("%w: comentário é obrigatório", ErrInvalidReport)
+	}
+
+	var result ReportResult
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.GetQuestion(ctx, questionID); err != nil {
+			return err
+		}
+		report, err := qtx.CreateQuestionReport(ctx, db.CreateQuestionReportParams{
+			QuestionID: questionID,
+			Category:   category,
+			Comment:    comment,
+			Reporter:   UserFromContext(ctx),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao registrar reporte: %w", err)
+		}
+		result.Report = report
+		result.Suspended, err = s.syncSuspension(ctx, qtx, questionID)
+		return err
+	})
+	if err != nil {
+		return ReportResult{}, err
+	}
+	return result, nil
+}
+
+func (s *ReportService) GetReport(ctx context.Context, id pgtype.UUID) (db.QuestionReport, error) {
+	return s.q.GetQuestionReport(ctx, id)
+}
+
+// ListReports returns the moderation queue, oldest reports first.
+func (s *ReportService) ListReports(ctx context.Context, filter ReportFilter) ([]db.QuestionReport, error) {
+	if filter.Status.Valid && !reportStatuses[filter.Status.String] {
+		return nil, fmt.Errorf("%w: status deve ser open, in_review, resolved ou dismissed", ErrInvalidReport)
+	}
+	if filter.Category.Valid && !reportCategories[filter.Category.String] {
+		return nil, fmt.Errorf("%w: categoria deve ser wrong_key, typo, outdated ou duplicate", ErrInvalidReport)
+	}
+	return s.q.ListQuestionReports(ctx, db.ListQuestionReportsParams{
+		Status:     filter.Status,
+		Category:   filter.Category,
+		QuestionID: filter.QuestionID,
+	})
+}
+
+// ListQuestionReports returns every report filed on a question.
+func (s *ReportService) ListQuestionReports(ctx context.Context, questionID pgtype.UUID) ([]db.QuestionReport, error) {
+	if _, err := s.q.GetQuestion(ctx, questionID); err != nil {
+		return nil, err
+	}
+	return s.q.ListReportsByQuestion(ctx, questionID)
+}
+
+// ModerateReport moves a report to a new status on behalf of the request
+// user. Resolving or dismissing a report closes it and requires a
+// resolution note; closed reports cannot be changed. The question's
+// suspension is re-evaluated against the remaining open reports.
+func (s *ReportService) ModerateReport(ctx context.Context, id pgtype.UUID, status, resolution string) (ReportResult, error) {
+	status = strings.TrimSpace(status)
+	resolution = strings.TrimSpace(resolution)
+	if !reportStatuses[status] {
+		return ReportResult{}, fmt.Errorf("%w: status deve ser open, in_review, resolved ou dismissed", ErrInvalidReport)
+	}
+	closing := status == ReportStatusResolved || status == ReportStatusDismissed
+	if closing && resolution == "" {
+		return ReportResult{}, fmt.Errorf("%w: nota de resolução é obrigatória para %s", ErrInvalidReport, status)
+	}
+
+	var result ReportResult
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		report, err := qtx.GetQuestionReportForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if report.ClosedAt.Valid {
+			return fmt.Errorf("%w: o reporte já foi encerrado como %s", ErrInvalidReport, report.Status)
+		}
+		params := db.UpdateQuestionReportParams{
+			ID:        id,
+			Status:    status,
+			Moderator: pgtype.Text{String: UserFromContext(ctx), Valid: true},
+		}
+		if resolution != "" {
+			params.Resolution = pgtype.Text{String: resolution, Valid: true}
+		}
+		if closing {
+			params.ClosedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+		result.Report, err = qtx.UpdateQuestionReport(ctx, params)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar reporte: %w", err)
+		}
+		result.Suspended, err = s.syncSuspension(ctx, qtx, report.QuestionID)
+		return err
+	})
+	if err != nil {
+		return ReportResult{}, err
+	}
+	return result, nil
+}
+
+func (s *ReportService) ListSuspensions(ctx context.Context) ([]db.QuestionSuspension, error) {
+	return s.q.ListQuestionSuspensions(ctx)
+}
+
+// syncSuspension suspends the question when its open reports reach the
+// threshold and lifts the suspension when they drop below it. It reports
+// whether the question ends up suspended.
+func (s *ReportService) syncSuspension(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID) (bool, error) {
+	open, err := qtx.CountOpenReports(ctx, questionID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao contar reportes abertos: %w", err)
+	}
+	if s.suspendAfter > 0 && open >= int64(s.suspendAfter) {
+		if _, err := qtx.SuspendQuestion(ctx, db.SuspendQuestionParams{QuestionID: questionID, OpenReports: int32(open)}); err != nil {
+			return false, fmt.Errorf("erro ao suspender questão: %w", err)
+		}
+		return true, nil
+	}
+	if _, err := qtx.LiftQuestionSuspension(ctx, questionID); err != nil {
+		return false, fmt.Errorf("erro ao retirar suspensão: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Review statuses stored in questions.review_status.
+const (
+	ReviewStatusPending          = "pending"
+	ReviewStatusApproved         = "approved"
+	ReviewStatusRejected         = "rejected"
+	ReviewStatusChangesRequested = "changes_requested"
+)
+
+// Review actions a reviewer can take on a question.
+const (
+	ReviewActionApprove        = "approve"
+	ReviewActionReject         = "reject"
+	ReviewActionRequestChanges = "request_changes"
+)
+
+// ErrInvalidReview is returned when a review action is malformed.
+var ErrInvalidReview = errors.New("revisão inválida")
+
+// reviewTransitions maps each action to the status it moves the question to.
+var reviewTransitions = map[string]string{
+	ReviewActionApprove:        ReviewStatusApproved,
+	ReviewActionReject:         ReviewStatusRejected,
+	ReviewActionRequestChanges: ReviewStatusChangesRequested,
+}
+
+// ReviewService handles the editorial review workflow for questions.
+// New and imported questions start as pending and only approved ones
+// are eligible for exam generation.
+type ReviewService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewReviewService creates a new ReviewService.
+func NewReviewService(pool *pgxpool.Pool) *ReviewService {
+	return &ReviewService{pool: pool, q: db.New(pool)}
+}
+
+// ReviewInput represents a reviewer decision on a question.
+type ReviewInput struct {
+	QuestionID pgtype.UUID
+	Reviewer   string
+	Action     string
+	Comment    string
+}
+
+// ReviewQuestion records a review and moves the question to the matching
+// status in a single transaction. Reject and request_changes require a comment.
+func (s *ReviewService) ReviewQuestion(ctx context.Context, input ReviewInput) (db.Question, db.QuestionReview, error) {
+	status, ok := reviewTransitions[input.Action]
+	if !ok {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("%w: ação deve ser approve, reject ou request_changes", ErrInvalidReview)
+	}
+	comment := strings.TrimSpace(input.Comment)
+	if input.Action != ReviewActionApprove && comment == "" {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("%w: comentário é obrigatório para %s", ErrInvalidReview, input.Action)
+	}
+	if strings.TrimSpace(input.Reviewer) == "" {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("%w: revisor é obrigatório", ErrInvalidReview)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := db.New(tx)
+
+	question, err := qtx.SetQuestionReviewStatus(ctx, db.SetQuestionReviewStatusParams{
+		ID:           input.QuestionID,
+		ReviewStatus: status,
+	})
+	if err != nil {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("erro ao atualizar status da questão: %w", err)
+	}
+
+	review, err := qtx.CreateQuestionReview(ctx, db.CreateQuestionReviewParams{
+		QuestionID: input.QuestionID,
+		Reviewer:   input.Reviewer,
+		Action:     input.Action,
+		Comment:    pgtype.Text{String: comment, Valid: comment != ""},
+	})
+	if err != nil {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("erro ao registrar revisão: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.Question{}, db.QuestionReview{}, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return question, review, nil
+}
+
+// ListQuestionReviews returns the review history of a question, newest first.
+func (s *ReviewService) ListQuestionReviews(ctx context.Context, questionID pgtype.UUID) ([]db.QuestionReview, error) {
+	return s.q.ListQuestionReviews(ctx, questionID)
+}
+
+// ListQuestionsForReview returns the questions of a subject in the given
+// review status. An empty status means pending.
+func (s *ReviewService) ListQuestionsForReview(ctx context.Context, subjectID pgtype.UUID, status string) ([]db.Question, error) {
+	if status == "" {
+		status = ReviewStatusPending
+	}
+	if !isReviewStatus(status) {
+		return nil, fmt.Errorf("%w: status %q desconhecido", ErrInvalidReview, status)
+	}
+	return s.q.ListQuestionsForReviewBySubject(ctx, db.ListQuestionsForReviewBySubjectParams{
+		SubjectID:    subjectID,
+		ReviewStatus: status,
+	})
+}
+
+// CountQuestionsForReview returns, per subject, how many questions are in
+// the given review status. An empty status means pending.
+func (s *ReviewService) CountQuestionsForReview(ctx context.Context, status string) ([]db.CountQuestionsForReviewBySubjectRow, error) {
+	if status == "" {
+		status = ReviewStatusPending
+	}
+	if !isReviewStatus(status) {
+		return nil, fmt.Errorf("%w: status %q desconhecido", ErrInvalidReview, status)
+	}
+	return s.q.CountQuestionsForReviewBySubject(ctx, status)
+}
+
+func isReviewStatus(status string) bool {
+	switch status {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected, ReviewStatusChangesRequested:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReviewQuestionValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ReviewInput
+	}{
+		{"unknown action", ReviewInput{Reviewer: "ana", Action: "publish"}},
+		{"reject without comment", ReviewInput{Reviewer: "ana", Action: ReviewActionReject, Comment: "  "}},
+		{"request changes without comment", ReviewInput{Reviewer: "ana", Action: ReviewActionRequestChanges}},
+		{"approve without reviewer", ReviewInput{Reviewer: " ", Action: ReviewActionApprove}},
+	}
+	s := &ReviewService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.ReviewQuestion(context.Background(), tt.input)
+			if !errors.Is(err, ErrInvalidReview) {
+				t.Errorf("ReviewQuestion() error = %v, want ErrInvalidReview", err)
+			}
+		})
+	}
+}
+
+func TestReviewTransitions(t *testing.T) {
+	for action, status := range reviewTransitions {
+		if !isReviewStatus(status) {
+			t.Errorf("action %q moves to unknown status %q", action, status)
+		}
+		if status == ReviewStatusPending {
+			t.Errorf("action %q moves back to pending", action)
+		}
+	}
+	if isReviewStatus("archived") {
+		t.Error(`isReviewStatus("archived") = true`)
+	}
+}
+
+func TestCountQuestionsForReviewUnknownStatus(t *testing.T) {
+	s := &ReviewService{}
+	if _, err := s.CountQuestionsForReview(context.Background(), "archived"); !errors.Is(err, ErrInvalidReview) {
+		t.Errorf("CountQuestionsForReview() error = %v, want ErrInvalidReview", err)
+	}
+}
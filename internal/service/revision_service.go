@@ -0,0 +1,460 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Reasons recorded alongside each revision.
+const (
+	RevisionReasonCreate        = "create"
+	RevisionReasonUpdate        = "update"
+	RevisionReasonImport        = "import"
+	RevisionReasonCreateChoice  = "create_choice"
+	RevisionReasonUpdateChoice  = "update_choice"
+	RevisionReasonDeleteChoice  = "delete_choice"
+	RevisionReasonRestoreChoice = "restore_choice"
+	RevisionReasonReorder       = "reorder_choices"
+	RevisionReasonRestore       = "restore"
+	RevisionReasonExamSnapshot  = "exam_snapshot"
+	RevisionReasonBulkUpdate    = "bulk_update"
+)
+
+// ErrRevisionNotFound is returned when a question has no such revision.
+var ErrRevisionNotFound = errors.New("revisão não encontrada")
+
+// RevisionChoice is the state of a choice inside a revision snapshot.
+type RevisionChoice struct {
+	ID         pgtype.UUID `json:"id"`
+	ChoiceText string      `json:"choice_text"`
+	IsCorrect  bool        `json:"is_correct"`
+}
+
+// RevisionSnapshot is the full content of a question and its choices at a
+// given point in time. Review status and timestamps are deliberately left
+// out: only content changes produce new revisions.
+type RevisionSnapshot struct {
+	Statement    string           `json:"statement"`
+	Year         int32            `json:"year"`
+	TopicID      pgtype.UUID      `json:"topic_id"`
+	Position     pgtype.Text      `json:"position"`
+	Level        pgtype.Text      `json:"level"`
+	Difficulty   pgtype.Text      `json:"difficulty"`
+	Modality     pgtype.Text      `json:"modality"`
+	PracticeArea pgtype.Text      `json:"practice_area"`
+	FieldOfStudy pgtype.Text      `json:"field_of_study"`
+	Explanation  pgtype.Text      `json:"explanation"`
+	Board        pgtype.Text      `json:"board"`
+	ChoiceCount  pgtype.Int4      `json:"choice_count"`
+	Choices      []RevisionChoice `json:"choices"`
+}
+
+// Revision is a decoded question revision.
+type Revision struct {
+	ID         pgtype.UUID        `json:"id"`
+	QuestionID pgtype.UUID        `json:"question_id"`
+	Revision   int32              `json:"revision"`
+	Author     string             `json:"author"`
+	Reason     string             `json:"reason"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Snapshot   RevisionSnapshot   `json:"snapshot"`
+}
+
+// FieldChange describes a single difference between two revisions.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// RevisionService exposes the append-only history of questions and choices.
+type RevisionService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewRevisionService creates a new RevisionService.
+func NewRevisionService(pool *pgxpool.Pool) *RevisionService {
+	return &RevisionService{pool: pool, q: db.New(pool)}
+}
+
+// ListRevisions returns every revision of a question, newest first.
+func (s *RevisionService) ListRevisions(ctx context.Context, questionID pgtype.UUID) ([]Revision, error) {
+	rows, err := s.q.ListQuestionRevisions(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]Revision, 0, len(rows))
+	for _, row := range rows {
+		rev, err := decodeRevision(row)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns a single revision of a question.
+func (s *RevisionService) GetRevision(ctx context.Context, questionID pgtype.UUID, revision int32) (Revision, error) {
+	row, err := s.q.GetQuestionRevision(ctx, db.GetQuestionRevisionParams{
+		QuestionID: questionID,
+		Revision:   revision,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Revision{}, fmt.Errorf("%w: %d", ErrRevisionNotFound, revision)
+	}
+	if err != nil {
+		return Revision{}, err
+	}
+	return decodeRevision(row)
+}
+
+// DiffRevisions lists the fields that changed between two revisions.
+func (s *RevisionService) DiffRevisions(ctx context.Context, questionID pgtype.UUID, from, to int32) ([]FieldChange, error) {
+	fromRev, err := s.GetRevision(ctx, questionID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.GetRevision(ctx, questionID, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffSnapshots(fromRev.Snapshot, toRev.Snapshot)
+}
+
+// RestoreRevision brings a question and its choices back to the content of
+// an older revision. The restore itself is appended as a new revision.
+func (s *RevisionService) RestoreRevision(ctx context.Context, questionID pgtype.UUID, revision int32) (Revision, error) {
+	target, err := s.GetRevision(ctx, questionID, revision)
+	if err != nil {
+		return Revision{}, err
+	}
+	snap := target.Snapshot
+
+	var restored db.QuestionRevision
+	err = inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.UpdateQuestion(ctx, db.UpdateQuestionParams{
+			ID:                  questionID,
+			Statement:           snap.Statement,
+			Year:                snap.Year,
+			TopicID:             snap.TopicID,
+			Position:            snap.Position,
+			Level:               snap.Level,
+			Difficulty:          snap.Difficulty,
+			Modality:            snap.Modality,
+			PracticeArea:        snap.PracticeArea,
+			FieldOfStudy:        snap.FieldOfStudy,
+			NormalizedStatement: NormalizeStatement(snap.Statement),
+			Explanation:         snap.Explanation,
+			Board:               snap.Board,
+			ChoiceCount:         snap.ChoiceCount,
+		}); err != nil {
+			return fmt.Errorf("erro ao restaurar questão: %w", err)
+		}
+
+		current, err := qtx.ListChoicesByQuestion(ctx, questionID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar alternativas: %w", err)
+		}
+		existing := make(map[pgtype.UUID]bool, len(current))
+		for _, c := range current {
+			existing[c.ID] = true
+		}
+
+		kept := make(map[pgtype.UUID]bool, len(snap.Choices))
+		order := make([]pgtype.UUID, 0, len(snap.Choices))
+		for i, c := range snap.Choices {
+			if existing[c.ID] {
+				if _, err := qtx.UpdateChoice(ctx, db.UpdateChoiceParams{
+					ID:         c.ID,
+					ChoiceText: c.ChoiceText,
+					IsCorrect:  pgtype.Bool{Bool: c.IsCorrect, Valid: true},
+				}); err != nil {
+					return fmt.Errorf("erro ao restaurar alternativa %d: %w", i+1, err)
+				}
+				kept[c.ID] = true
+				order = append(order, c.ID)
+				continue
+			}
+			created, err := qtx.CreateChoice(ctx, db.CreateChoiceParams{
+				QuestionID: questionID,
+				ChoiceText: c.ChoiceText,
+				IsCorrect:  pgtype.Bool{Bool: c.IsCorrect, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao recriar alternativa %d: %w", i+1, err)
+			}
+			order = append(order, created.ID)
+		}
+
+		for _, c := range current {
+			if !kept[c.ID] {
+				if err := softDelete(ctx, qtx, TrashKindChoice, c.ID); err != nil {
+					return fmt.Errorf("erro ao remover alternativa: %w", err)
+				}
+			}
+		}
+
+		// Choices come back in the order of the snapshot.
+		if err := setChoicePositions(ctx, qtx, order); err != nil {
+			return err
+		}
+		if err := checkAnswerKey(ctx, qtx, questionID); err != nil {
+			return err
+		}
+
+		restored, err = recordRevision(ctx, qtx, questionID, fmt.Sprintf("%s:%d", RevisionReasonRestore, revision))
+		return err
+	})
+	if err != nil {
+		return Revision{}, err
+	}
+
+	return decodeRevision(restored)
+}
+
+// snapshotQuestion reads the current content of a question, locking its row
+// so concurrent revisions are numbered sequentially.
+func snapshotQuestion(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID) (RevisionSnapshot, error) {
+	question, err := qtx.GetQuestionForUpdate(ctx, questionID)
+	if err != nil {
+		return RevisionSnapshot{}, fmt.Errorf("erro ao buscar questão: %w", err)
+	}
+	choices, err := qtx.ListChoicesByQuestion(ctx, questionID)
+	if err != nil {
+		return RevisionSnapshot{}, fmt.Errorf("erro ao buscar alternativas: %w", err)
+	}
+
+	snap := RevisionSnapshot{
+		Statement:    question.Statement,
+		Year:         question.Year,
+		TopicID:      question.TopicID,
+		Position:     question.Position,
+		Level:        question.Level,
+		Difficulty:   question.Difficulty,
+		Modality:     question.Modality,
+		PracticeArea: question.PracticeArea,
+		FieldOfStudy: question.FieldOfStudy,
+		Explanation:  question.Explanation,
+		Board:        question.Board,
+		ChoiceCount:  question.ChoiceCount,
+		Choices:      make([]RevisionChoice, 0, len(choices)),
+	}
+	for _, c := range choices {
+		snap.Choices = append(snap.Choices, RevisionChoice{
+			ID:         c.ID,
+			ChoiceText: c.ChoiceText,
+			IsCorrect:  c.IsCorrect.Bool,
+		})
+	}
+	return snap, nil
+}
+
+// recordRevision appends the current state of a question to its history,
+// attributed to the user in ctx. It must run in the same transaction as the
+// mutation it records. When the statement or the choices changed since the
+// previous revision, an approved question goes back to review.
+func recordRevision(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID, reason string) (db.QuestionRevision, error) {
+	snap, err := snapshotQuestion(ctx, qtx, questionID)
+	if err != nil {
+		return db.QuestionRevision{}, err
+	}
+	if reason != RevisionReasonExamSnapshot {
+		if err := reopenReviewOnChange(ctx, qtx, questionID, reason, snap); err != nil {
+			return db.QuestionRevision{}, err
+		}
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return db.QuestionRevision{}, fmt.Errorf("erro ao serializar revisão: %w", err)
+	}
+	rev, err := qtx.CreateQuestionRevision(ctx, db.CreateQuestionRevisionParams{
+		QuestionID: questionID,
+		Author:     UserFromContext(ctx),
+		Reason:     reason,
+		Snapshot:   data,
+	})
+	if err != nil {
+		return db.QuestionRevision{}, fmt.Errorf("erro ao registrar revisão: %w", err)
+	}
+	return rev, nil
+}
+
+// reopenReviewOnChange sends the question back to pending review when snap
+// differs from the latest recorded revision in what a reviewer signs off on.
+// Questions approved before history existed have no revision to compare
+// with, so any edit that may touch their content reopens them.
+func reopenReviewOnChange(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID, reason string, snap RevisionSnapshot) error {
+	latest, err := qtx.GetLatestQuestionRevision(ctx, questionID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if !editsReviewedContent(reason) {
+			return nil
+		}
+	case err != nil:
+		return fmt.Errorf("erro ao buscar revisão: %w", err)
+	default:
+		var previous RevisionSnapshot
+		if err := json.Unmarshal(latest.Snapshot, &previous); err != nil {
+			return fmt.Errorf("erro ao ler revisão %d: %w", latest.Revision, err)
+		}
+		if !reviewedContentChanged(previous, snap) {
+			return nil
+		}
+	}
+	// Só questões já revisadas voltam para pending
+	if err := qtx.ReopenQuestionReview(ctx, questionID); err != nil {
+		return fmt.Errorf("erro ao reabrir revisão editorial: %w", err)
+	}
+	return nil
+}
+
+// editsReviewedContent reports whether a revision recorded for reason comes
+// from an edit that may change the statement or the choices. Creations keep
+// the review status they were given, and bulk updates, taxonomy moves and
+// exam snapshots leave the content alone.
+func editsReviewedContent(reason string) bool {
+	switch reason {
+	case RevisionReasonUpdate, RevisionReasonCreateChoice, RevisionReasonUpdateChoice,
+		RevisionReasonDeleteChoice, RevisionReasonRestoreChoice, RevisionReasonReorder:
+		return true
+	}
+	return strings.HasPrefix(reason, RevisionReasonRestore+":")
+}
+
+// reviewedContentChanged reports whether the statement or the choices,
+// including their order and answer key, differ between two snapshots.
+func reviewedContentChanged(from, to RevisionSnapshot) bool {
+	if from.Statement != to.Statement || len(from.Choices) != len(to.Choices) {
+		return true
+	}
+	for i := range from.Choices {
+		if from.Choices[i] != to.Choices[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// currentRevision returns the revision matching the current content of a
+// question, recording a new one when the latest revision is missing or stale
+// (for instance, questions created before history existed).
+func currentRevision(ctx context.Context, qtx *db.Queries, questionID pgtype.UUID) (db.QuestionRevision, error) {
+	snap, err := snapshotQuestion(ctx, qtx, questionID)
+	if err != nil {
+		return db.QuestionRevision{}, err
+	}
+
+	latest, err := qtx.GetLatestQuestionRevision(ctx, questionID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return db.QuestionRevision{}, fmt.Errorf("erro ao buscar revisão: %w", err)
+	}
+	if err == nil {
+		var stored RevisionSnapshot
+		if err := json.Unmarshal(latest.Snapshot, &stored); err != nil {
+			return db.QuestionRevision{}, fmt.Errorf("erro ao ler revisão %d: %w", latest.Revision, err)
+		}
+		a, _ := json.Marshal(stored)
+		b, _ := json.Marshal(snap)
+		if bytes.Equal(a, b) {
+			return latest, nil
+		}
+	}
+
+	return recordRevision(ctx, qtx, questionID, RevisionReasonExamSnapshot)
+}
+
+func decodeRevision(row db.QuestionRevision) (Revision, error) {
+	rev := Revision{
+		ID:         row.ID,
+		QuestionID: row.QuestionID,
+		Revision:   row.Revision,
+		Author:     row.Author,
+		Reason:     row.Reason,
+		CreatedAt:  row.CreatedAt,
+	}
+	if err := json.Unmarshal(row.Snapshot, &rev.Snapshot); err != nil {
+		return Revision{}, fmt.Errorf("erro ao ler revisão %d: %w", row.Revision, err)
+	}
+	return rev, nil
+}
+
+// diffSnapshots compares two snapshots field by field. Choices are compared
+// by position, so "choices[2].is_correct" means the third choice.
+func diffSnapshots(from, to RevisionSnapshot) ([]FieldChange, error) {
+	a, err := flattenSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := flattenSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []FieldChange{}
+	for _, k := range sorted {
+		if !reflect.DeepEqual(a[k], b[k]) {
+			changes = append(changes, FieldChange{Field: k, From: a[k], To: b[k]})
+		}
+	}
+	return changes, nil
+}
+
+func flattenSnapshot(snap RevisionSnapshot) (map[string]any, error) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	flat := map[string]any{}
+	flattenInto(flat, "", tree)
+	return flat, nil
+}
+
+func flattenInto(flat map[string]any, prefix string, v any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenInto(flat, key, child)
+		}
+	case []any:
+		for i, child := range val {
+			flattenInto(flat, fmt.Sprintf("%s[%d]", prefix, i), child)
+		}
+	default:
+		flat[prefix] = val
+	}
+}
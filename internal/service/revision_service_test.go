@@ -0,0 +1,159 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	base := RevisionSnapshot{
+		Statement: "Qual é a capital?",
+		Year:      2020,
+		Level:     pgtype.Text{String: "Superior", Valid: true},
+		Choices: []RevisionChoice{
+			{ChoiceText: "Brasília", IsCorrect: true},
+			{ChoiceText: "Rio de Janeiro"},
+		},
+	}
+	edit := func(f func(*RevisionSnapshot)) RevisionSnapshot {
+		snap := base
+		snap.Choices = append([]RevisionChoice(nil), base.Choices...)
+		f(&snap)
+		return snap
+	}
+
+	tests := []struct {
+		name string
+		to   RevisionSnapshot
+		want []FieldChange
+	}{
+		{
+			name: "unchanged",
+			to:   base,
+			want: []FieldChange{},
+		},
+		{
+			name: "statement and year",
+			to: edit(func(s *RevisionSnapshot) {
+				s.Statement = "Qual é a capital do Brasil?"
+				s.Year = 2021
+			}),
+			want: []FieldChange{
+				{Field: "statement", From: "Qual é a capital?", To: "Qual é a capital do Brasil?"},
+				{Field: "year", From: 2020.0, To: 2021.0},
+			},
+		},
+		{
+			name: "cleared and set optional fields",
+			to: edit(func(s *RevisionSnapshot) {
+				s.Level = pgtype.Text{}
+				s.Position = pgtype.Text{String: "Analista", Valid: true}
+			}),
+			want: []FieldChange{
+				{Field: "level", From: "Superior", To: nil},
+				{Field: "position", From: nil, To: "Analista"},
+			},
+		},
+		{
+			name: "answer moved by position",
+			to: edit(func(s *RevisionSnapshot) {
+				s.Choices[0].IsCorrect = false
+				s.Choices[1].IsCorrect = true
+			}),
+			want: []FieldChange{
+				{Field: "choices[0].is_correct", From: true, To: false},
+				{Field: "choices[1].is_correct", From: false, To: true},
+			},
+		},
+		{
+			name: "choice added",
+			to: edit(func(s *RevisionSnapshot) {
+				s.Choices = append(s.Choices, RevisionChoice{ChoiceText: "Salvador"})
+			}),
+			want: []FieldChange{
+				{Field: "choices[2].choice_text", From: nil, To: "Salvador"},
+				{Field: "choices[2].is_correct", From: nil, To: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := diffSnapshots(base, tt.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffSnapshots() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewedContentChanged(t *testing.T) {
+	base := RevisionSnapshot{
+		Statement: "Qual é a capital?",
+		Year:      2020,
+		Choices: []RevisionChoice{
+			{ChoiceText: "Brasília", IsCorrect: true},
+			{ChoiceText: "Rio de Janeiro"},
+		},
+	}
+	edit := func(f func(*RevisionSnapshot)) RevisionSnapshot {
+		snap := base
+		snap.Choices = append([]RevisionChoice(nil), base.Choices...)
+		f(&snap)
+		return snap
+	}
+
+	tests := []struct {
+		name string
+		to   RevisionSnapshot
+		want bool
+	}{
+		{"unchanged", base, false},
+		{"metadata only", edit(func(s *RevisionSnapshot) { s.Year = 2021 }), false},
+		{"statement", edit(func(s *RevisionSnapshot) { s.Statement = "Qual é a capital do Brasil?" }), true},
+		{"answer key", edit(func(s *RevisionSnapshot) {
+			s.Choices[0].IsCorrect = false
+			s.Choices[1].IsCorrect = true
+		}), true},
+		{"choice order", edit(func(s *RevisionSnapshot) {
+			s.Choices[0], s.Choices[1] = s.Choices[1], s.Choices[0]
+		}), true},
+		{"choice removed", edit(func(s *RevisionSnapshot) { s.Choices = s.Choices[:1] }), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reviewedContentChanged(base, tt.to); got != tt.want {
+				t.Errorf("reviewedContentChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditsReviewedContent(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   bool
+	}{
+		{RevisionReasonUpdate, true},
+		{RevisionReasonCreateChoice, true},
+		{RevisionReasonUpdateChoice, true},
+		{RevisionReasonDeleteChoice, true},
+		{RevisionReasonRestoreChoice, true},
+		{RevisionReasonReorder, true},
+		{RevisionReasonRestore + ":3", true},
+		{RevisionReasonCreate, false},
+		{RevisionReasonImport, false},
+		{RevisionReasonBulkUpdate, false},
+		{RevisionReasonExamSnapshot, false},
+		{TaxonomyMergeTopic, false},
+	}
+	for _, tt := range tests {
+		if got := editsReviewedContent(tt.reason); got != tt.want {
+			t.Errorf("editsReviewedContent(%q) = %v, want %v", tt.reason, got, tt.want)
+		}
+	}
+}
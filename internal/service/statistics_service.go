@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// ErrInvalidAttempt is returned when the answers of an attempt do not match
+// the exam they are recorded for.
+var ErrInvalidAttempt = errors.New("respostas inválidas")
+
+// ChoiceStatistics is the distractor analysis of one choice: how many
+// candidates picked it and how their scores on the rest of the exam compare
+// with everyone else's. A good distractor has a negative discrimination.
+type ChoiceStatistics struct {
+	ChoiceID       pgtype.UUID `json:"choice_id"`
+	Letter         string      `json:"letter,omitempty"`
+	ChoiceText     string      `json:"choice_text,omitempty"`
+	IsCorrect      bool        `json:"is_correct"`
+	Count          int         `json:"count"`
+	Rate           float64     `json:"rate"`
+	Discrimination *float64    `json:"discrimination"`
+}
+
+// QuestionStatistics holds the psychometric statistics of a question
+// computed from the recorded answers. PCorrect is the share of correct
+// answers and Discrimination the point-biserial correlation between
+// answering correctly and the score on the rest of the exam. Statistics
+// that are undefined, such as the discrimination of a question everyone
+// got right, are null.
+type QuestionStatistics struct {
+	QuestionID     pgtype.UUID        `json:"question_id"`
+	Responses      int                `json:"responses"`
+	Blank          int                `json:"blank"`
+	PCorrect       *float64           `json:"p_correct"`
+	Discrimination *float64           `json:"discrimination"`
+	Choices        []ChoiceStatistics `json:"choices"`
+}
+
+// StatisticsService records candidates' answers to exams and computes
+// per-question statistics from them.
+type StatisticsService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewStatisticsService creates a new StatisticsService.
+func NewStatisticsService(pool *pgxpool.Pool) *StatisticsService {
+	return &StatisticsService{pool: pool, q: db.New(pool)}
+}
+
+// RecordAttempt stores a candidate's answers to an exam, one letter per
+// question in exam order, with an empty string for a blank answer. Letters
+// refer to the choices as printed, so shuffled exams are graded against
+// their own order. The statistics of the exam's questions are refreshed in
+// the same transaction.
+func (s *StatisticsService) RecordAttempt(ctx context.Context, examID pgtype.UUID, candidate string, answers []string) (db.ExamAttempt, error) {
+	candidate = strings.TrimSpace(candidate)
+	if candidate == "" {
+		return db.ExamAttempt{}, fmt.Errorf("%w: candidato é obrigatório", ErrInvalidAttempt)
+	}
+
+	var attempt db.ExamAttempt
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if _, err := qtx.GetExam(ctx, examID); err != nil {
+			return err
+		}
+		rows, err := qtx.ListExamQuestions(ctx, examID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar questões da prova: %w", err)
+		}
+		if len(answers) != len(rows) {
+			return fmt.Errorf("%w: a prova tem %d questões, recebido: %d respostas", ErrInvalidAttempt, len(rows), len(answers))
+		}
+
+		graded := make([]db.CreateAttemptAnswerParams, 0, len(rows))
+		questionIDs := make([]pgtype.UUID, 0, len(rows))
+		score := int32(0)
+		for i, row := range rows {
+			var snap RevisionSnapshot
+			if err := json.Unmarshal(row.Snapshot, &snap); err != nil {
+				return fmt.Errorf("erro ao ler revisão da questão %d: %v", row.Number, err)
+			}
+			answer := db.CreateAttemptAnswerParams{Number: row.Number, QuestionID: row.QuestionID}
+			letter := strings.ToUpper(strings.TrimSpace(answers[i]))
+			if letter != "" {
+				choices := orderedChoices(snap.Choices, row.ChoiceOrder)
+				idx := int(letter[0]) - 'A'
+				if len(letter) != 1 || idx < 0 || idx >= len(choices) {
+					return fmt.Errorf("%w: resposta %q da questão %d deve estar entre %s", ErrInvalidAttempt, answers[i], row.Number, choiceRange(len(choices)))
+				}
+				answer.ChoiceID = choices[idx].ID
+				answer.IsCorrect = letter == row.Answer
+			}
+			if answer.IsCorrect {
+				score++
+			}
+			graded = append(graded, answer)
+			questionIDs = append(questionIDs, row.QuestionID)
+		}
+
+		attempt, err = qtx.CreateExamAttempt(ctx, db.CreateExamAttemptParams{
+			ExamID:    examID,
+			Candidate: candidate,
+			Score:     score,
+			Total:     int32(len(rows)),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao registrar tentativa: %w", err)
+		}
+		for _, answer := range graded {
+			answer.AttemptID = attempt.ID
+			if err := qtx.CreateAttemptAnswer(ctx, answer); err != nil {
+				return fmt.Errorf("erro ao registrar resposta %d: %w", answer.Number, err)
+			}
+		}
+		return refreshStatistics(ctx, qtx, questionIDs)
+	})
+	if err != nil {
+		return db.ExamAttempt{}, err
+	}
+	return attempt, nil
+}
+
+func (s *StatisticsService) ListAttempts(ctx context.Context, examID pgtype.UUID) ([]db.ExamAttempt, error) {
+	return s.q.ListExamAttempts(ctx, examID)
+}
+
+// GetQuestionStatistics computes the statistics of a question, including
+// the distractor analysis of each of its current choices and of removed
+// choices that were still picked.
+func (s *StatisticsService) GetQuestionStatistics(ctx context.Context, questionID pgtype.UUID) (QuestionStatistics, error) {
+	if _, err := s.q.GetQuestion(ctx, questionID); err != nil {
+		return QuestionStatistics{}, err
+	}
+	responses, err := s.q.ListQuestionResponses(ctx, []pgtype.UUID{questionID})
+	if err != nil {
+		return QuestionStatistics{}, err
+	}
+	choices, err := s.q.ListChoicesByQuestion(ctx, questionID)
+	if err != nil {
+		return QuestionStatistics{}, err
+	}
+
+	stats := computeStatistics(questionID, responses)
+	rest := restScores(responses)
+	picked := make(map[pgtype.UUID]bool, len(choices))
+	analyse := func(c ChoiceStatistics) ChoiceStatistics {
+		chose := make([]bool, len(responses))
+		for i, r := range responses {
+			if r.ChoiceID == c.ChoiceID {
+				chose[i] = true
+				c.Count++
+			}
+		}
+		if len(responses) > 0 {
+			c.Rate = float64(c.Count) / float64(len(responses))
+		}
+		c.Discrimination = pointBiserial(chose, rest)
+		return c
+	}
+	for i, c := range choices {
+		picked[c.ID] = true
+		stats.Choices = append(stats.Choices, analyse(ChoiceStatistics{
+			ChoiceID:   c.ID,
+			Letter:     string(rune('A' + i)),
+			ChoiceText: c.ChoiceText,
+			IsCorrect:  c.IsCorrect.Bool,
+		}))
+	}
+	for _, r := range responses {
+		if r.ChoiceID.Valid && !picked[r.ChoiceID] {
+			picked[r.ChoiceID] = true
+			stats.Choices = append(stats.Choices, analyse(ChoiceStatistics{ChoiceID: r.ChoiceID, IsCorrect: r.IsCorrect}))
+		}
+	}
+	return stats, nil
+}
+
+// refreshStatistics recomputes and stores the statistics used by exam
+// generation for the given questions.
+func refreshStatistics(ctx context.Context, qtx *db.Queries, questionIDs []pgtype.UUID) error {
+	responses, err := qtx.ListQuestionResponses(ctx, questionIDs)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar respostas: %w", err)
+	}
+	byQuestion := make(map[pgtype.UUID][]db.ListQuestionResponsesRow)
+	for _, r := range responses {
+		byQuestion[r.QuestionID] = append(byQuestion[r.QuestionID], r)
+	}
+	for id, rows := range byQuestion {
+		stats := computeStatistics(id, rows)
+		params := db.UpsertQuestionStatisticsParams{
+			QuestionID: id,
+			Responses:  int32(stats.Responses),
+			PCorrect:   *stats.PCorrect,
+		}
+		if stats.Discrimination != nil {
+			params.Discrimination = pgtype.Float8{Float64: *stats.Discrimination, Valid: true}
+		}
+		if _, err := qtx.UpsertQuestionStatistics(ctx, params); err != nil {
+			return fmt.Errorf("erro ao salvar estatísticas: %w", err)
+		}
+	}
+	return nil
+}
+
+func computeStatistics(questionID pgtype.UUID, responses []db.ListQuestionResponsesRow) QuestionStatistics {
+	stats := QuestionStatistics{QuestionID: questionID, Responses: len(responses), Choices: []ChoiceStatistics{}}
+	if len(responses) == 0 {
+		return stats
+	}
+	correct := make([]bool, len(responses))
+	n := 0
+	for i, r := range responses {
+		correct[i] = r.IsCorrect
+		if r.IsCorrect {
+			n++
+		}
+		if !r.ChoiceID.Valid {
+			stats.Blank++
+		}
+	}
+	p := float64(n) / float64(len(responses))
+	stats.PCorrect = &p
+	stats.Discrimination = pointBiserial(correct, restScores(responses))
+	return stats
+}
+
+// restScores returns, for each response, the share of the other questions
+// of the attempt answered correctly. Leaving the question itself out keeps
+// it from inflating its own discrimination.
+func restScores(responses []db.ListQuestionResponsesRow) []float64 {
+	rest := make([]float64, len(responses))
+	for i, r := range responses {
+		if r.Total <= 1 {
+			continue
+		}
+		score := r.Score
+		if r.IsCorrect {
+			score--
+		}
+		rest[i] = float64(score) / float64(r.Total-1)
+	}
+	return rest
+}
+
+// pointBiserial correlates a dichotomous variable with a continuous one.
+// It returns nil when the correlation is undefined: no variation in either
+// variable.
+func pointBiserial(flags []bool, scores []float64) *float64 {
+	var n1, sum, sum1 float64
+	for i, f := range flags {
+		sum += scores[i]
+		if f {
+			n1++
+			sum1 += scores[i]
+		}
+	}
+	n := float64(len(flags))
+	if n1 == 0 || n1 == n {
+		return nil
+	}
+	mean := sum / n
+	var variance float64
+	for _, x := range scores {
+		variance += (x - mean) * (x - mean)
+	}
+	sd := math.Sqrt(variance / n)
+	if sd == 0 {
+		return nil
+	}
+	m1 := sum1 / n1
+	m0 := (sum - sum1) / (n - n1)
+	p := n1 / n
+	r := (m1 - m0) / sd * math.Sqrt(p*(1-p))
+	return &r
+}
@@ -5,17 +5,20 @@ import (
 	"log/slog"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
 )
 
 type SubjectService struct {
-	q db.Querier
+	pool *pgxpool.Pool
+	q    db.Querier
 }
 
-func NewSubjectService(q db.Querier) *SubjectService {
+func NewSubjectService(pool *pgxpool.Pool) *SubjectService {
 	return &SubjectService{
-		q: q,
+		pool: pool,
+		q:    db.New(pool),
 	}
 }
 
@@ -63,12 +66,24 @@ func (s *SubjectService) UpdateSubject(ctx context.Context, id string, name stri
 	return s.q.UpdateSubject(ctx, arg)
 }
 
+// DeleteSubject moves a subject and everything below it to the trash.
 func (s *SubjectService) DeleteSubject(ctx context.Context, id string) error {
 	uuid := pgtype.UUID{}
 	if err := uuid.Scan(id); err != nil {
 		return err
 	}
-	return s.q.DeleteSubject(ctx, uuid)
+	return inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		return softDelete(ctx, qtx, TrashKindSubject, uuid)
+	})
+}
+
+// DeleteSubjectImpact reports what DeleteSubject would move to the trash.
+func (s *SubjectService) DeleteSubjectImpact(ctx context.Context, id string) (DeleteImpact, error) {
+	uuid := pgtype.UUID{}
+	if err := uuid.Scan(id); err != nil {
+		return DeleteImpact{}, err
+	}
+	return deleteImpact(ctx, s.q, TrashKindSubject, uuid)
 }
 
 func (s *SubjectService) GetSubjectByName(ctx context.Context, name string) (db.Subject, error) {
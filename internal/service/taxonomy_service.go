@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Actions recorded in the taxonomy change log. They double as the revision
+// reason of every question moved by the change.
+const (
+	TaxonomyMergeTopic   = "merge_topic"
+	TaxonomyMergeSubject = "merge_subject"
+	TaxonomySplitTopic   = "split_topic"
+)
+
+// ErrInvalidTaxonomyChange is returned when a merge or split makes no sense,
+// such as merging a topic into itself or splitting off questions that do
+// not belong to the topic.
+var ErrInvalidTaxonomyChange = errors.New("alteração de taxonomia inválida")
+
+// TaxonomyService merges and splits topics and subjects. Every operation
+// runs in one transaction, records a revision for each question that
+// changes topic, and is logged with its author.
+type TaxonomyService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewTaxonomyService creates a new TaxonomyService.
+func NewTaxonomyService(pool *pgxpool.Pool) *TaxonomyService {
+	return &TaxonomyService{pool: pool, q: db.New(pool)}
+}
+
+// MergeTopics moves every question of the source topic, including those in
+// the trash, to the target topic and then moves the emptied source topic to
+// the trash.
+func (s *TaxonomyService) MergeTopics(ctx context.Context, sourceID, targetID pgtype.UUID) (db.TaxonomyChange, error) {
+	var change db.TaxonomyChange
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if sourceID == targetID {
+			return fmt.Errorf("%w: um tópico não pode ser fundido com ele mesmo", ErrInvalidTaxonomyChange)
+		}
+		if _, err := qtx.GetTopic(ctx, targetID); err != nil {
+			return err
+		}
+		moved, err := mergeTopic(ctx, qtx, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		change, err = recordTaxonomyChange(ctx, qtx, TaxonomyMergeTopic, sourceID, targetID, moved)
+		return err
+	})
+	if err != nil {
+		return db.TaxonomyChange{}, err
+	}
+	return change, nil
+}
+
+// MergeSubjects moves the topics of the source subject to the target
+// subject and then moves the emptied source subject to the trash. A source
+// topic whose name matches a topic of the target subject, ignoring case, is
+// merged into it instead of being moved. Trashed source topics are matched
+// too: their questions go to the target topic and the emptied topic is
+// purged, since it could never be restored next to an active topic of the
+// same name.
+func (s *TaxonomyService) MergeSubjects(ctx context.Context, sourceID, targetID pgtype.UUID) (db.TaxonomyChange, error) {
+	var change db.TaxonomyChange
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if sourceID == targetID {
+			return fmt.Errorf("%w: uma disciplina não pode ser fundida com ela mesma", ErrInvalidTaxonomyChange)
+		}
+		if _, err := qtx.GetSubject(ctx, sourceID); err != nil {
+			return err
+		}
+		if _, err := qtx.GetSubject(ctx, targetID); err != nil {
+			return err
+		}
+
+		targetTopics, err := qtx.ListTopicsBySubject(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar tópicos: %w", err)
+		}
+		byName := make(map[string]pgtype.UUID, len(targetTopics))
+		for _, t := range targetTopics {
+			byName[topicKey(t.Name)] = t.ID
+		}
+		sourceTopics, err := qtx.ListTopicsBySubject(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar tópicos: %w", err)
+		}
+
+		var moved []pgtype.UUID
+		for _, t := range sourceTopics {
+			match, ok := byName[topicKey(t.Name)]
+			if !ok {
+				continue
+			}
+			ids, err := mergeTopic(ctx, qtx, t.ID, match)
+			if err != nil {
+				return err
+			}
+			moved = append(moved, ids...)
+		}
+
+		trashedTopics, err := qtx.ListTrashedTopicsBySubject(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("erro ao buscar tópicos excluídos: %w", err)
+		}
+		for _, t := range trashedTopics {
+			match, ok := byName[topicKey(t.Name)]
+			if !ok {
+				continue
+			}
+			questions, err := qtx.MoveQuestionsToTopic(ctx, db.MoveQuestionsToTopicParams{TargetTopicID: match, SourceTopicID: t.ID})
+			if err != nil {
+				return fmt.Errorf("erro ao mover questões: %w", err)
+			}
+			ids, err := reviseMovedQuestions(ctx, qtx, questions, TaxonomyMergeTopic)
+			if err != nil {
+				return err
+			}
+			moved = append(moved, ids...)
+			if _, err := qtx.PurgeTopic(ctx, t.ID); err != nil {
+				return fmt.Errorf("erro ao remover tópico excluído: %w", err)
+			}
+		}
+
+		// The remaining topics, trashed ones included, keep their questions
+		// and only change subject.
+		if err := qtx.MoveTopicsToSubject(ctx, db.MoveTopicsToSubjectParams{TargetSubjectID: targetID, SourceSubjectID: sourceID}); err != nil {
+			return fmt.Errorf("erro ao mover tópicos: %w", err)
+		}
+		if err := softDelete(ctx, qtx, TrashKindSubject, sourceID); err != nil {
+			return err
+		}
+		change, err = recordTaxonomyChange(ctx, qtx, TaxonomyMergeSubject, sourceID, targetID, moved)
+		return err
+	})
+	if err != nil {
+		return db.TaxonomyChange{}, err
+	}
+	return change, nil
+}
+
+// SplitTopic creates a topic named name and moves the given active
+// questions of the source topic to it. The new topic belongs to subjectID,
+// or to the subject of the source topic when subjectID is not set.
+func (s *TaxonomyService) SplitTopic(ctx context.Context, sourceID pgtype.UUID, name string, subjectID pgtype.UUID, questionIDs []pgtype.UUID) (db.Topic, db.TaxonomyChange, error) {
+	var (
+		topic  db.Topic
+		change db.TaxonomyChange
+	)
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: informe o nome do novo tópico", ErrInvalidTaxonomyChange)
+		}
+		if len(questionIDs) == 0 {
+			return fmt.Errorf("%w: informe as questões do novo tópico", ErrInvalidTaxonomyChange)
+		}
+		source, err := qtx.GetTopic(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if !subjectID.Valid {
+			subjectID = source.SubjectID
+		}
+		if _, err := qtx.GetSubject(ctx, subjectID); err != nil {
+			return err
+		}
+
+		topic, err = qtx.CreateTopic(ctx, db.CreateTopicParams{SubjectID: subjectID, Name: name})
+		if err != nil {
+			return fmt.Errorf("erro ao criar tópico: %w", err)
+		}
+		questions, err := qtx.MoveQuestionsByIDsToTopic(ctx, db.MoveQuestionsByIDsToTopicParams{
+			TargetTopicID: topic.ID,
+			SourceTopicID: sourceID,
+			Ids:           questionIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao mover questões: %w", err)
+		}
+		if len(questions) != len(questionIDs) {
+			return fmt.Errorf("%w: %d de %d questões não pertencem ao tópico, não existem ou estão repetidas",
+				ErrInvalidTaxonomyChange, len(questionIDs)-len(questions), len(questionIDs))
+		}
+		moved, err := reviseMovedQuestions(ctx, qtx, questions, TaxonomySplitTopic)
+		if err != nil {
+			return err
+		}
+		change, err = recordTaxonomyChange(ctx, qtx, TaxonomySplitTopic, sourceID, topic.ID, moved)
+		return err
+	})
+	if err != nil {
+		return db.Topic{}, db.TaxonomyChange{}, err
+	}
+	return topic, change, nil
+}
+
+// ListTaxonomyChanges returns the log of merges and splits, newest first.
+func (s *TaxonomyService) ListTaxonomyChanges(ctx context.Context) ([]db.TaxonomyChange, error) {
+	return s.q.ListTaxonomyChanges(ctx)
+}
+
+// mergeTopic moves all questions of the source topic to the target topic,
+// trashes the source topic and returns the IDs of the moved questions.
+func mergeTopic(ctx context.Context, qtx *db.Queries, sourceID, targetID pgtype.UUID) ([]pgtype.UUID, error) {
+	if _, err := qtx.GetTopic(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	questions, err := qtx.MoveQuestionsToTopic(ctx, db.MoveQuestionsToTopicParams{TargetTopicID: targetID, SourceTopicID: sourceID})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao mover questões: %w", err)
+	}
+	moved, err := reviseMovedQuestions(ctx, qtx, questions, TaxonomyMergeTopic)
+	if err != nil {
+		return nil, err
+	}
+	if err := softDelete(ctx, qtx, TrashKindTopic, sourceID); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+// reviseMovedQuestions records a revision for each moved question that is
+// not in the trash and returns the IDs of all of them.
+func reviseMovedQuestions(ctx context.Context, qtx *db.Queries, questions []db.Question, reason string) ([]pgtype.UUID, error) {
+	ids := make([]pgtype.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+		if q.DeletedAt.Valid {
+			continue
+		}
+		if _, err := recordRevision(ctx, qtx, q.ID, reason); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+func recordTaxonomyChange(ctx context.Context, qtx *db.Queries, action string, sourceID, targetID pgtype.UUID, questionIDs []pgtype.UUID) (db.TaxonomyChange, error) {
+	if questionIDs == nil {
+		questionIDs = []pgtype.UUID{}
+	}
+	change, err := qtx.CreateTaxonomyChange(ctx, db.CreateTaxonomyChangeParams{
+		Author:      UserFromContext(ctx),
+		Action:      action,
+		SourceID:    sourceID,
+		TargetID:    targetID,
+		QuestionIds: questionIDs,
+	})
+	if err != nil {
+		return db.TaxonomyChange{}, fmt.Errorf("erro ao registrar alteração de taxonomia: %w", err)
+	}
+	return change, nil
+}
+
+// topicKey compares topic names ignoring case and surrounding spaces.
+func topicKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
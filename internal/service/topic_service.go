@@ -4,17 +4,20 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
 )
 
 type TopicService struct {
-	q db.Querier
+	pool *pgxpool.Pool
+	q    db.Querier
 }
 
-func NewTopicService(q db.Querier) *TopicService {
+func NewTopicService(pool *pgxpool.Pool) *TopicService {
 	return &TopicService{
-		q: q,
+		pool: pool,
+		q:    db.New(pool),
 	}
 }
 
@@ -46,8 +49,16 @@ func (s *TopicService) UpdateTopic(ctx context.Context, id pgtype.UUID, name str
 	return s.q.UpdateTopic(ctx, arg)
 }
 
+// DeleteTopic moves a topic, its questions and their choices to the trash.
 func (s *TopicService) DeleteTopic(ctx context.Context, id pgtype.UUID) error {
-	return s.q.DeleteTopic(ctx, id)
+	return inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		return softDelete(ctx, qtx, TrashKindTopic, id)
+	})
+}
+
+// DeleteTopicImpact reports what DeleteTopic would move to the trash.
+func (s *TopicService) DeleteTopicImpact(ctx context.Context, id pgtype.UUID) (DeleteImpact, error) {
+	return deleteImpact(ctx, s.q, TrashKindTopic, id)
 }
 
 func (s *TopicService) ListTopicsBySubject(ctx context.Context, subjectID pgtype.UUID) ([]db.Topic, error) {
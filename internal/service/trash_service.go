@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// Kinds of catalog entries that can be moved to the trash.
+const (
+	TrashKindSubject  = "subjects"
+	TrashKindTopic    = "topics"
+	TrashKindQuestion = "questions"
+	TrashKindChoice   = "choices"
+)
+
+var (
+	// ErrInvalidTrashKind is returned for an unknown kind of trash entry.
+	ErrInvalidTrashKind = errors.New("tipo de item inválido")
+	// ErrParentInTrash is returned when restoring an entry whose parent is
+	// still in the trash.
+	ErrParentInTrash = errors.New("item pai está na lixeira")
+	// ErrNameInUse is returned when restoring a subject or topic whose name
+	// was taken by another entry while it was in the trash.
+	ErrNameInUse = errors.New("já existe um item ativo com o mesmo nome")
+	// ErrActiveChildren is returned when purging a subject or topic that
+	// still holds active topics or questions.
+	ErrActiveChildren = errors.New("item ainda tem itens ativos")
+)
+
+// DeleteImpact counts the rows a delete moves to the trash, the entry
+// itself included.
+type DeleteImpact struct {
+	Subjects  int64 `json:"subjects"`
+	Topics    int64 `json:"topics"`
+	Questions int64 `json:"questions"`
+	Choices   int64 `json:"choices"`
+}
+
+// Trash lists the entries deleted on their own. Children deleted together
+// with their parent are omitted, since restoring the parent brings them back.
+type Trash struct {
+	Subjects  []db.Subject  `json:"subjects"`
+	Topics    []db.Topic    `json:"topics"`
+	Questions []db.Question `json:"questions"`
+	Choices   []db.Choice   `json:"choices"`
+}
+
+// TrashService lists, restores and purges soft-deleted catalog entries.
+//
+// A delete stamps the entry and all of its active descendants with the same
+// deleted_at, so a restore brings back exactly what that delete removed and
+// leaves alone children that had been deleted earlier.
+type TrashService struct {
+	pool *pgxpool.Pool
+	q    db.Querier
+}
+
+// NewTrashService creates a new TrashService.
+func NewTrashService(pool *pgxpool.Pool) *TrashService {
+	return &TrashService{pool: pool, q: db.New(pool)}
+}
+
+// ListTrash returns the entries currently in the trash, newest first.
+func (s *TrashService) ListTrash(ctx context.Context) (Trash, error) {
+	var (
+		trash Trash
+		err   error
+	)
+	if trash.Subjects, err = s.q.ListTrashedSubjects(ctx); err != nil {
+		return Trash{}, fmt.Errorf("erro ao listar matérias excluídas: %w", err)
+	}
+	if trash.Topics, err = s.q.ListTrashedTopics(ctx); err != nil {
+		return Trash{}, fmt.Errorf("erro ao listar tópicos excluídos: %w", err)
+	}
+	if trash.Questions, err = s.q.ListTrashedQuestions(ctx); err != nil {
+		return Trash{}, fmt.Errorf("erro ao listar questões excluídas: %w", err)
+	}
+	if trash.Choices, err = s.q.ListTrashedChoices(ctx); err != nil {
+		return Trash{}, fmt.Errorf("erro ao listar alternativas excluídas: %w", err)
+	}
+	return trash, nil
+}
+
+// Restore brings an entry back from the trash together with the descendants
+// deleted with it. The parent must not be in the trash, and a subject or
+// topic cannot come back while an active one uses its name. A restored
+// choice goes after the choices the question has now.
+func (s *TrashService) Restore(ctx context.Context, kind string, id pgtype.UUID) error {
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		switch kind {
+		case TrashKindSubject:
+			subject, err := qtx.GetTrashedSubject(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := requireNameFree(qtx.GetSubjectByName(ctx, subject.Name)); err != nil {
+				return err
+			}
+			if err := qtx.RestoreChoicesBySubject(ctx, db.RestoreChoicesBySubjectParams{SubjectID: id, DeletedAt: subject.DeletedAt}); err != nil {
+				return err
+			}
+			if err := qtx.RestoreQuestionsBySubject(ctx, db.RestoreQuestionsBySubjectParams{SubjectID: id, DeletedAt: subject.DeletedAt}); err != nil {
+				return err
+			}
+			if err := qtx.RestoreTopicsBySubject(ctx, db.RestoreTopicsBySubjectParams{SubjectID: id, DeletedAt: subject.DeletedAt}); err != nil {
+				return err
+			}
+			return qtx.RestoreSubject(ctx, id)
+
+		case TrashKindTopic:
+			topic, err := qtx.GetTrashedTopic(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := requireActive(qtx.GetSubject(ctx, topic.SubjectID)); err != nil {
+				return err
+			}
+			if err := requireNameFree(qtx.GetTopicByName(ctx, db.GetTopicByNameParams{SubjectID: topic.SubjectID, Name: topic.Name})); err != nil {
+				return err
+			}
+			if err := qtx.RestoreChoicesByTopic(ctx, db.RestoreChoicesByTopicParams{TopicID: id, DeletedAt: topic.DeletedAt}); err != nil {
+				return err
+			}
+			if err := qtx.RestoreQuestionsByTopic(ctx, db.RestoreQuestionsByTopicParams{TopicID: id, DeletedAt: topic.DeletedAt}); err != nil {
+				return err
+			}
+			return qtx.RestoreTopic(ctx, id)
+
+		case TrashKindQuestion:
+			question, err := qtx.GetTrashedQuestion(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := requireActive(qtx.GetTopic(ctx, question.TopicID)); err != nil {
+				return err
+			}
+			if err := qtx.RestoreChoicesByQuestion(ctx, db.RestoreChoicesByQuestionParams{QuestionID: id, DeletedAt: question.DeletedAt}); err != nil {
+				return err
+			}
+			return qtx.RestoreQuestion(ctx, id)
+
+		case TrashKindChoice:
+			choice, err := qtx.GetTrashedChoice(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := requireActive(qtx.GetQuestion(ctx, choice.QuestionID)); err != nil {
+				return err
+			}
+			if err := qtx.RestoreChoice(ctx, id); err != nil {
+				return err
+			}
+			if err := checkAnswerKey(ctx, qtx, choice.QuestionID); err != nil {
+				return err
+			}
+			_, err = recordRevision(ctx, qtx, choice.QuestionID, RevisionReasonRestoreChoice)
+			return err
+		}
+		return fmt.Errorf("%w: %q", ErrInvalidTrashKind, kind)
+	})
+	// O nome pode ter sido ocupado por outra transação depois da verificação
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: renomeie ou exclua o item ativo primeiro", ErrNameInUse)
+	}
+	return err
+}
+
+// Purge permanently removes an entry that is in the trash, along with
+// everything below it. A subject or topic that still holds active topics or
+// questions, such as questions moved into it after it was deleted, is left
+// alone with ErrActiveChildren.
+func (s *TrashService) Purge(ctx context.Context, kind string, id pgtype.UUID) error {
+	return inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		var (
+			n   int64
+			err error
+		)
+		switch kind {
+		case TrashKindSubject:
+			if _, err = qtx.GetTrashedSubject(ctx, id); err != nil {
+				return err
+			}
+			if err = requireNoActiveChildren(ctx, qtx, TrashKindSubject, id); err != nil {
+				return err
+			}
+			if err = qtx.PurgeQuestionsBySubject(ctx, id); err != nil {
+				return err
+			}
+			n, err = qtx.PurgeSubject(ctx, id)
+		case TrashKindTopic:
+			if _, err = qtx.GetTrashedTopic(ctx, id); err != nil {
+				return err
+			}
+			if err = requireNoActiveChildren(ctx, qtx, TrashKindTopic, id); err != nil {
+				return err
+			}
+			if err = qtx.PurgeQuestionsByTopic(ctx, id); err != nil {
+				return err
+			}
+			n, err = qtx.PurgeTopic(ctx, id)
+		case TrashKindQuestion:
+			n, err = qtx.PurgeQuestion(ctx, id)
+		case TrashKindChoice:
+			n, err = qtx.PurgeChoice(ctx, id)
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidTrashKind, kind)
+		}
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+}
+
+// PurgeBefore permanently removes every entry deleted before the given time
+// and reports how many rows of each kind were removed. Topics and subjects
+// that still hold questions or topics not purged in the same run, such as
+// questions moved into them after they were deleted, are kept.
+func (s *TrashService) PurgeBefore(ctx context.Context, before time.Time) (DeleteImpact, error) {
+	cutoff := pgtype.Timestamptz{Time: before, Valid: true}
+
+	var purged DeleteImpact
+	err := inTx(ctx, s.pool, func(qtx *db.Queries) error {
+		var err error
+		if purged.Choices, err = qtx.PurgeTrashedChoices(ctx, cutoff); err != nil {
+			return fmt.Errorf("erro ao remover alternativas: %w", err)
+		}
+		if purged.Questions, err = qtx.PurgeTrashedQuestions(ctx, cutoff); err != nil {
+			return fmt.Errorf("erro ao remover questões: %w", err)
+		}
+		if purged.Topics, err = qtx.PurgeTrashedTopics(ctx, cutoff); err != nil {
+			return fmt.Errorf("erro ao remover tópicos: %w", err)
+		}
+		if purged.Subjects, err = qtx.PurgeTrashedSubjects(ctx, cutoff); err != nil {
+			return fmt.Errorf("erro ao remover matérias: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return DeleteImpact{}, err
+	}
+	return purged, nil
+}
+
+// requireActive turns a missing parent into ErrParentInTrash.
+func requireActive[T any](_ T, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: restaure o item pai primeiro", ErrParentInTrash)
+	}
+	return err
+}
+
+// requireNoActiveChildren returns ErrActiveChildren when a trashed subject
+// or topic still holds active topics or questions.
+func requireNoActiveChildren(ctx context.Context, q db.Querier, kind string, id pgtype.UUID) error {
+	var topics, questions int64
+	switch kind {
+	case TrashKindSubject:
+		deps, err := q.CountSubjectDependents(ctx, id)
+		if err != nil {
+			return err
+		}
+		topics, questions = deps.Topics, deps.Questions
+	case TrashKindTopic:
+		deps, err := q.CountTopicDependents(ctx, id)
+		if err != nil {
+			return err
+		}
+		questions = deps.Questions
+	}
+	if topics > 0 || questions > 0 {
+		return fmt.Errorf("%w: restaure o item ou mova seus %d tópicos e %d questões ativos primeiro", ErrActiveChildren, topics, questions)
+	}
+	return nil
+}
+
+// requireNameFree turns an active entry found under the same name into
+// ErrNameInUse.
+func requireNameFree[T any](_ T, err error) error {
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: renomeie ou exclua o item ativo primeiro", ErrNameInUse)
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err comes from a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// deleteImpact counts what deleting the given entry would move to the trash.
+func deleteImpact(ctx context.Context, q db.Querier, kind string, id pgtype.UUID) (DeleteImpact, error) {
+	switch kind {
+	case TrashKindSubject:
+		if _, err := q.GetSubject(ctx, id); err != nil {
+			return DeleteImpact{}, err
+		}
+		deps, err := q.CountSubjectDependents(ctx, id)
+		if err != nil {
+			return DeleteImpact{}, err
+		}
+		return DeleteImpact{Subjects: 1, Topics: deps.Topics, Questions: deps.Questions, Choices: deps.Choices}, nil
+	case TrashKindTopic:
+		if _, err := q.GetTopic(ctx, id); err != nil {
+			return DeleteImpact{}, err
+		}
+		deps, err := q.CountTopicDependents(ctx, id)
+		if err != nil {
+			return DeleteImpact{}, err
+		}
+		return DeleteImpact{Topics: 1, Questions: deps.Questions, Choices: deps.Choices}, nil
+	case TrashKindQuestion:
+		if _, err := q.GetQuestion(ctx, id); err != nil {
+			return DeleteImpact{}, err
+		}
+		choices, err := q.CountQuestionDependents(ctx, id)
+		if err != nil {
+			return DeleteImpact{}, err
+		}
+		return DeleteImpact{Questions: 1, Choices: choices}, nil
+	case TrashKindChoice:
+		if _, err := q.GetChoice(ctx, id); err != nil {
+			return DeleteImpact{}, err
+		}
+		return DeleteImpact{Choices: 1}, nil
+	}
+	return DeleteImpact{}, fmt.Errorf("%w: %q", ErrInvalidTrashKind, kind)
+}
+
+// softDelete moves an entry and its active descendants to the trash, all
+// stamped with the same deleted_at. It returns pgx.ErrNoRows when the entry
+// does not exist or is already in the trash.
+func softDelete(ctx context.Context, qtx *db.Queries, kind string, id pgtype.UUID) error {
+	now := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	var (
+		n   int64
+		err error
+	)
+	switch kind {
+	case TrashKindSubject:
+		if n, err = qtx.SoftDeleteSubject(ctx, db.SoftDeleteSubjectParams{ID: id, DeletedAt: now}); err != nil || n == 0 {
+			break
+		}
+		if err = qtx.SoftDeleteTopicsBySubject(ctx, db.SoftDeleteTopicsBySubjectParams{SubjectID: id, DeletedAt: now}); err != nil {
+			break
+		}
+		if err = qtx.SoftDeleteQuestionsBySubject(ctx, db.SoftDeleteQuestionsBySubjectParams{SubjectID: id, DeletedAt: now}); err != nil {
+			break
+		}
+		err = qtx.SoftDeleteChoicesBySubject(ctx, db.SoftDeleteChoicesBySubjectParams{SubjectID: id, DeletedAt: now})
+	case TrashKindTopic:
+		if n, err = qtx.SoftDeleteTopic(ctx, db.SoftDeleteTopicParams{ID: id, DeletedAt: now}); err != nil || n == 0 {
+			break
+		}
+		if err = qtx.SoftDeleteQuestionsByTopic(ctx, db.SoftDeleteQuestionsByTopicParams{TopicID: id, DeletedAt: now}); err != nil {
+			break
+		}
+		err = qtx.SoftDeleteChoicesByTopic(ctx, db.SoftDeleteChoicesByTopicParams{TopicID: id, DeletedAt: now})
+	case TrashKindQuestion:
+		if n, err = qtx.SoftDeleteQuestion(ctx, db.SoftDeleteQuestionParams{ID: id, DeletedAt: now}); err != nil || n == 0 {
+			break
+		}
+		err = qtx.SoftDeleteChoicesByQuestion(ctx, db.SoftDeleteChoicesByQuestionParams{QuestionID: id, DeletedAt: now})
+	case TrashKindChoice:
+		n, err = qtx.SoftDeleteChoice(ctx, db.SoftDeleteChoiceParams{ID: id, DeletedAt: now})
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidTrashKind, kind)
+	}
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// dependentsQuerier answers the dependent counts used before a purge.
+type dependentsQuerier struct {
+	db.Querier
+	subject db.CountSubjectDependentsRow
+	topic   db.CountTopicDependentsRow
+}
+
+func (q dependentsQuerier) CountSubjectDependents(context.Context, pgtype.UUID) (db.CountSubjectDependentsRow, error) {
+	return q.subject, nil
+}
+
+func (q dependentsQuerier) CountTopicDependents(context.Context, pgtype.UUID) (db.CountTopicDependentsRow, error) {
+	return q.topic, nil
+}
+
+func TestRequireNoActiveChildren(t *testing.T) {
+	tests := []struct {
+		name string
+		kind string
+		q    dependentsQuerier
+		want error
+	}{
+		{"empty subject", TrashKindSubject, dependentsQuerier{}, nil},
+		{"subject with active topic", TrashKindSubject, dependentsQuerier{subject: db.CountSubjectDependentsRow{Topics: 1}}, ErrActiveChildren},
+		{"subject with active question", TrashKindSubject, dependentsQuerier{subject: db.CountSubjectDependentsRow{Questions: 2}}, ErrActiveChildren},
+		{"empty topic", TrashKindTopic, dependentsQuerier{}, nil},
+		{"topic with active question", TrashKindTopic, dependentsQuerier{topic: db.CountTopicDependentsRow{Questions: 1}}, ErrActiveChildren},
+		{"topic with only active choices", TrashKindTopic, dependentsQuerier{topic: db.CountTopicDependentsRow{Choices: 4}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireNoActiveChildren(context.Background(), tt.q, tt.kind, pgtype.UUID{})
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("requireNoActiveChildren() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// topicQuerier answers GetTopic with err, as for a trashed topic when it
+// is pgx.ErrNoRows.
+type topicQuerier struct {
+	db.Querier
+	err error
+}
+
+func (q topicQuerier) GetTopic(context.Context, pgtype.UUID) (db.Topic, error) {
+	return db.Topic{}, q.err
+}
+
+func TestRequireActiveTopic(t *testing.T) {
+	ctx := context.Background()
+	if err := requireActiveTopic(ctx, topicQuerier{}, pgtype.UUID{}); err != nil {
+		t.Errorf("requireActiveTopic(active) = %v, want nil", err)
+	}
+	if err := requireActiveTopic(ctx, topicQuerier{err: pgx.ErrNoRows}, pgtype.UUID{}); !errors.Is(err, ErrUnknownTopic) {
+		t.Errorf("requireActiveTopic(trashed) = %v, want ErrUnknownTopic", err)
+	}
+}
+
+func TestRequireActive(t *testing.T) {
+	if err := requireActive(db.Subject{}, nil); err != nil {
+		t.Errorf("requireActive(found) = %v, want nil", err)
+	}
+	if err := requireActive(db.Subject{}, pgx.ErrNoRows); !errors.Is(err, ErrParentInTrash) {
+		t.Errorf("requireActive(missing) = %v, want ErrParentInTrash", err)
+	}
+}
+
+func TestRequireNameFree(t *testing.T) {
+	failure := errors.New("conexão perdida")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"name taken", nil, ErrNameInUse},
+		{"name free", pgx.ErrNoRows, nil},
+		{"query failed", failure, failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := requireNameFree(db.Topic{}, tt.err)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("requireNameFree() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unique violation", &pgconn.PgError{Code: "23505"}, true},
+		{"wrapped unique violation", fmt.Errorf("erro ao restaurar: %w", &pgconn.PgError{Code: "23505"}), true},
+		{"foreign key violation", &pgconn.PgError{Code: "23503"}, false},
+		{"duplicate key text only", errors.New("duplicate key value violates unique constraint"), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUniqueViolation(tt.err); got != tt.want {
+				t.Errorf("isUniqueViolation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/JeanGrijp/AutoBanca/internal/adapter/database/sqlc/db"
+)
+
+// beginner starts transactions: a pool, or a transaction, in which case the
+// new transaction is a savepoint.
+type beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// inTx runs fn inside a transaction, committing when it returns nil and
+// rolling back otherwise.
+func inTx(ctx context.Context, pool beginner, fn func(qtx *db.Queries) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx) // Will be no-op if committed
+
+	if err := fn(db.New(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying the user performing the request.
+// Services read it back to attribute revisions and audit entries.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user stored by WithUser, or "system" when
+// the call did not originate from a request.
+func UserFromContext(ctx context.Context) string {
+	if user, ok := ctx.Value(userKey{}).(string); ok && user != "" {
+		return user
+	}
+	return "system"
+}